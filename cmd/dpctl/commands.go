@@ -0,0 +1,335 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/signaling"
+)
+
+// messageFlags binds the flags of prepare and start messages. Flags that are set override the fields of the message
+// read from the file given with -f.
+type messageFlags struct {
+	file             string
+	messageID        string
+	processID        string
+	agreementID      string
+	datasetID        string
+	participantID    string
+	counterPartyID   string
+	dataspaceContext string
+	callbackAddress  string
+	destinationType  string
+	flowType         string
+	address          string
+}
+
+func bindMessageFlags(fs *flag.FlagSet) *messageFlags {
+	f := &messageFlags{}
+	fs.StringVar(&f.file, "f", "", "JSON file containing the message, - for stdin")
+	fs.StringVar(&f.messageID, "message-id", "", "message ID (generated if empty)")
+	fs.StringVar(&f.processID, "process-id", "", "process ID of the data flow")
+	fs.StringVar(&f.agreementID, "agreement-id", "", "agreement ID")
+	fs.StringVar(&f.datasetID, "dataset-id", "", "dataset ID")
+	fs.StringVar(&f.participantID, "participant-id", "", "participant ID of the data plane owner")
+	fs.StringVar(&f.counterPartyID, "counter-party-id", "", "participant ID of the counter-party")
+	fs.StringVar(&f.dataspaceContext, "dataspace-context", "", "dataspace context")
+	fs.StringVar(&f.callbackAddress, "callback", "", "control plane callback address")
+	fs.StringVar(&f.destinationType, "destination-type", "", "transfer destination type")
+	fs.StringVar(&f.flowType, "flow-type", "", "transfer flow type: pull or push")
+	fs.StringVar(&f.address, "address", "", "JSON file containing the data address")
+	return f
+}
+
+// message creates the base message from the file and the flags set on the command line
+func (f *messageFlags) message(fs *flag.FlagSet) (dsdk.DataFlowBaseMessage, error) {
+	var message dsdk.DataFlowBaseMessage
+	if f.file != "" {
+		if err := readJSON(f.file, &message); err != nil {
+			return message, err
+		}
+	}
+
+	var err error
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "message-id":
+			message.MessageID = f.messageID
+		case "process-id":
+			message.ProcessID = f.processID
+		case "agreement-id":
+			message.AgreementID = f.agreementID
+		case "dataset-id":
+			message.DatasetID = f.datasetID
+		case "participant-id":
+			message.ParticipantID = f.participantID
+		case "counter-party-id":
+			message.CounterPartyID = f.counterPartyID
+		case "dataspace-context":
+			message.DataspaceContext = f.dataspaceContext
+		case "destination-type":
+			message.TransferType.DestinationType = f.destinationType
+		case "flow-type":
+			message.TransferType.FlowType = dsdk.FlowType(f.flowType)
+		case "callback":
+			callbackURL, parseErr := url.Parse(f.callbackAddress)
+			if parseErr != nil {
+				err = fmt.Errorf("invalid callback address: %w", parseErr)
+				return
+			}
+			message.CallbackAddress = dsdk.CallbackURL(*callbackURL)
+		case "address":
+			message.DataAddress = &dsdk.DataAddress{}
+			err = readJSON(f.address, message.DataAddress)
+		}
+	})
+	if message.MessageID == "" {
+		message.MessageID = uuid.NewString()
+	}
+	return message, err
+}
+
+func runPrepare(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	flags := bindMessageFlags(fs)
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	message, err := flags.message(fs)
+	if err != nil {
+		return err
+	}
+	response, err := env.client.Prepare(ctx, dsdk.DataFlowPrepareMessage{DataFlowBaseMessage: message})
+	if err != nil {
+		return err
+	}
+	return printOutput(env, response, printResponse)
+}
+
+func runStart(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	flags := bindMessageFlags(fs)
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	message, err := flags.message(fs)
+	if err != nil {
+		return err
+	}
+	response, err := env.client.Start(ctx, dsdk.DataFlowStartMessage{DataFlowBaseMessage: message})
+	if err != nil {
+		return err
+	}
+	return printOutput(env, response, printResponse)
+}
+
+func runStarted(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	file := fs.String("f", "", "JSON file containing the message, - for stdin")
+	messageID := fs.String("message-id", "", "message ID (generated if empty)")
+	address := fs.String("address", "", "JSON file containing the data address")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var message dsdk.DataFlowStartedNotificationMessage
+	if *file != "" {
+		if err := readJSON(*file, &message); err != nil {
+			return err
+		}
+	}
+	if *address != "" {
+		message.DataAddress = &dsdk.DataAddress{}
+		if err := readJSON(*address, message.DataAddress); err != nil {
+			return err
+		}
+	}
+	message.MessageID = firstNonEmpty(*messageID, message.MessageID, uuid.NewString())
+
+	response, err := env.client.Started(ctx, positional[0], message)
+	if err != nil {
+		return err
+	}
+	return printOutput(env, response, printResponse)
+}
+
+// transitionCommand creates a command sending a transition message for the flow given as argument
+func transitionCommand(send func(*signaling.Client, context.Context, string, dsdk.DataFlowTransitionMessage) error) func(context.Context, *environment, *flag.FlagSet, []string) error {
+	return func(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+		file := fs.String("f", "", "JSON file containing the message, - for stdin")
+		messageID := fs.String("message-id", "", "message ID (generated if empty)")
+		reason := fs.String("reason", "", "reason of the transition")
+		positional, err := parseFlags(fs, args, 1)
+		if err != nil {
+			return err
+		}
+
+		var message dsdk.DataFlowTransitionMessage
+		if *file != "" {
+			if err := readJSON(*file, &message); err != nil {
+				return err
+			}
+		}
+		message.MessageID = firstNonEmpty(*messageID, message.MessageID, uuid.NewString())
+		message.Reason = firstNonEmpty(*reason, message.Reason)
+
+		if err := send(env.client, ctx, positional[0], message); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.out, "%s: %s\n", fs.Name(), positional[0])
+		return nil
+	}
+}
+
+func runComplete(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if err := env.client.Complete(ctx, positional[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.out, "complete: %s\n", positional[0])
+	return nil
+}
+
+func runStatus(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	status, err := env.client.Status(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return printOutput(env, status, printStatus)
+}
+
+func runHistory(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	history, err := env.admin.History(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	return printOutput(env, history, printHistory)
+}
+
+func runList(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	states := fs.String("state", "", "comma-separated states of the listed flows, e.g. STARTED,SUSPENDED")
+	limit := fs.Int("limit", 0, "maximum number of flows")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	query := dsdk.FlowQuery{Limit: *limit}
+	if *states != "" {
+		for _, name := range strings.Split(*states, ",") {
+			state, err := dsdk.ParseDataFlowState(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			query.States = append(query.States, state)
+		}
+	}
+	flows, err := env.admin.List(ctx, query)
+	if err != nil {
+		return err
+	}
+	return printOutput(env, flows, printList)
+}
+
+func runBulk(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error {
+	agreementID := fs.String("agreement-id", "", "select the flows of the agreement")
+	counterPartyID := fs.String("counter-party-id", "", "select the flows with the counterparty")
+	datasetID := fs.String("dataset-id", "", "select the flows of the dataset")
+	reason := fs.String("reason", "", "reason of the transition")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	message := dsdk.DataFlowBulkMessage{
+		Action: dsdk.BulkAction(positional[0]),
+		Selector: dsdk.FlowSelector{
+			AgreementID:    *agreementID,
+			CounterPartyID: *counterPartyID,
+			DatasetID:      *datasetID,
+		},
+		Reason: *reason,
+	}
+	results, err := env.admin.Bulk(ctx, message)
+	if err != nil {
+		return err
+	}
+	if err := printOutput(env, results, printBulk); err != nil {
+		return err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s failed for %d of %d data flows", positional[0], failed, len(results))
+	}
+	return nil
+}
+
+// printOutput writes the value as JSON if requested and in the human-readable format otherwise
+func printOutput[T any](env *environment, value T, human func(io.Writer, T)) error {
+	if env.json {
+		encoder := json.NewEncoder(env.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+	human(env.out, value)
+	return nil
+}
+
+// readJSON decodes the file, or stdin if the path is -, into value
+func readJSON(path string, value any) error {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		reader = file
+	}
+	if err := json.NewDecoder(reader).Decode(value); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,235 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+// Command dpctl sends signaling messages to a data plane and inspects its data flows.
+//
+// Usage:
+//
+//	dpctl [global flags] <command> [flags] [processID]
+//
+// Run dpctl -help for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/signaling"
+)
+
+const (
+	defaultURL = "http://localhost:8080"
+	// urlEnv overrides the default data plane URL
+	urlEnv = "DPCTL_URL"
+	// adminURLEnv overrides the URL of the admin API, which defaults to the data plane URL
+	adminURLEnv = "DPCTL_ADMIN_URL"
+
+	exitError = 1
+	exitUsage = 2
+)
+
+// errUsage reports invalid command line arguments. The usage of the command has already been printed.
+var errUsage = errors.New("invalid usage")
+
+// command is a dpctl subcommand
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, env *environment, fs *flag.FlagSet, args []string) error
+}
+
+var commands = []command{
+	{"prepare", "prepare [flags]", "send a prepare message to a consumer data plane", runPrepare},
+	{"start", "start [flags]", "send a start message", runStart},
+	{"started", "started [flags] <processID>", "notify a consumer data plane that the provider has started", runStarted},
+	{"suspend", "suspend [flags] <processID>", "suspend a data flow", transitionCommand((*signaling.Client).Suspend)},
+	{"resume", "resume [flags] <processID>", "resume a suspended data flow", transitionCommand((*signaling.Client).Resume)},
+	{"terminate", "terminate [flags] <processID>", "terminate a data flow", transitionCommand((*signaling.Client).Terminate)},
+	{"complete", "complete <processID>", "signal that the transfer of a data flow has completed", runComplete},
+	{"status", "status <processID>", "show the status of a data flow", runStatus},
+	{"history", "history <processID>", "show the transition history of a data flow", runHistory},
+	{"list", "list [flags]", "list data flows", runList},
+	{"bulk", "bulk [flags] <suspend|resume|terminate>", "apply an action to the data flows of an agreement, counterparty or dataset", runBulk},
+}
+
+// environment carries the client and output settings shared by all commands
+type environment struct {
+	client *signaling.Client
+	// admin sends operator requests such as listing, history and bulk operations to the admin API
+	admin  *signaling.Client
+	out    io.Writer
+	errOut io.Writer
+	json   bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) int {
+	var headers, adminHeaders headerFlag
+	fs := flag.NewFlagSet("dpctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseURL := fs.String("url", envOrDefault(urlEnv, defaultURL), "base URL of the data plane signaling API (env "+urlEnv+")")
+	adminURL := fs.String("admin-url", os.Getenv(adminURLEnv), "base URL of the data plane admin API used by history, list and bulk, defaults to -url (env "+adminURLEnv+")")
+	timeout := fs.Duration("timeout", 30*time.Second, "request timeout")
+	jsonOutput := fs.Bool("json", false, "print responses as JSON")
+	fs.Var(&headers, "H", "header sent with signaling requests, e.g. -H 'Authorization: Bearer <token>' (repeatable)")
+	fs.Var(&adminHeaders, "admin-H", "header sent with admin requests, e.g. -admin-H 'Authorization: Bearer <token>' (repeatable)")
+	fs.Usage = func() { printUsage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		printUsage(fs)
+		return exitUsage
+	}
+
+	httpClient := &http.Client{Timeout: *timeout}
+	if *adminURL == "" {
+		adminURL = baseURL
+	}
+	env := &environment{
+		client: signaling.NewClient(*baseURL, clientOptions(httpClient, headers)...),
+		admin:  signaling.NewClient(*adminURL, clientOptions(httpClient, adminHeaders)...),
+		out:    stdout,
+		errOut: stderr,
+		json:   *jsonOutput,
+	}
+
+	name := fs.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(ctx, env, newFlagSet(env, cmd), fs.Args()[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return exitUsage
+		default:
+			printError(stderr, err)
+			return exitError
+		}
+	}
+	fmt.Fprintf(stderr, "dpctl: unknown command %q\n", name)
+	printUsage(fs)
+	return exitUsage
+}
+
+func printUsage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Usage: dpctl [global flags] <command> [flags] [processID]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(out, "\nGlobal flags:")
+	fs.PrintDefaults()
+}
+
+// newFlagSet creates the flag set of a command
+func newFlagSet(env *environment, cmd command) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(env.errOut)
+	fs.Usage = func() {
+		fmt.Fprintf(env.errOut, "Usage: dpctl %s\n\nTo %s.\n", cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the command arguments and returns the positional arguments. want is the number of positional
+// arguments the command accepts.
+func parseFlags(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	if fs.NArg() != want {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
+
+func printError(w io.Writer, err error) {
+	var statusErr *signaling.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Problem == nil {
+		fmt.Fprintf(w, "dpctl: %v\n", err)
+		return
+	}
+	problem := statusErr.Problem
+	fmt.Fprintf(w, "dpctl: %s (%d): %s\n", problem.Title, problem.Status, problem.Detail)
+	if problem.State != "" {
+		fmt.Fprintf(w, "  state: %s\n", problem.State)
+	}
+	for _, violation := range problem.Violations {
+		fmt.Fprintf(w, "  %s: %s\n", violation.Field, violation.Message)
+	}
+	if problem.CorrelationID != "" {
+		fmt.Fprintf(w, "  correlation ID: %s\n", problem.CorrelationID)
+	}
+}
+
+// clientOptions configures a client sending the headers
+func clientOptions(httpClient *http.Client, headers headerFlag) []signaling.ClientOption {
+	options := []signaling.ClientOption{signaling.WithHTTPClient(httpClient)}
+	for _, header := range headers {
+		options = append(options, signaling.WithHeader(header[0], header[1]))
+	}
+	return options
+}
+
+// headerFlag collects repeated -H and -admin-H flags
+type headerFlag [][2]string
+
+func (h *headerFlag) String() string {
+	return ""
+}
+
+func (h *headerFlag) Set(value string) error {
+	key, val, found := strings.Cut(value, ":")
+	if !found || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("header %q must have the form 'Key: Value'", value)
+	}
+	*h = append(*h, [2]string{strings.TrimSpace(key), strings.TrimSpace(val)})
+	return nil
+}
+
+func envOrDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,184 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dpctl runs the command line against the data plane and returns the exit code and output
+func dpctl(t *testing.T, dataPlane *dsdktest.DataPlane, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	global := []string{"-url", dataPlane.URL, "-admin-url", dataPlane.AdminURL, "-admin-H", "Authorization: Bearer " + dsdktest.AdminToken}
+	code := run(context.Background(), append(global, args...), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func prepareArgs(processID string) []string {
+	return []string{"prepare",
+		"-process-id", processID,
+		"-agreement-id", "agreement-1",
+		"-participant-id", "did:web:consumer.com",
+		"-counter-party-id", "did:web:provider.com",
+		"-dataspace-context", "dscontext",
+		"-callback", "http://localhost:1/callback",
+		"-destination-type", "HttpData",
+		"-flow-type", "pull",
+	}
+}
+
+func TestDpctl_Lifecycle(t *testing.T) {
+	consumer := dsdktest.NewDataPlane(t)
+
+	code, out, errOut := dpctl(t, consumer, prepareArgs("process-1")...)
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "State:        PREPARED")
+
+	address := filepath.Join(t.TempDir(), "address.json")
+	require.NoError(t, os.WriteFile(address, []byte(`{"properties":{"endpoint":"http://provider.com/data","endpointProperties":[{"key":"authorization","type":"header","value":"secret"}]}}`), 0o600))
+	code, out, errOut = dpctl(t, consumer, "started", "-address", address, "process-1")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "State:        STARTED")
+
+	for _, command := range []string{"suspend", "resume"} {
+		code, out, errOut = dpctl(t, consumer, command, "-reason", "maintenance", "process-1")
+		require.Equal(t, 0, code, errOut)
+		assert.Equal(t, command+": process-1\n", out)
+	}
+	dsdktest.AssertTransitions(t, consumer, "process-1", dsdk.Preparing, dsdk.Prepared, dsdk.Started, dsdk.Suspended, dsdk.Started)
+
+	code, out, errOut = dpctl(t, consumer, "status", "process-1")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "STARTED (4 transitions")
+	assert.Contains(t, out, "Role:              consumer")
+
+	code, out, errOut = dpctl(t, consumer, "list", "-state", "started")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "process-1")
+
+	code, out, errOut = dpctl(t, consumer, "history", "process-1")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "SUSPENDED")
+	assert.Contains(t, out, "maintenance")
+}
+
+func TestDpctl_PrepareFromFile(t *testing.T) {
+	consumer := dsdktest.NewDataPlane(t)
+	controlPlane := dsdktest.NewControlPlane(t)
+	message, err := json.Marshal(controlPlane.NewPrepareMessage("from-file", dsdk.TransferType{DestinationType: "HttpData", FlowType: dsdk.Push}))
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "prepare.json")
+	require.NoError(t, os.WriteFile(file, message, 0o600))
+
+	// flags override the fields of the file
+	code, _, errOut := dpctl(t, consumer, "prepare", "-f", file, "-process-id", "overridden")
+
+	require.Equal(t, 0, code, errOut)
+	dsdktest.AssertState(t, consumer, "overridden", dsdk.Prepared)
+}
+
+func TestDpctl_Bulk(t *testing.T) {
+	consumer := dsdktest.NewDataPlane(t)
+	for _, processID := range []string{"process-1", "process-2"} {
+		code, _, errOut := dpctl(t, consumer, prepareArgs(processID)...)
+		require.Equal(t, 0, code, errOut)
+	}
+
+	code, out, errOut := dpctl(t, consumer, "bulk", "-agreement-id", "agreement-1", "-reason", "agreement revoked", "terminate")
+
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "process-1")
+	assert.Contains(t, out, "process-2")
+	dsdktest.AssertState(t, consumer, "process-1", dsdk.Terminated)
+	dsdktest.AssertState(t, consumer, "process-2", dsdk.Terminated)
+}
+
+func TestDpctl_JSONOutput(t *testing.T) {
+	consumer := dsdktest.NewDataPlane(t)
+	code, _, errOut := dpctl(t, consumer, prepareArgs("process-1")...)
+	require.Equal(t, 0, code, errOut)
+
+	code, out, errOut := dpctl(t, consumer, "-json", "list")
+
+	require.Equal(t, 0, code, errOut)
+	var flows []dsdk.DataFlowStatusResponseMessage
+	require.NoError(t, json.Unmarshal([]byte(out), &flows))
+	require.Len(t, flows, 1)
+	assert.Equal(t, dsdk.Prepared, flows[0].State)
+}
+
+func TestDpctl_Problem(t *testing.T) {
+	dataPlane := dsdktest.NewDataPlane(t)
+
+	code, _, errOut := dpctl(t, dataPlane, "terminate", "unknown")
+
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, errOut, "Not found (404)")
+	assert.Contains(t, errOut, "correlation ID")
+}
+
+func TestDpctl_Usage(t *testing.T) {
+	dataPlane := dsdktest.NewDataPlane(t)
+
+	code, _, errOut := dpctl(t, dataPlane, "unknown")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, errOut, `unknown command "unknown"`)
+
+	code, _, errOut = dpctl(t, dataPlane, "status")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, errOut, "Usage: dpctl status <processID>")
+}
+
+func TestDpctl_SeparateHeaders(t *testing.T) {
+	dataPlane := dsdktest.NewDataPlane(t)
+	var stdout, stderr bytes.Buffer
+	args := []string{"-url", dataPlane.URL, "-admin-url", dataPlane.AdminURL,
+		"-H", "Authorization: Bearer " + dsdktest.AdminToken, "list"}
+
+	code := run(context.Background(), args, &stdout, &stderr)
+
+	assert.Equal(t, exitError, code, "signaling headers must not be sent to the admin API")
+	assert.Contains(t, stderr.String(), "401")
+}
+
+func TestPrintAddress(t *testing.T) {
+	address, err := dsdk.NewDataAddressBuilder().
+		Property(dsdk.EndpointKey, "http://provider.com/data").
+		Property("nested", map[string]any{"b": 2, "a": 1}).
+		EndpointProperty("authorization", "header", "***").
+		Build()
+	require.NoError(t, err)
+	var out bytes.Buffer
+
+	printAddress(&out, address, "")
+
+	assert.Equal(t, `@type: DataAddress
+endpoint: http://provider.com/data
+endpointProperties:
+  authorization (header): ***
+nested:
+  a: 1
+  b: 2
+`, out.String())
+}
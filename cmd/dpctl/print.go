@@ -0,0 +1,174 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+)
+
+func printResponse(w io.Writer, response *dsdk.DataFlowResponseMessage) {
+	fmt.Fprintf(w, "State:        %s\n", response.State)
+	if response.DataplaneID != "" {
+		fmt.Fprintf(w, "Data plane:   %s\n", response.DataplaneID)
+	}
+	if response.Error != "" {
+		fmt.Fprintf(w, "Error:        %s\n", response.Error)
+	}
+	if response.DataAddress != nil {
+		fmt.Fprintln(w, "Data address:")
+		printAddress(w, response.DataAddress, "  ")
+	}
+}
+
+func printStatus(w io.Writer, status *dsdk.DataFlowExtendedStatusResponseMessage) {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", status.DataFlowID)
+	fmt.Fprintf(tw, "State:\t%s (%d transitions, since %s)\n", status.State, status.StateCount, formatMillis(status.StateTimestamp))
+	fmt.Fprintf(tw, "Role:\t%s\n", role(status.Consumer))
+	fmt.Fprintf(tw, "Agreement:\t%s\n", status.AgreementID)
+	fmt.Fprintf(tw, "Dataset:\t%s\n", status.DatasetID)
+	fmt.Fprintf(tw, "Participant:\t%s\n", status.ParticipantID)
+	fmt.Fprintf(tw, "Counter-party:\t%s\n", status.CounterPartyID)
+	fmt.Fprintf(tw, "Dataspace context:\t%s\n", status.DataspaceContext)
+	fmt.Fprintf(tw, "Transfer type:\t%s (%s)\n", status.TransferType.DestinationType, status.TransferType.FlowType)
+	fmt.Fprintf(tw, "Created:\t%s\n", formatMillis(status.CreatedAt))
+	fmt.Fprintf(tw, "Updated:\t%s\n", formatMillis(status.UpdatedAt))
+	if status.Progress != nil {
+		fmt.Fprintf(tw, "Progress:\t%d bytes, %d messages\n", status.Progress.BytesTransferred, status.Progress.MessagesTransferred)
+	}
+	if status.ErrorDetail != "" {
+		fmt.Fprintf(tw, "Error:\t%s\n", status.ErrorDetail)
+	}
+	_ = tw.Flush()
+	if status.SourceDataAddress != nil {
+		fmt.Fprintln(w, "Source address:")
+		printAddress(w, status.SourceDataAddress, "  ")
+	}
+	if status.DestinationDataAddress != nil {
+		fmt.Fprintln(w, "Destination address:")
+		printAddress(w, status.DestinationDataAddress, "  ")
+	}
+}
+
+func printHistory(w io.Writer, history *dsdk.DataFlowHistoryResponseMessage) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tFROM\tTO\tREASON\tMESSAGE\tCALLER")
+	for _, record := range history.Transitions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", formatMillis(record.Timestamp), record.From, record.To,
+			orDash(record.Reason), orDash(record.MessageID), orDash(record.Caller))
+	}
+	_ = tw.Flush()
+}
+
+func printList(w io.Writer, flows []dsdk.DataFlowStatusResponseMessage) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATE\tBYTES\tMESSAGES")
+	for _, flow := range flows {
+		var progress dsdk.TransferProgress
+		if flow.Progress != nil {
+			progress = *flow.Progress
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", flow.DataFlowID, flow.State, progress.BytesTransferred, progress.MessagesTransferred)
+	}
+	_ = tw.Flush()
+}
+
+func printBulk(w io.Writer, results []dsdk.BulkResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tRESULT")
+	for _, result := range results {
+		outcome := "ok"
+		if result.Error != "" {
+			outcome = "failed: " + result.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", result.DataFlowID, outcome)
+	}
+	_ = tw.Flush()
+}
+
+// printAddress writes the properties of a data address sorted by key, one per line. Endpoint properties are written
+// as "key (type): value".
+func printAddress(w io.Writer, address *dsdk.DataAddress, indent string) {
+	for _, key := range slices.Sorted(maps.Keys(address.Properties)) {
+		value := address.Properties[key]
+		if key == dsdk.EndpointProperties {
+			if entries, ok := value.([]any); ok {
+				fmt.Fprintf(w, "%s%s:\n", indent, key)
+				for _, entry := range entries {
+					printEndpointProperty(w, entry, indent+"  ")
+				}
+				continue
+			}
+		}
+		printValue(w, key, value, indent)
+	}
+}
+
+func printEndpointProperty(w io.Writer, entry any, indent string) {
+	property, ok := entry.(map[string]any)
+	if !ok {
+		fmt.Fprintf(w, "%s%v\n", indent, entry)
+		return
+	}
+	if propertyType, ok := property["type"]; ok && propertyType != "" {
+		fmt.Fprintf(w, "%s%v (%v): %v\n", indent, property["key"], propertyType, property["value"])
+		return
+	}
+	fmt.Fprintf(w, "%s%v: %v\n", indent, property["key"], property["value"])
+}
+
+func printValue(w io.Writer, key string, value any, indent string) {
+	switch v := value.(type) {
+	case map[string]any:
+		fmt.Fprintf(w, "%s%s:\n", indent, key)
+		for _, nested := range slices.Sorted(maps.Keys(v)) {
+			printValue(w, nested, v[nested], indent+"  ")
+		}
+	case []any:
+		fmt.Fprintf(w, "%s%s:\n", indent, key)
+		for _, item := range v {
+			printValue(w, "-", item, indent+"  ")
+		}
+	default:
+		fmt.Fprintf(w, "%s%s: %v\n", indent, key, v)
+	}
+}
+
+func formatMillis(millis int64) string {
+	if millis == 0 {
+		return "-"
+	}
+	return time.UnixMilli(millis).UTC().Format(time.RFC3339)
+}
+
+func role(consumer bool) string {
+	if consumer {
+		return "consumer"
+	}
+	return "provider"
+}
+
+func orDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
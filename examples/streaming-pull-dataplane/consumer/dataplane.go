@@ -26,6 +26,7 @@ import (
 
 type ConsumerDataPlane struct {
 	api             *dsdk.DataPlaneApi
+	sdk             *dsdk.DataPlaneSDK
 	signalingServer *http.Server
 	dataServer      *http.Server
 	eventSubscriber *natsservices.EventSubscriber
@@ -34,9 +35,10 @@ type ConsumerDataPlane struct {
 func NewDataPlane(eventSubscriber *natsservices.EventSubscriber) (*ConsumerDataPlane, error) {
 	dataplane := &ConsumerDataPlane{eventSubscriber: eventSubscriber}
 
+	store := memory.NewInMemoryStore()
 	sdk, err := dsdk.NewDataPlaneSDK(
-		dsdk.WithStore(memory.NewInMemoryStore()),
-		dsdk.WithTransactionContext(memory.InMemoryTrxContext{}),
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
 		dsdk.WithPrepareProcessor(dataplane.prepareProcessor),
 		dsdk.WithStartProcessor(dataplane.startProcessor),
 	)
@@ -44,6 +46,7 @@ func NewDataPlane(eventSubscriber *natsservices.EventSubscriber) (*ConsumerDataP
 		return nil, err
 	}
 	dataplane.api = dsdk.NewDataPlaneApi(sdk)
+	dataplane.sdk = sdk
 	return dataplane, nil
 }
 
@@ -64,6 +67,9 @@ func (d *ConsumerDataPlane) Shutdown(ctx context.Context) {
 			log.Printf("Consumer signaling server shutdown error: %v", err)
 		}
 	}
+	if err := d.sdk.Shutdown(ctx); err != nil {
+		log.Printf("Consumer data plane SDK shutdown error: %v", err)
+	}
 	log.Println("Consumer data plane shutdown")
 }
 
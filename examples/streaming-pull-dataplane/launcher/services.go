@@ -32,7 +32,7 @@ func LaunchServices() (*provider.ProviderDataPlane, *consumer.ConsumerDataPlane)
 	if err != nil {
 		log.Fatalf("Failed to initialize Auth Service: %v\n", err)
 	}
-	providerDataplane, err := provider.NewDataPlane(as, ns, provider.NewEventPublisherService())
+	providerDataplane, err := provider.NewDataPlane(as, ns)
 	if err != nil {
 		log.Fatalf("Failed to launch Provider Data Plane: %v\n", err)
 	}
@@ -28,35 +28,40 @@ import (
 // ProviderDataPlane demonstrates how to use the Data Plane SDK. This implementation supports pull event streaming.
 type ProviderDataPlane struct {
 	api                   *dsdk.DataPlaneApi
+	sdk                   *dsdk.DataPlaneSDK
 	signalingServer       *http.Server
 	authService           *natsservices.AuthService
 	connectionInvalidator ConnectionInvalidator
-	publisherService      *EventPublisherService
 }
 
-func NewDataPlane(authService *natsservices.AuthService,
-	invalidator ConnectionInvalidator,
-	publisherService *EventPublisherService) (*ProviderDataPlane, error) {
-	providerDataPlane := &ProviderDataPlane{authService: authService, connectionInvalidator: invalidator, publisherService: publisherService}
+func NewDataPlane(authService *natsservices.AuthService, invalidator ConnectionInvalidator) (*ProviderDataPlane, error) {
+	providerDataPlane := &ProviderDataPlane{authService: authService, connectionInvalidator: invalidator}
 
+	store := memory.NewInMemoryStore()
 	sdk, err := dsdk.NewDataPlaneSDK(
-		dsdk.WithStore(memory.NewInMemoryStore()),
-		dsdk.WithTransactionContext(memory.InMemoryTrxContext{}),
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
 		dsdk.WithPrepareProcessor(providerDataPlane.prepareProcessor),
 		dsdk.WithStartProcessor(providerDataPlane.startProcessor),
 		dsdk.WithSuspendProcessor(providerDataPlane.suspendProcessor),
 		dsdk.WithTerminateProcessor(providerDataPlane.terminateProcessor),
+		dsdk.WithRuntimeID("streaming-pull-provider"),
+		dsdk.WithRecoveryHandler(providerDataPlane.recoverProcessor),
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	providerDataPlane.api = dsdk.NewDataPlaneApi(sdk)
+	providerDataPlane.sdk = sdk
 
 	return providerDataPlane, nil
 }
 
 func (d *ProviderDataPlane) Init() {
+	if err := d.sdk.Startup(context.Background()); err != nil {
+		log.Printf("Provider data plane SDK startup error: %v", err)
+	}
 	d.signalingServer = common.NewSignalingServer(d.api, common.ProviderSignalingPort)
 
 	// Start signaling server
@@ -74,6 +79,9 @@ func (d *ProviderDataPlane) Shutdown(ctx context.Context) {
 			log.Printf("Provider signaling server shutdown error: %v", err)
 		}
 	}
+	if err := d.sdk.Shutdown(ctx); err != nil {
+		log.Printf("Provider data plane SDK shutdown error: %v", err)
+	}
 	log.Println("Provider data plane shutdown")
 }
 
@@ -84,9 +92,9 @@ func (d *ProviderDataPlane) prepareProcessor(_ context.Context,
 	return nil, errors.New("not supported on provider")
 }
 
-func (d *ProviderDataPlane) startProcessor(_ context.Context,
+func (d *ProviderDataPlane) startProcessor(ctx context.Context,
 	flow *dsdk.DataFlow,
-	_ *dsdk.DataPlaneSDK,
+	sdk *dsdk.DataPlaneSDK,
 	options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
 	if options.Duplicate {
 		// Perform de-duplication. This code path is not needed, but it demonstrates how de-deduplication can be handled
@@ -110,23 +118,30 @@ func (d *ProviderDataPlane) startProcessor(_ context.Context,
 		return nil, fmt.Errorf("failed to build data address: %w", err)
 	}
 
-	// Start publishing events. In a real system, this could be done via a queue or notification mechanism
-	d.publisherService.Start(channel)
+	// Start publishing events once the flow has been started. In a real system, this could be done via a queue or
+	// notification mechanism
+	sdk.RegisterFlowWorker(ctx, flow, publishEvents(channel))
 
 	log.Printf("[Provider Data Plane] Starting transfer for %s\n", flow.CounterPartyID)
 	return &dsdk.DataFlowResponseMessage{State: dsdk.Started, DataAddress: da}, nil
 }
 
+// recoverProcessor restarts the publisher of a started flow after a data plane restart
+func (d *ProviderDataPlane) recoverProcessor(ctx context.Context, flow *dsdk.DataFlow) error {
+	if flow.State != dsdk.Started {
+		return nil
+	}
+	d.sdk.RegisterFlowWorker(ctx, flow, publishEvents(flow.ID+"."+natsservices.ForwardSuffix))
+	log.Printf("[Provider Data Plane] Recovered transfer for %s\n", flow.CounterPartyID)
+	return nil
+}
+
 func (d *ProviderDataPlane) suspendProcessor(_ context.Context, flow *dsdk.DataFlow) error {
-	channel := flow.ID + "." + natsservices.ForwardSuffix
-	d.publisherService.Terminate(channel)
 	log.Printf("[Provider Data Plane] Suspending transfer for %s\n", flow.CounterPartyID)
 	return d.invalidate(flow)
 }
 
 func (d *ProviderDataPlane) terminateProcessor(_ context.Context, flow *dsdk.DataFlow) error {
-	channel := flow.ID + "." + natsservices.ForwardSuffix
-	d.publisherService.Terminate(channel)
 	log.Printf("[Provider Data Plane] Terminating transfer for %s\n", flow.CounterPartyID)
 	return d.invalidate(flow)
 }
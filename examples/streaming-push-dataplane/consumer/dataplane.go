@@ -28,6 +28,7 @@ import (
 // ConsumerDataPlane demonstrates how to use the Data Plane SDK. This implementation supports push event streaming.
 type ConsumerDataPlane struct {
 	api                   *dsdk.DataPlaneApi
+	sdk                   *dsdk.DataPlaneSDK
 	signalingServer       *http.Server
 	authService           *natsservices.AuthService
 	connectionInvalidator ConnectionInvalidator
@@ -46,9 +47,10 @@ func NewDataPlane(authService *natsservices.AuthService,
 		natsUrl:               natsUrl,
 		eventSubscriber:       eventSubscriber}
 
+	store := memory.NewInMemoryStore()
 	sdk, err := dsdk.NewDataPlaneSDK(
-		dsdk.WithStore(memory.NewInMemoryStore()),
-		dsdk.WithTransactionContext(memory.InMemoryTrxContext{}),
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
 		dsdk.WithPrepareProcessor(dataPlane.prepareProcessor),
 		dsdk.WithStartProcessor(dataPlane.startProcessor),
 		dsdk.WithSuspendProcessor(dataPlane.suspendProcessor),
@@ -59,6 +61,7 @@ func NewDataPlane(authService *natsservices.AuthService,
 	}
 
 	dataPlane.api = dsdk.NewDataPlaneApi(sdk)
+	dataPlane.sdk = sdk
 
 	return dataPlane, nil
 }
@@ -81,6 +84,9 @@ func (d *ConsumerDataPlane) Shutdown(ctx context.Context) {
 			log.Printf("Consumer signaling server shutdown error: %v", err)
 		}
 	}
+	if err := d.sdk.Shutdown(ctx); err != nil {
+		log.Printf("Consumer data plane SDK shutdown error: %v", err)
+	}
 	log.Println("Consumer data plane shutdown")
 }
 
@@ -26,7 +26,7 @@ func LaunchServices() (*provider.ProviderDataPlane, *consumer.ConsumerDataPlane)
 		log.Fatalf("Failed to initialize NATS Server: %v\n", err)
 	}
 
-	providerDataplane, err := provider.NewDataPlane(provider.NewEventPublisherService())
+	providerDataplane, err := provider.NewDataPlane()
 	if err != nil {
 		log.Fatalf("Failed to launch Provider Data Plane: %v\n", err)
 	}
@@ -25,31 +25,38 @@ import (
 )
 
 type ProviderDataPlane struct {
-	api              *dsdk.DataPlaneApi
-	signalingServer  *http.Server
-	dataServer       *http.Server
-	publisherService *EventPublisherService
+	api             *dsdk.DataPlaneApi
+	sdk             *dsdk.DataPlaneSDK
+	signalingServer *http.Server
+	dataServer      *http.Server
 }
 
-func NewDataPlane(publisherService *EventPublisherService) (*ProviderDataPlane, error) {
-	dataplane := &ProviderDataPlane{publisherService: publisherService}
+func NewDataPlane() (*ProviderDataPlane, error) {
+	dataplane := &ProviderDataPlane{}
+	store := memory.NewInMemoryStore()
 	sdk, err := dsdk.NewDataPlaneSDK(
-		dsdk.WithStore(memory.NewInMemoryStore()),
-		dsdk.WithTransactionContext(memory.InMemoryTrxContext{}),
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
 		dsdk.WithPrepareProcessor(dataplane.prepareProcessor),
 		dsdk.WithStartProcessor(dataplane.startProcessor),
 		dsdk.WithSuspendProcessor(dataplane.suspendProcessor),
 		dsdk.WithTerminateProcessor(dataplane.terminateProcessor),
+		dsdk.WithRuntimeID("streaming-push-provider"),
+		dsdk.WithRecoveryHandler(dataplane.recoverProcessor),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 	dataplane.api = dsdk.NewDataPlaneApi(sdk)
+	dataplane.sdk = sdk
 	return dataplane, nil
 }
 
 func (d *ProviderDataPlane) Init() {
+	if err := d.sdk.Startup(context.Background()); err != nil {
+		log.Printf("Provider data plane SDK startup error: %v", err)
+	}
 	d.signalingServer = common.NewSignalingServer(d.api, common.ProviderSignalingPort)
 	// Start signaling server
 	go func() {
@@ -66,6 +73,9 @@ func (d *ProviderDataPlane) Shutdown(ctx context.Context) {
 			log.Printf("Provider signaling server shutdown error: %v", err)
 		}
 	}
+	if err := d.sdk.Shutdown(ctx); err != nil {
+		log.Printf("Provider data plane SDK shutdown error: %v", err)
+	}
 	log.Println("Provider data plane shutdown")
 }
 
@@ -78,37 +88,54 @@ func (d *ProviderDataPlane) prepareProcessor(_ context.Context,
 
 func (d *ProviderDataPlane) startProcessor(ctx context.Context,
 	flow *dsdk.DataFlow,
-	_ *dsdk.DataPlaneSDK,
+	sdk *dsdk.DataPlaneSDK,
 	options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
 
-	endpoint := options.DataAddress.Properties[dsdk.EndpointKey].(string)
-	token, found := parseToken(natsservices.TokenKey, options.DataAddress)
+	if err := startPublisher(ctx, sdk, flow, options.DataAddress); err != nil {
+		return nil, err
+	}
+	// keep the address so that the publisher can be recovered after a restart
+	flow.DestinationDataAddress = *options.DataAddress
+
+	log.Printf("[Provider Data Plane] Started NATS subscriber for participant %s dataset %s\n", flow.ParticipantID, flow.DatasetID)
+	return &dsdk.DataFlowResponseMessage{State: dsdk.Started}, nil
+}
+
+// recoverProcessor restarts the publisher of a started flow after a data plane restart
+func (d *ProviderDataPlane) recoverProcessor(ctx context.Context, flow *dsdk.DataFlow) error {
+	if flow.State != dsdk.Started {
+		return nil
+	}
+	if err := startPublisher(ctx, d.sdk, flow, &flow.DestinationDataAddress); err != nil {
+		return err
+	}
+	log.Printf("[Provider Data Plane] Recovered NATS publisher for %s\n", flow.ID)
+	return nil
+}
+
+// startPublisher registers the publisher of the flow, which is run by the SDK once the flow has been started
+func startPublisher(ctx context.Context, sdk *dsdk.DataPlaneSDK, flow *dsdk.DataFlow, address *dsdk.DataAddress) error {
+	endpoint := address.Properties[dsdk.EndpointKey].(string)
+	token, found := parseToken(natsservices.TokenKey, address)
 	if !found {
-		return nil, errors.New("token not found in endpoint properties")
+		return errors.New("token not found in endpoint properties")
 	}
-	channel, found := parseToken(natsservices.ChannelKey, options.DataAddress)
+	channel, found := parseToken(natsservices.ChannelKey, address)
 	if !found {
-		return nil, errors.New("channel not found in endpoint properties")
+		return errors.New("channel not found in endpoint properties")
 	}
 
-	// publisher close and reopen
-	d.publisherService.Terminate(channel)
-	d.publisherService.Start(flow.ID, endpoint, channel, token)
-
-	log.Printf("[Provider Data Plane] Started NATS subscriber for participant %s dataset %s\n", flow.ParticipantID, flow.DatasetID)
-	return &dsdk.DataFlowResponseMessage{State: dsdk.Started}, nil
+	// replaces a publisher registered previously for the flow
+	sdk.RegisterFlowWorker(ctx, flow, publishEvents(endpoint, channel, token))
+	return nil
 }
 
 func (d *ProviderDataPlane) terminateProcessor(_ context.Context, flow *dsdk.DataFlow) error {
-	d.publisherService.Terminate(flow.ID)
-
 	log.Printf("[Provider Data Plane] Terminated transfer for %s\n", flow.CounterPartyID)
 	return nil
 }
 
 func (d *ProviderDataPlane) suspendProcessor(_ context.Context, flow *dsdk.DataFlow) error {
-	d.publisherService.Terminate(flow.ID)
-
 	log.Printf("[Provider Data Plane] Suspended transfer for %s\n", flow.CounterPartyID)
 	return nil
 }
@@ -15,82 +15,41 @@ package provider
 import (
 	"context"
 	"fmt"
-	"github.com/metaform/dataplane-sdk-go/examples/common"
-	"github.com/nats-io/nats.go"
 	"log"
 	"time"
-)
-
-// EventPublisherService mocks a service that publishes event streams intended for clients. Event streams are managed by
-// the provider data plane.
-type EventPublisherService struct {
-	publisherStore *common.Store[*storeEntry]
-}
-
-func NewEventPublisherService() *EventPublisherService {
-	return &EventPublisherService{publisherStore: common.NewStore[*storeEntry]()}
-}
-
-func (m *EventPublisherService) Start(id string, endpoint string, channel string, token string) {
-	ctx, cancellation := context.WithCancel(context.Background())
-	entry := &storeEntry{id: id, channel: channel, endpoint: endpoint, token: token, cancellation: &cancellation}
-	m.publisherStore.Create(id, entry)
-	go m.startInternal(ctx, entry)
-}
-
-func (m *EventPublisherService) Terminate(id string) {
-	cancellation, found := m.publisherStore.Find(id)
-	if !found {
-		return
-	}
-	m.publisherStore.Delete(id)
-	(*cancellation.cancellation)()
-}
 
-func (m *EventPublisherService) startInternal(ctx context.Context, entry *storeEntry) {
-	nc, err := connect(entry)
-	if err != nil {
-		log.Printf("[Event Publisher] Failed to connect to NATS: %v", err)
-		return
-	}
-	defer nc.Close()
-
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/nats-io/nats.go"
+)
 
-	i := 0
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("[Event Publisher] Event publishing cancelled: %v", ctx.Err())
-			return
-		case <-ticker.C:
-			log.Printf("[Event Publisher] Sending event: %d\n", i)
-			err := nc.Publish(entry.channel, []byte(fmt.Sprintf(`{"data": "Event %d"}`, i)))
-			if err != nil {
-				log.Printf("[Event Publisher] Failed to publish event: %v", err)
-				return
+// publishEvents mocks a service that publishes an event stream to the consumer endpoint. The publisher runs as a flow
+// worker, so that the SDK stops it when the flow is suspended or terminated and restarts it when the flow is resumed.
+func publishEvents(endpoint string, channel string, token string) dsdk.FlowWorker {
+	return func(ctx context.Context, _ *dsdk.DataFlow) error {
+		nc, err := nats.Connect(endpoint, nats.Token(token))
+		if err != nil {
+			return fmt.Errorf("connecting to NATS: %w", err)
+		}
+		log.Println("[Event Publisher] connected to provider NATS")
+		defer nc.Close()
+
+		ticker := time.NewTicker(1 * time.Second)
+		defer ticker.Stop()
+
+		i := 0
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("[Event Publisher] Event publishing cancelled: %v", ctx.Err())
+				return ctx.Err()
+			case <-ticker.C:
+				log.Printf("[Event Publisher] Sending event: %d\n", i)
+				err := nc.Publish(channel, []byte(fmt.Sprintf(`{"data": "Event %d"}`, i)))
+				if err != nil {
+					return fmt.Errorf("publishing event: %w", err)
+				}
+				i++
 			}
-			i++
 		}
 	}
 }
-
-func connect(entry *storeEntry) (*nats.Conn, error) {
-	nc, err := nats.Connect(entry.endpoint,
-		nats.Token(entry.token),
-	)
-	if err != nil {
-		return nil, err
-	}
-	log.Println("[Event Publisher] connected to provider NATS")
-	return nc, nil
-}
-
-type storeEntry struct {
-	id           string
-	channel      string
-	endpoint     string
-	token        string
-	cancellation *context.CancelFunc
-}
@@ -40,6 +40,7 @@ const (
 // period of time. For example, the dataset could be access to an API.
 type ConsumerDataPlane struct {
 	api             *dsdk.DataPlaneApi
+	sdk             *dsdk.DataPlaneSDK
 	signalingServer *http.Server
 	dataServer      *http.Server
 	tokenStore      *common.Store[tokenEntry]
@@ -48,9 +49,10 @@ type ConsumerDataPlane struct {
 func NewDataPlane() (*ConsumerDataPlane, error) {
 	dataplane := &ConsumerDataPlane{tokenStore: common.NewStore[tokenEntry]()}
 
+	store := memory.NewInMemoryStore()
 	sdk, err := dsdk.NewDataPlaneSDK(
-		dsdk.WithStore(memory.NewInMemoryStore()),
-		dsdk.WithTransactionContext(memory.InMemoryTrxContext{}),
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
 		dsdk.WithPrepareProcessor(dataplane.prepareProcessor),
 		dsdk.WithStartProcessor(dataplane.startProcessor),
 	)
@@ -58,6 +60,7 @@ func NewDataPlane() (*ConsumerDataPlane, error) {
 		return nil, err
 	}
 	dataplane.api = dsdk.NewDataPlaneApi(sdk)
+	dataplane.sdk = sdk
 	return dataplane, nil
 }
 
@@ -86,6 +89,9 @@ func (d *ConsumerDataPlane) Shutdown(ctx context.Context) {
 			log.Printf("Consumer signaling server shutdown error: %v", err)
 		}
 	}
+	if err := d.sdk.Shutdown(ctx); err != nil {
+		log.Printf("Consumer data plane SDK shutdown error: %v", err)
+	}
 	log.Println("Consumer data plane shutdown")
 }
 
@@ -36,6 +36,7 @@ const (
 // the transfer of simple JSON datasets over HTTP and Data Plane Signaling start and prepare handling using synchronous responses.
 type ProviderDataPlane struct {
 	api             *dsdk.DataPlaneApi
+	sdk             *dsdk.DataPlaneSDK
 	tokenStore      *common.Store[tokenEntry]
 	signalingServer *http.Server
 	dataServer      *http.Server
@@ -46,9 +47,10 @@ func NewDataPlane() (*ProviderDataPlane, error) {
 		tokenStore: common.NewStore[tokenEntry](),
 	}
 
+	store := memory.NewInMemoryStore()
 	sdk, err := dsdk.NewDataPlaneSDK(
-		dsdk.WithStore(memory.NewInMemoryStore()),
-		dsdk.WithTransactionContext(memory.InMemoryTrxContext{}),
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
 		dsdk.WithPrepareProcessor(providerDataPlane.prepareProcessor),
 		dsdk.WithStartProcessor(providerDataPlane.startProcessor),
 		dsdk.WithSuspendProcessor(providerDataPlane.suspendProcessor),
@@ -59,6 +61,7 @@ func NewDataPlane() (*ProviderDataPlane, error) {
 	}
 
 	providerDataPlane.api = dsdk.NewDataPlaneApi(sdk)
+	providerDataPlane.sdk = sdk
 
 	return providerDataPlane, nil
 }
@@ -95,6 +98,9 @@ func (d *ProviderDataPlane) Shutdown(ctx context.Context) {
 			log.Printf("Provider data server shutdown error: %v", err)
 		}
 	}
+	if err := d.sdk.Shutdown(ctx); err != nil {
+		log.Printf("Provider data plane SDK shutdown error: %v", err)
+	}
 	log.Println("Provider data plane shutdown")
 }
 
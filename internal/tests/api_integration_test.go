@@ -14,7 +14,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
@@ -31,33 +30,7 @@ var database *sql.DB
 // newServerWithSdk instantiates a new HTTP server using the DataPlane SDK and registers its callbacks with endpoints
 func newServerWithSdk(t *testing.T, sdk *dsdk.DataPlaneSDK) http.Handler {
 	t.Helper()
-	sdkApi := dsdk.NewDataPlaneApi(sdk)
-	r := chi.NewRouter()
-
-	r.Post("/dataflows/start", sdkApi.Start)
-	r.Post("/dataflows/{id}/started", func(writer http.ResponseWriter, request *http.Request) {
-		id := chi.URLParam(request, "id")
-		sdkApi.StartById(writer, request, id)
-	})
-	r.Post("/dataflows/prepare", sdkApi.Prepare)
-	r.Post("/dataflows/{id}/terminate", func(writer http.ResponseWriter, request *http.Request) {
-		id := chi.URLParam(request, "id")
-		sdkApi.Terminate(id, writer, request)
-	})
-	r.Post("/dataflows/{id}/suspend", func(writer http.ResponseWriter, request *http.Request) {
-		id := chi.URLParam(request, "id")
-		sdkApi.Suspend(id, writer, request)
-	})
-	r.Get("/dataflows/{id}/status", func(writer http.ResponseWriter, request *http.Request) {
-		id := chi.URLParam(request, "id")
-		sdkApi.Status(id, writer, request)
-	})
-
-	r.Post("/dataflows/{id}/completed", func(writer http.ResponseWriter, request *http.Request) {
-		id := chi.URLParam(request, "id")
-		sdkApi.Complete(id, writer, request)
-	})
-	return r
+	return dsdk.NewDataPlaneApi(sdk).Handler()
 }
 
 var handler http.Handler
@@ -0,0 +1,175 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ForceTransition sets the state of a data flow regardless of the state machine, e.g. to terminate an orphaned flow or
+// to correct a state after an incident. The reason is required and recorded in the transition history together with
+// the caller. When forcing a flow to SUSPENDED or TERMINATED, the corresponding handler is invoked to release
+// resources; its errors are logged and do not prevent the transition. The control plane is not notified.
+func (dsdk *DataPlaneSDK) ForceTransition(ctx context.Context, processID string, state DataFlowState, reason string) error {
+	if processID == "" {
+		return errors.New("processID cannot be empty")
+	}
+	if reason == "" {
+		return fmt.Errorf("%w: a reason is required to force a transition", ErrInvalidInput)
+	}
+	if state == Uninitialized || !slices.Contains(dataFlowStates, state) {
+		return fmt.Errorf("%w: cannot force data flow %s to %s", ErrInvalidInput, processID, state)
+	}
+
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return fmt.Errorf("forcing transition of data flow %s: %w", processID, err)
+		}
+		op.attribute(flow)
+
+		if flow.State == state {
+			return nil
+		}
+		var handler DataFlowHandler
+		switch state {
+		case Suspended:
+			handler = dsdk.onSuspend
+		case Terminated:
+			handler = dsdk.onTerminate
+		}
+		if handler != nil {
+			if err := handler(ctx, flow); err != nil {
+				dsdk.Monitor.Printf("Ignoring handler error while forcing data flow %s to %s: %v\n", flow.ID, state, err)
+			}
+		}
+
+		dsdk.Monitor.Printf("Forcing data flow %s from %s to %s by %q: %s\n", flow.ID, flow.State, state, op.caller, reason)
+		flow.forceTransition(state, reason)
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return fmt.Errorf("forcing transition of data flow %s: %w", flow.ID, err)
+		}
+		if state == Suspended || state == Terminated || state == Completed {
+			dsdk.stopFlowWorkerOnCommit(op, flow)
+		}
+		return nil
+	})
+}
+
+// RetryStart re-runs the start processor for a data flow stuck in STARTING. The processor is invoked with the address
+// stored on the flow, the source address for consumer and the destination address for provider flows. If the flow is
+// started, the control plane is notified. Transient and fatal processor errors are handled as for start messages.
+func (dsdk *DataPlaneSDK) RetryStart(ctx context.Context, processID string) (*DataFlowResponseMessage, error) {
+	if processID == "" {
+		return nil, errors.New("processID cannot be empty")
+	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var response *DataFlowResponseMessage
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	err = dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return fmt.Errorf("retrying start of data flow %s: %w", processID, err)
+		}
+		op.attribute(flow)
+
+		if flow.State != Starting {
+			return &FlowStateError{
+				Err:     ErrInvalidTransition,
+				FlowID:  flow.ID,
+				State:   flow.State,
+				Message: fmt.Sprintf("data flow %s is not in STARTING state but in %s", flow.ID, flow.State),
+			}
+		}
+
+		options := &ProcessorOptions{DataAddress: storedAddress(flow)}
+		response, err = dsdk.onStart(withOperation(ctx, op), flow, dsdk, options)
+		if err != nil {
+			if isClassified(err) {
+				response, err = dsdk.processorFailure(ctx, op, flow, Starting, dsdk.onStart, options, dsdk.Store.Save, err)
+				return err
+			}
+			return fmt.Errorf("processing data flow %s: %w", flow.ID, err)
+		}
+		if response == nil {
+			return fmt.Errorf("processor returned no response for data flow %s", flow.ID)
+		}
+		if err := dsdk.startState(response, flow); err != nil {
+			return fmt.Errorf("onStart returned an invalid state: %w", err)
+		}
+		flow.ErrorDetail = ""
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return fmt.Errorf("retrying start of data flow %s: %w", flow.ID, err)
+		}
+		if flow.State == Started {
+			started := response
+			op.afterCommit(func() {
+				dsdk.notifyAsync(flow, StartedEvent, started)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Delete removes a data flow from the store. Processors are not invoked and the control plane is not notified.
+func (dsdk *DataPlaneSDK) Delete(ctx context.Context, processID string) error {
+	if processID == "" {
+		return errors.New("processID cannot be empty")
+	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	op := newOperation(ctx, "")
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		if err := dsdk.Store.Delete(ctx, processID); err != nil {
+			return fmt.Errorf("deleting data flow %s: %w", processID, err)
+		}
+		op.afterCommit(func() { dsdk.stopFlowWorker(processID, true) })
+		dsdk.Monitor.Printf("Deleted data flow %s by %q\n", processID, CallerFromContext(ctx))
+		return nil
+	})
+}
+
+// storedAddress returns the address a start processor receives for the flow, or nil if none is stored
+func storedAddress(flow *DataFlow) *DataAddress {
+	address := flow.DestinationDataAddress
+	if flow.Consumer {
+		address = flow.SourceDataAddress
+	}
+	if len(address.Properties) == 0 {
+		return nil
+	}
+	return &address
+}
@@ -0,0 +1,214 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// AdminAuthorizer authorizes requests to the admin API. It returns the identity of the operator, which is recorded as
+// caller in the transition history, or an error if the request is not authorized.
+type AdminAuthorizer func(r *http.Request) (string, error)
+
+// BearerTokenAuthorizer authorizes requests carrying one of the tokens as bearer token. The map assigns each token the
+// identity of its operator.
+func BearerTokenAuthorizer(tokens map[string]string) AdminAuthorizer {
+	return func(r *http.Request) (string, error) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || token == "" {
+			return "", fmt.Errorf("%w: missing bearer token", ErrUnauthorized)
+		}
+		for candidate, operator := range tokens {
+			if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+				return operator, nil
+			}
+		}
+		return "", fmt.Errorf("%w: invalid bearer token", ErrUnauthorized)
+	}
+}
+
+// AdminApi exposes operator interventions on data flows. It is authorized independently of the signaling API and
+// should be served on a separate listener or path.
+type AdminApi struct {
+	sdk        *DataPlaneSDK
+	authorizer AdminAuthorizer
+	// api writes responses and problems the same way as the signaling API
+	api *DataPlaneApi
+}
+
+// NewAdminApi creates the admin API. All requests are rejected if the authorizer is nil.
+func NewAdminApi(sdk *DataPlaneSDK, authorizer AdminAuthorizer) *AdminApi {
+	return &AdminApi{sdk: sdk, authorizer: authorizer, api: NewDataPlaneApi(sdk)}
+}
+
+// Handler returns a handler serving the admin endpoints:
+//
+//	POST   /dataflows/{id}/transition   force a transition, body AdminTransitionMessage
+//	POST   /dataflows/{id}/retry-start  re-run the start processor
+//	DELETE /dataflows/{id}              delete the flow
+//	GET    /dataflows                   list flows, see AdminApi.List
+//	GET    /dataflows/{id}/history      transition history of a flow
+//	POST   /dataflows/bulk              bulk suspend, resume or terminate, body DataFlowBulkMessage
+func (a *AdminApi) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dataflows", a.List)
+	mux.HandleFunc("GET /dataflows/{id}/history", func(w http.ResponseWriter, r *http.Request) {
+		a.History(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("POST /dataflows/bulk", a.Bulk)
+	mux.HandleFunc("POST /dataflows/{id}/transition", func(w http.ResponseWriter, r *http.Request) {
+		a.ForceTransition(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("POST /dataflows/{id}/retry-start", func(w http.ResponseWriter, r *http.Request) {
+		a.RetryStart(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("DELETE /dataflows/{id}", func(w http.ResponseWriter, r *http.Request) {
+		a.Delete(r.PathValue("id"), w, r)
+	})
+	return mux
+}
+
+// ForceTransition sets the state of a data flow to the state of the AdminTransitionMessage.
+func (a *AdminApi) ForceTransition(id string, w http.ResponseWriter, r *http.Request) {
+	ctx, ok := a.authorize(w, r)
+	if !ok {
+		return
+	}
+	var message AdminTransitionMessage
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		a.api.decodingError(w, r, err)
+		return
+	}
+	if err := message.Validate(); err != nil {
+		a.api.handleError(err, w, r)
+		return
+	}
+	state, err := ParseDataFlowState(message.State)
+	if err != nil {
+		a.api.handleError(err, w, r)
+		return
+	}
+
+	if err := a.sdk.ForceTransition(ContextWithMessageID(ctx, message.MessageID), id, state, message.Reason); err != nil {
+		a.api.handleError(err, w, r)
+		return
+	}
+	flow, err := a.sdk.Status(ctx, id)
+	if err != nil {
+		a.api.handleError(err, w, r)
+		return
+	}
+	a.api.writeResponse(w, http.StatusOK, DataFlowStatusResponseMessage{State: flow.State, DataFlowID: flow.ID, Progress: progressOf(flow)})
+}
+
+// RetryStart re-runs the start processor of a data flow stuck in STARTING.
+func (a *AdminApi) RetryStart(id string, w http.ResponseWriter, r *http.Request) {
+	ctx, ok := a.authorize(w, r)
+	if !ok {
+		return
+	}
+	message, ok := a.decodeReason(w, r)
+	if !ok {
+		return
+	}
+	a.sdk.Monitor.Printf("Retrying start of data flow %s by %q: %s\n", id, CallerFromContext(ctx), message.Reason)
+
+	response, err := a.sdk.RetryStart(ContextWithMessageID(ctx, message.MessageID), id)
+	if err != nil {
+		a.api.handleError(err, w, r)
+		return
+	}
+	a.api.writeResponse(w, http.StatusOK, response)
+}
+
+// Delete removes a data flow.
+func (a *AdminApi) Delete(id string, w http.ResponseWriter, r *http.Request) {
+	ctx, ok := a.authorize(w, r)
+	if !ok {
+		return
+	}
+	message, ok := a.decodeReason(w, r)
+	if !ok {
+		return
+	}
+	if message.Reason != "" {
+		a.sdk.Monitor.Printf("Deleting data flow %s by %q: %s\n", id, CallerFromContext(ctx), message.Reason)
+	}
+
+	if err := a.sdk.Delete(ctx, id); err != nil {
+		a.api.handleError(err, w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List returns the status of the data flows selected by the StateParam and LimitParam query parameters.
+func (a *AdminApi) List(w http.ResponseWriter, r *http.Request) {
+	if ctx, ok := a.authorize(w, r); ok {
+		a.api.list(w, r.WithContext(ctx))
+	}
+}
+
+// History returns the transition history of a data flow.
+func (a *AdminApi) History(id string, w http.ResponseWriter, r *http.Request) {
+	if ctx, ok := a.authorize(w, r); ok {
+		a.api.history(id, w, r.WithContext(ctx))
+	}
+}
+
+// Bulk applies a suspend, resume or terminate action to the selected data flows.
+func (a *AdminApi) Bulk(w http.ResponseWriter, r *http.Request) {
+	if ctx, ok := a.authorize(w, r); ok {
+		a.api.bulk(w, r.WithContext(ctx))
+	}
+}
+
+// authorize returns the request context with the operator identity as caller. If the request is not authorized, a
+// problem is written and false is returned.
+func (a *AdminApi) authorize(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	if a.authorizer == nil {
+		a.api.handleError(fmt.Errorf("%w: no admin authorizer configured", ErrUnauthorized), w, r)
+		return nil, false
+	}
+	operator, err := a.authorizer(r)
+	if err != nil {
+		a.api.handleError(err, w, r)
+		return nil, false
+	}
+	return ContextWithCaller(r.Context(), operator), true
+}
+
+// decodeReason decodes the optional body of admin requests carrying an audit reason
+func (a *AdminApi) decodeReason(w http.ResponseWriter, r *http.Request) (DataFlowTransitionMessage, bool) {
+	var message DataFlowTransitionMessage
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.api.decodingError(w, r, err)
+		return message, false
+	}
+	if len(body) == 0 {
+		return message, true
+	}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&message); err != nil {
+		a.api.decodingError(w, r, err)
+		return message, false
+	}
+	return message, true
+}
@@ -0,0 +1,144 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var adminTokens = BearerTokenAuthorizer(map[string]string{"admin-token": "operator"})
+
+func TestAdminApi_Unauthorized(t *testing.T) {
+	sdk := &DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	tests := []struct {
+		name       string
+		authorizer AdminAuthorizer
+		header     string
+	}{
+		{name: "no authorizer", authorizer: nil, header: "Bearer admin-token"},
+		{name: "missing token", authorizer: adminTokens},
+		{name: "invalid token", authorizer: adminTokens, header: "Bearer signaling-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/dataflows/flow123", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			NewAdminApi(sdk, tt.authorizer).Handler().ServeHTTP(rec, req)
+
+			problem := decodeProblem(t, rec, http.StatusUnauthorized)
+			assert.Equal(t, ProblemTypeUnauthorized, problem.Type)
+		})
+	}
+}
+
+func TestAdminApi_ForceTransition(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk := &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	flow := &DataFlow{ID: "flow123", State: Started}
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(flow, nil)
+	var caller string
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		transitions := df.TakeTransitions()
+		caller = transitions[0].Caller
+		return df.State == Suspended
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/flow123/transition", strings.NewReader(`{"state":"suspended","reason":"incident 42"}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+
+	NewAdminApi(sdk, adminTokens).Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response DataFlowStatusResponseMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, Suspended, response.State)
+	assert.NotContains(t, rec.Body.String(), "progress", "flows without progress must not report an empty progress")
+	assert.Equal(t, "incident 42", flow.ErrorDetail)
+	assert.Equal(t, "operator", caller)
+}
+
+func TestAdminApi_ForceTransition_InvalidMessage(t *testing.T) {
+	sdk := &DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	for _, body := range []string{`{"state":"started"}`, `{"state":"unknown","reason":"incident"}`, `{`} {
+		req := httptest.NewRequest(http.MethodPost, "/dataflows/flow123/transition", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-token")
+		rec := httptest.NewRecorder()
+
+		NewAdminApi(sdk, adminTokens).Handler().ServeHTTP(rec, req)
+
+		decodeProblem(t, rec, http.StatusBadRequest)
+	}
+}
+
+func TestAdminApi_Delete(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk := &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	store.EXPECT().Delete(mock.Anything, "flow123").Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/dataflows/flow123", strings.NewReader(`{"reason":"cleanup"}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+
+	NewAdminApi(sdk, adminTokens).Handler().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestAdminApi_OperatorEndpointsUnauthorized(t *testing.T) {
+	sdk := &DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/dataflows", nil),
+		httptest.NewRequest(http.MethodGet, "/dataflows/flow123/history", nil),
+		httptest.NewRequest(http.MethodPost, "/dataflows/bulk", strings.NewReader(`{"action":"terminate","selector":{"agreementID":"a"}}`)),
+	} {
+		t.Run(req.Method+" "+req.URL.Path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			NewAdminApi(sdk, adminTokens).Handler().ServeHTTP(rec, req)
+
+			decodeProblem(t, rec, http.StatusUnauthorized)
+		})
+	}
+}
+
+func TestAdminApi_List(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk := &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: []DataFlowState{Started}}).
+		Return(newSliceIterator(&DataFlow{ID: "flow123", State: Started}), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows?state=STARTED", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+
+	NewAdminApi(sdk, adminTokens).Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response DataFlowListResponseMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.DataFlows, 1)
+	assert.Equal(t, "flow123", response.DataFlows[0].DataFlowID)
+}
@@ -0,0 +1,133 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneSDK_ForceTransition(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	terminated := false
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		Monitor:    defaultLogMonitor{},
+		onTerminate: func(ctx context.Context, flow *DataFlow) error {
+			terminated = true
+			// handler errors do not prevent the transition
+			return errors.New("resources already released")
+		},
+	}
+	ctx := ContextWithCaller(context.Background(), "operator")
+
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", State: Starting, StateCount: 1}, nil)
+	var transitions []TransitionRecord
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		transitions = df.TakeTransitions()
+		return df.State == Terminated && df.ErrorDetail == "orphaned" && df.StateCount == 2
+	})).Return(nil)
+
+	err := dsdk.ForceTransition(ctx, "flow123", Terminated, "orphaned")
+
+	require.NoError(t, err)
+	assert.True(t, terminated)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, Starting, transitions[0].From)
+	assert.Equal(t, Terminated, transitions[0].To)
+	assert.Equal(t, "orphaned", transitions[0].Reason)
+	assert.Equal(t, "operator", transitions[0].Caller)
+}
+
+func Test_DataPlaneSDK_ForceTransition_SameState(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", State: Started}, nil)
+
+	// no save call expected
+
+	err := dsdk.ForceTransition(context.Background(), "flow123", Started, "correction")
+
+	assert.NoError(t, err)
+}
+
+func Test_DataPlaneSDK_ForceTransition_InvalidInput(t *testing.T) {
+	dsdk := DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+
+	err := dsdk.ForceTransition(context.Background(), "flow123", Started, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	err = dsdk.ForceTransition(context.Background(), "flow123", Uninitialized, "reset")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	err = dsdk.ForceTransition(context.Background(), "flow123", DataFlowState(42), "unknown")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func Test_DataPlaneSDK_RetryStart(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	var received *DataAddress
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		Monitor:    defaultLogMonitor{},
+		onStart: func(ctx context.Context, flow *DataFlow, sdk *DataPlaneSDK, options *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			received = options.DataAddress
+			return &DataFlowResponseMessage{State: Starting}, nil
+		},
+	}
+	address := DataAddress{Properties: map[string]any{EndpointKey: "https://example.com"}}
+
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{
+		ID:                     "flow123",
+		State:                  Starting,
+		ErrorDetail:            "timeout",
+		DestinationDataAddress: address,
+	}, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Starting && df.ErrorDetail == ""
+	})).Return(nil)
+
+	response, err := dsdk.RetryStart(context.Background(), "flow123")
+
+	require.NoError(t, err)
+	assert.Equal(t, Starting, response.State)
+	assert.Equal(t, &address, received)
+}
+
+func Test_DataPlaneSDK_RetryStart_NotStarting(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", State: Started}, nil)
+
+	_, err := dsdk.RetryStart(context.Background(), "flow123")
+
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+}
+
+func Test_DataPlaneSDK_Delete(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+
+	store.EXPECT().Delete(mock.Anything, "flow123").Return(nil)
+
+	assert.NoError(t, dsdk.Delete(context.Background(), "flow123"))
+}
@@ -0,0 +1,134 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// WithMaxActiveFlowsPerCounterparty limits the number of flows with a counterparty that are not completed or
+// terminated. Further prepare and start messages of the counterparty are rejected with ErrLimitExceeded before the
+// processor runs.
+func WithMaxActiveFlowsPerCounterparty(limit int) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.maxActivePerCounterparty = limit
+	}
+}
+
+// WithMaxActiveFlowsPerAgreement limits the number of flows of an agreement that are not completed or terminated.
+// Further prepare and start messages for the agreement are rejected with ErrLimitExceeded before the processor runs.
+func WithMaxActiveFlowsPerAgreement(limit int) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.maxActivePerAgreement = limit
+	}
+}
+
+// admissionLimit is an active flow limit applying to the flows matching the query
+type admissionLimit struct {
+	key   string
+	limit int
+	query FlowQuery
+}
+
+// admitFlow verifies that a new flow may be created for the message. The flow must be admitted by its tenant and the
+// active flows of its participant, agreement and counterparty must be below the configured limits, so that rejected
+// flows never reach the processor. Returns the function creating the admitted flow in the store. If the store
+// implements AdmissionLocker, it locks the limits for the remainder of the transaction and checks them again, so that
+// instances sharing the store cannot exceed them concurrently. The locks are taken just before the flow is persisted
+// and are not held while the processor runs. Without AdmissionLocker, concurrent messages may exceed the limits.
+func (dsdk *DataPlaneSDK) admitFlow(ctx context.Context, message *DataFlowBaseMessage) (func(context.Context, *DataFlow) error, error) {
+	tenantLimit, err := dsdk.admitTenant(ctx, message.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// locks are acquired in a fixed order to prevent deadlocks between transactions
+	var limits []admissionLimit
+	if tenantLimit > 0 {
+		limits = append(limits, admissionLimit{
+			key:   "participant/" + message.ParticipantID,
+			limit: tenantLimit,
+			query: FlowQuery{States: activeStates, ParticipantID: message.ParticipantID},
+		})
+	}
+	if dsdk.maxActivePerAgreement > 0 {
+		limits = append(limits, admissionLimit{
+			key:   "agreement/" + message.AgreementID,
+			limit: dsdk.maxActivePerAgreement,
+			query: FlowQuery{States: activeStates, AgreementID: message.AgreementID},
+		})
+	}
+	if dsdk.maxActivePerCounterparty > 0 {
+		limits = append(limits, admissionLimit{
+			key:   "counterparty/" + message.CounterPartyID,
+			limit: dsdk.maxActivePerCounterparty,
+			query: FlowQuery{States: activeStates, CounterPartyID: message.CounterPartyID},
+		})
+	}
+	if err := dsdk.checkLimits(ctx, limits); err != nil {
+		return nil, err
+	}
+
+	locker, ok := dsdk.Store.(AdmissionLocker)
+	if !ok || len(limits) == 0 {
+		return dsdk.Store.Create, nil
+	}
+	return func(ctx context.Context, flow *DataFlow) error {
+		// flows terminated by the processor do not count against the limits
+		if slices.Contains(activeStates, flow.State) {
+			for _, limit := range limits {
+				if err := locker.LockAdmission(ctx, limit.key); err != nil {
+					return err
+				}
+			}
+			if err := dsdk.checkLimits(ctx, limits); err != nil {
+				return err
+			}
+		}
+		return dsdk.Store.Create(ctx, flow)
+	}, nil
+}
+
+// checkLimits returns ErrLimitExceeded if the active flows of a limit reached it
+func (dsdk *DataPlaneSDK) checkLimits(ctx context.Context, limits []admissionLimit) error {
+	for _, limit := range limits {
+		active, err := dsdk.countFlows(ctx, limit.query)
+		if err != nil {
+			return fmt.Errorf("counting active flows of %s: %w", limit.key, err)
+		}
+		if active >= limit.limit {
+			return fmt.Errorf("%w: %d active flows for %s", ErrLimitExceeded, active, limit.key)
+		}
+	}
+	return nil
+}
+
+// countFlows counts the flows matching the query with FlowCounter if the store implements it
+func (dsdk *DataPlaneSDK) countFlows(ctx context.Context, query FlowQuery) (int, error) {
+	if counter, ok := dsdk.Store.(FlowCounter); ok {
+		return counter.CountFlows(ctx, query)
+	}
+	query.Limit = 0
+	iterator, err := dsdk.Store.Query(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer iterator.Close()
+	count := 0
+	for iterator.Next() {
+		count++
+	}
+	return count, iterator.Error()
+}
@@ -0,0 +1,160 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type lockingStore struct {
+	*MockDataplaneStore
+	locked []string
+}
+
+func (s *lockingStore) LockAdmission(_ context.Context, key string) error {
+	s.locked = append(s.locked, key)
+	return nil
+}
+
+type countingStore struct {
+	*MockDataplaneStore
+	counts map[string]int
+}
+
+func (s *countingStore) CountFlows(_ context.Context, query FlowQuery) (int, error) {
+	return s.counts[query.AgreementID], nil
+}
+
+func Test_Admission_CounterpartyLimit(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	started := false
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithMaxActiveFlowsPerCounterparty(1),
+		WithStartProcessor(func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			started = true
+			return &DataFlowResponseMessage{State: Started}, nil
+		}),
+	)
+	require.NoError(t, err)
+	message := createStartMessage()
+
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, CounterPartyID: message.CounterPartyID}).
+		Return(newSliceIterator(&DataFlow{ID: "flow-1"}), nil)
+
+	_, err = sdk.Start(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.False(t, started, "the processor must not run for rejected flows")
+}
+
+func Test_Admission_AgreementLimit(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithMaxActiveFlowsPerAgreement(2),
+		WithMaxActiveFlowsPerCounterparty(2),
+	)
+	require.NoError(t, err)
+	message := createStartMessage()
+
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, AgreementID: message.AgreementID}).
+		Return(newSliceIterator(&DataFlow{ID: "flow-1"}), nil)
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, CounterPartyID: message.CounterPartyID}).
+		Return(newSliceIterator(&DataFlow{ID: "flow-1"}), nil)
+	store.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
+
+	_, err = sdk.Start(context.Background(), message)
+
+	assert.NoError(t, err, "flows below the limits must be admitted")
+}
+
+func Test_Admission_Locks(t *testing.T) {
+	store := &lockingStore{MockDataplaneStore: NewMockDataplaneStore(t)}
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithMaxActiveFlowsPerCounterparty(5),
+		WithMaxActiveFlowsPerAgreement(5),
+	)
+	require.NoError(t, err)
+	message := createPrepareMessage()
+
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+	store.EXPECT().Query(mock.Anything, mock.Anything).RunAndReturn(func(context.Context, FlowQuery) (Iterator[*DataFlow], error) {
+		return newSliceIterator(), nil
+	})
+	store.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
+
+	_, err = sdk.Prepare(context.Background(), message)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agreement/" + message.AgreementID, "counterparty/" + message.CounterPartyID}, store.locked)
+}
+
+func Test_Admission_LocksAfterProcessor(t *testing.T) {
+	store := &lockingStore{MockDataplaneStore: NewMockDataplaneStore(t)}
+	var lockedDuringProcessor []string
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithMaxActiveFlowsPerAgreement(1),
+		WithStartProcessor(func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			lockedDuringProcessor = append([]string{}, store.locked...)
+			return &DataFlowResponseMessage{State: Started}, nil
+		}),
+	)
+	require.NoError(t, err)
+	message := createStartMessage()
+
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+	// the first check rejects early, the second one runs under the lock
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, AgreementID: message.AgreementID}).
+		Return(newSliceIterator(), nil).Once()
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, AgreementID: message.AgreementID}).
+		Return(newSliceIterator(&DataFlow{ID: "concurrent"}), nil).Once()
+
+	_, err = sdk.Start(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrLimitExceeded, "flows admitted concurrently must be counted under the lock")
+	assert.Empty(t, lockedDuringProcessor, "the lock must not be held while the processor runs")
+	assert.Equal(t, []string{"agreement/" + message.AgreementID}, store.locked)
+}
+
+func Test_Admission_FlowCounter(t *testing.T) {
+	store := &countingStore{MockDataplaneStore: NewMockDataplaneStore(t)}
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithMaxActiveFlowsPerAgreement(2),
+	)
+	require.NoError(t, err)
+	message := createStartMessage()
+	store.counts = map[string]int{message.AgreementID: 2}
+
+	// no query expected
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+
+	_, err = sdk.Start(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+}
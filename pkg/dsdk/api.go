@@ -14,11 +14,13 @@ package dsdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -26,15 +28,114 @@ import (
 const contentType = "Content-Type"
 const jsonContentType = "application/json"
 
+const (
+	// StateParam selects the states of the flows returned by List. It may be repeated or contain comma-separated names.
+	StateParam = "state"
+	// LimitParam is the maximum number of flows returned by List.
+	LimitParam = "limit"
+	// ExtendedStatusMediaType requests the extended status representation via the Accept header.
+	ExtendedStatusMediaType = "application/vnd.dataplane.status.extended+json"
+	// StatusViewParam requests the extended status representation via a query parameter, e.g. ?view=extended.
+	StatusViewParam = "view"
+	// ExtendedStatusView is the StatusViewParam value selecting the extended status representation.
+	ExtendedStatusView = "extended"
+)
+
 type DataPlaneApi struct {
-	sdk *DataPlaneSDK
+	sdk                 *DataPlaneSDK
+	secretKeys          []string
+	callerResolver      CallerResolver
+	participantResolver ParticipantResolver
+	rateLimiter         RateLimiter
+	rateLimitKey        RateLimitKey
 }
 
-func NewDataPlaneApi(sdk *DataPlaneSDK) *DataPlaneApi {
-	return &DataPlaneApi{sdk: sdk}
+// CallerResolver returns the identity of the authenticated caller of a request, which is recorded in the transition
+// history. An empty string denotes an unknown caller.
+type CallerResolver func(r *http.Request) string
+
+// ParticipantResolver returns the participant an authenticated request acts for. Requests are scoped to the flows of
+// the participant; an empty string denotes an unscoped request.
+type ParticipantResolver func(r *http.Request) string
+
+// DataPlaneApiOption configures a DataPlaneApi instance
+type DataPlaneApiOption func(*DataPlaneApi)
+
+// WithSecretKeys replaces the property key fragments redacted from data addresses in status responses.
+func WithSecretKeys(keys ...string) DataPlaneApiOption {
+	return func(api *DataPlaneApi) {
+		api.secretKeys = keys
+	}
+}
+
+// WithCallerResolver configures how the caller identity recorded in the transition history is obtained.
+func WithCallerResolver(resolver CallerResolver) DataPlaneApiOption {
+	return func(api *DataPlaneApi) {
+		api.callerResolver = resolver
+	}
+}
+
+// WithParticipantResolver scopes requests to the flows of the participant they are authenticated for, so that a
+// participant hosted by a multi-tenant data plane cannot access the flows of another.
+func WithParticipantResolver(resolver ParticipantResolver) DataPlaneApiOption {
+	return func(api *DataPlaneApi) {
+		api.participantResolver = resolver
+	}
+}
+
+func NewDataPlaneApi(sdk *DataPlaneSDK, options ...DataPlaneApiOption) *DataPlaneApi {
+	api := &DataPlaneApi{sdk: sdk, secretKeys: DefaultSecretKeys}
+	for _, opt := range options {
+		opt(api)
+	}
+	return api
+}
+
+// Handler returns a handler serving the signaling, status and health endpoints:
+//
+//	POST /dataflows/prepare
+//	POST /dataflows/start
+//	POST /dataflows/{id}/started
+//	POST /dataflows/{id}/suspend
+//	POST /dataflows/{id}/resume
+//	POST /dataflows/{id}/terminate
+//	POST /dataflows/{id}/completed
+//	GET  /dataflows/{id}/status
+//	GET  /health/live
+//	GET  /health/ready
+//
+// Listing, history and bulk operations are operator endpoints served by AdminApi.Handler.
+func (d *DataPlaneApi) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /dataflows/prepare", d.Prepare)
+	mux.HandleFunc("POST /dataflows/start", d.Start)
+	mux.HandleFunc("POST /dataflows/{id}/started", func(w http.ResponseWriter, r *http.Request) {
+		d.StartById(w, r, r.PathValue("id"))
+	})
+	mux.HandleFunc("POST /dataflows/{id}/suspend", func(w http.ResponseWriter, r *http.Request) {
+		d.Suspend(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("POST /dataflows/{id}/resume", func(w http.ResponseWriter, r *http.Request) {
+		d.Resume(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("POST /dataflows/{id}/terminate", func(w http.ResponseWriter, r *http.Request) {
+		d.Terminate(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("POST /dataflows/{id}/completed", func(w http.ResponseWriter, r *http.Request) {
+		d.Complete(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("GET /dataflows/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		d.Status(r.PathValue("id"), w, r)
+	})
+	mux.HandleFunc("GET /health/live", d.Liveness)
+	mux.HandleFunc("GET /health/ready", d.Readiness)
+	return mux
 }
 
 func (d *DataPlaneApi) Prepare(w http.ResponseWriter, r *http.Request) {
+	if !d.allowRequest(w, r) {
+		return
+	}
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusBadRequest)
 		return
@@ -42,17 +143,18 @@ func (d *DataPlaneApi) Prepare(w http.ResponseWriter, r *http.Request) {
 	var prepareMessage DataFlowPrepareMessage
 
 	if err := json.NewDecoder(r.Body).Decode(&prepareMessage); err != nil {
-		d.decodingError(w, err)
+		d.decodingError(w, r, err)
 		return
 	}
 
 	if err := prepareMessage.Validate(); err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
+		return
 	}
 
-	response, err := d.sdk.Prepare(r.Context(), prepareMessage)
+	response, err := d.sdk.Prepare(d.callerContext(r), prepareMessage)
 	if err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
 
@@ -66,6 +168,9 @@ func (d *DataPlaneApi) Prepare(w http.ResponseWriter, r *http.Request) {
 }
 
 func (d *DataPlaneApi) Start(w http.ResponseWriter, r *http.Request) {
+	if !d.allowRequest(w, r) {
+		return
+	}
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusBadRequest)
 		return
@@ -73,18 +178,18 @@ func (d *DataPlaneApi) Start(w http.ResponseWriter, r *http.Request) {
 	var startMessage DataFlowStartMessage
 
 	if err := json.NewDecoder(r.Body).Decode(&startMessage); err != nil {
-		d.decodingError(w, err)
+		d.decodingError(w, r, err)
 		return
 	}
 
 	if err := startMessage.Validate(); err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
 
-	response, err := d.sdk.Start(r.Context(), startMessage)
+	response, err := d.sdk.Start(d.callerContext(r), startMessage)
 	if err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
 
@@ -100,6 +205,9 @@ func (d *DataPlaneApi) Start(w http.ResponseWriter, r *http.Request) {
 }
 
 func (d *DataPlaneApi) StartById(w http.ResponseWriter, r *http.Request, id string) {
+	if !d.allowRequest(w, r) {
+		return
+	}
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusBadRequest)
 		return
@@ -107,18 +215,18 @@ func (d *DataPlaneApi) StartById(w http.ResponseWriter, r *http.Request, id stri
 	var startMessage DataFlowStartedNotificationMessage
 
 	if err := json.NewDecoder(r.Body).Decode(&startMessage); err != nil {
-		d.decodingError(w, err)
+		d.decodingError(w, r, err)
 		return
 	}
 
 	if err := startMessage.Validate(); err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
 
-	response, err := d.sdk.StartById(r.Context(), id, startMessage)
+	response, err := d.sdk.StartById(d.callerContext(r), id, startMessage)
 	if err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
 
@@ -133,11 +241,14 @@ func (d *DataPlaneApi) StartById(w http.ResponseWriter, r *http.Request, id stri
 }
 
 func (d *DataPlaneApi) Terminate(id string, w http.ResponseWriter, r *http.Request) {
-	reason := ""
+	if !d.allowRequest(w, r) {
+		return
+	}
+	reason, messageID := "", ""
 	// Peek into the body
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
-		d.decodingError(w, err)
+		d.decodingError(w, r, err)
 		return
 	}
 	// if a body was sent, parse it, read the reason
@@ -146,18 +257,18 @@ func (d *DataPlaneApi) Terminate(id string, w http.ResponseWriter, r *http.Reque
 		var terminateMessage DataFlowTransitionMessage
 
 		if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(&terminateMessage); err != nil {
-			d.decodingError(w, err)
+			d.decodingError(w, r, err)
 			return
 		}
 		if err := terminateMessage.Validate(); err != nil {
-			d.handleError(err, w)
+			d.handleError(err, w, r)
 			return
 		}
-		reason = terminateMessage.Reason
+		reason, messageID = terminateMessage.Reason, terminateMessage.MessageID
 	}
-	terminateError := d.sdk.Terminate(r.Context(), id, reason)
+	terminateError := d.sdk.Terminate(ContextWithMessageID(d.callerContext(r), messageID), id, reason)
 	if terminateError != nil {
-		d.handleError(terminateError, w)
+		d.handleError(terminateError, w, r)
 		return
 	}
 
@@ -166,12 +277,15 @@ func (d *DataPlaneApi) Terminate(id string, w http.ResponseWriter, r *http.Reque
 }
 
 func (d *DataPlaneApi) Suspend(id string, w http.ResponseWriter, r *http.Request) {
+	if !d.allowRequest(w, r) {
+		return
+	}
 
-	reason := ""
+	reason, messageID := "", ""
 	// Peek into the body
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
-		d.decodingError(w, err)
+		d.decodingError(w, r, err)
 		return
 	}
 	// if a body was sent, parse it, read the reason
@@ -179,25 +293,136 @@ func (d *DataPlaneApi) Suspend(id string, w http.ResponseWriter, r *http.Request
 		var suspendMessage DataFlowTransitionMessage
 
 		if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(&suspendMessage); err != nil {
-			d.decodingError(w, err)
+			d.decodingError(w, r, err)
 			return
 		}
 		if err := suspendMessage.Validate(); err != nil {
-			d.handleError(err, w)
+			d.handleError(err, w, r)
 			return
 		}
-		reason = suspendMessage.Reason
+		reason, messageID = suspendMessage.Reason, suspendMessage.MessageID
 	}
 
-	suspensionError := d.sdk.Suspend(r.Context(), id, reason)
+	suspensionError := d.sdk.Suspend(ContextWithMessageID(d.callerContext(r), messageID), id, reason)
 	if suspensionError != nil {
-		d.handleError(suspensionError, w)
+		d.handleError(suspensionError, w, r)
+		return
+	}
+
+	w.Header().Set(contentType, jsonContentType)
+	w.WriteHeader(http.StatusOK)
+
+}
+
+// Resume restarts a suspended data flow.
+func (d *DataPlaneApi) Resume(id string, w http.ResponseWriter, r *http.Request) {
+	if !d.allowRequest(w, r) {
+		return
+	}
+	messageID := ""
+	bodyBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		d.decodingError(w, r, err)
+		return
+	}
+	if len(bodyBytes) > 0 {
+		var resumeMessage DataFlowTransitionMessage
+		if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(&resumeMessage); err != nil {
+			d.decodingError(w, r, err)
+			return
+		}
+		if err := resumeMessage.Validate(); err != nil {
+			d.handleError(err, w, r)
+			return
+		}
+		messageID = resumeMessage.MessageID
+	}
+
+	if err := d.sdk.Resume(ContextWithMessageID(d.callerContext(r), messageID), id); err != nil {
+		d.handleError(err, w, r)
 		return
 	}
 
 	w.Header().Set(contentType, jsonContentType)
 	w.WriteHeader(http.StatusOK)
+}
+
+// bulk applies a suspend, resume or terminate action to the data flows selected by agreement, counterparty or dataset
+// and reports the outcome for each flow. It is served by AdminApi.Bulk, which authorizes the operator.
+func (d *DataPlaneApi) bulk(w http.ResponseWriter, r *http.Request) {
+	if !d.allowRequest(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid request method", http.StatusBadRequest)
+		return
+	}
+	var bulkMessage DataFlowBulkMessage
+	if err := json.NewDecoder(r.Body).Decode(&bulkMessage); err != nil {
+		d.decodingError(w, r, err)
+		return
+	}
+	if err := bulkMessage.Validate(); err != nil {
+		d.handleError(err, w, r)
+		return
+	}
 
+	results, err := d.sdk.Bulk(d.callerContext(r), bulkMessage.Action, bulkMessage.Selector, bulkMessage.Reason)
+	if err != nil {
+		d.handleError(err, w, r)
+		return
+	}
+	d.writeResponse(w, http.StatusOK, DataFlowBulkResponseMessage{DataFlows: results})
+}
+
+// list returns the status of the data flows selected by the StateParam and LimitParam query parameters. It is served by
+// AdminApi.List, which authorizes the operator.
+func (d *DataPlaneApi) list(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusBadRequest)
+		return
+	}
+	query, err := parseFlowQuery(r)
+	if err != nil {
+		d.handleError(err, w, r)
+		return
+	}
+	flows, err := d.sdk.List(d.callerContext(r), query)
+	if err != nil {
+		d.handleError(err, w, r)
+		return
+	}
+	response := DataFlowListResponseMessage{DataFlows: make([]DataFlowStatusResponseMessage, 0, len(flows))}
+	for _, flow := range flows {
+		response.DataFlows = append(response.DataFlows, DataFlowStatusResponseMessage{
+			State:      flow.State,
+			DataFlowID: flow.ID,
+			Progress:   progressOf(flow),
+		})
+	}
+	d.writeResponse(w, http.StatusOK, response)
+}
+
+// parseFlowQuery creates a query from the list request parameters
+func parseFlowQuery(r *http.Request) (FlowQuery, error) {
+	var query FlowQuery
+	for _, value := range r.URL.Query()[StateParam] {
+		for _, name := range strings.Split(value, ",") {
+			state, err := ParseDataFlowState(strings.TrimSpace(name))
+			if err != nil {
+				return FlowQuery{}, err
+			}
+			query.States = append(query.States, state)
+		}
+	}
+	if limit := r.URL.Query().Get(LimitParam); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 0 {
+			return FlowQuery{}, fmt.Errorf("%w: invalid limit %q", ErrInvalidInput, limit)
+		}
+		query.Limit = value
+	}
+	return query, nil
 }
 
 func (d *DataPlaneApi) Status(processID string, w http.ResponseWriter, r *http.Request) {
@@ -205,58 +430,171 @@ func (d *DataPlaneApi) Status(processID string, w http.ResponseWriter, r *http.R
 		http.Error(w, "Invalid request method", http.StatusBadRequest)
 		return
 	}
-	dataFlow, err := d.sdk.Status(r.Context(), processID)
+	dataFlow, err := d.sdk.Status(d.callerContext(r), processID)
 	if err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
-	w.Header().Set(contentType, jsonContentType)
+	w.Header().Set("Vary", "Accept")
 	response := DataFlowStatusResponseMessage{
 		State:      dataFlow.State,
 		DataFlowID: dataFlow.ID,
+		Progress:   progressOf(dataFlow),
 	}
-	d.writeResponse(w, http.StatusOK, response)
+	if !extendedStatusRequested(r) {
+		d.writeResponse(w, http.StatusOK, response)
+		return
+	}
+	d.writeResponse(w, http.StatusOK, DataFlowExtendedStatusResponseMessage{
+		DataFlowStatusResponseMessage: response,
+		Consumer:                      dataFlow.Consumer,
+		AgreementID:                   dataFlow.AgreementID,
+		DatasetID:                     dataFlow.DatasetID,
+		ParticipantID:                 dataFlow.ParticipantID,
+		CounterPartyID:                dataFlow.CounterPartyID,
+		DataspaceContext:              dataFlow.DataspaceContext,
+		TransferType:                  dataFlow.TransferType,
+		StateCount:                    dataFlow.StateCount,
+		StateTimestamp:                dataFlow.StateTimestamp,
+		ErrorDetail:                   dataFlow.ErrorDetail,
+		CreatedAt:                     dataFlow.CreatedAt,
+		UpdatedAt:                     dataFlow.UpdatedAt,
+		SourceDataAddress:             redactedAddress(dataFlow.SourceDataAddress, d.secretKeys),
+		DestinationDataAddress:        redactedAddress(dataFlow.DestinationDataAddress, d.secretKeys),
+	})
+}
+
+// extendedStatusRequested returns true if the client asked for the extended status via query parameter or Accept header.
+func extendedStatusRequested(r *http.Request) bool {
+	if r.URL.Query().Get(StatusViewParam) == ExtendedStatusView {
+		return true
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			if strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0]) == ExtendedStatusMediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// progressOf returns the progress of the flow, or nil if no progress has been reported
+func progressOf(flow *DataFlow) *TransferProgress {
+	if flow.Progress == (TransferProgress{}) {
+		return nil
+	}
+	return &flow.Progress
+}
+
+func redactedAddress(address DataAddress, secretKeys []string) *DataAddress {
+	if len(address.Properties) == 0 {
+		return nil
+	}
+	return address.Redacted(secretKeys)
+}
+
+// history returns the transition history of a data flow. It is served by AdminApi.History, which authorizes the
+// operator.
+func (d *DataPlaneApi) history(processID string, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid request method", http.StatusBadRequest)
+		return
+	}
+	history, err := d.sdk.History(d.callerContext(r), processID)
+	if err != nil {
+		d.handleError(err, w, r)
+		return
+	}
+	if history == nil {
+		history = []TransitionRecord{}
+	}
+	d.writeResponse(w, http.StatusOK, DataFlowHistoryResponseMessage{DataFlowID: processID, Transitions: history})
 }
 
 func (d *DataPlaneApi) Complete(processID string, w http.ResponseWriter, r *http.Request) {
+	if !d.allowRequest(w, r) {
+		return
+	}
 	if r.Method != http.MethodPost {
 		http.Error(w, "Invalid request method", http.StatusBadRequest)
 		return
 	}
-	err := d.sdk.Complete(r.Context(), processID)
+	err := d.sdk.Complete(d.callerContext(r), processID)
 	if err != nil {
-		d.handleError(err, w)
+		d.handleError(err, w, r)
 		return
 	}
 	d.writeResponse(w, http.StatusOK, nil)
 }
 
-func (d *DataPlaneApi) decodingError(w http.ResponseWriter, err error) {
-	id := uuid.NewString()
-	d.sdk.Monitor.Printf("Error decoding flow [%s]: %v\n", id, err)
-	d.writeResponse(w, http.StatusBadRequest, &DataFlowResponseMessage{Error: fmt.Sprintf("Failed to decode request body [%s]", id)})
+// Liveness writes the liveness report of the SDK with status 200 if it is up and 503 otherwise.
+func (d *DataPlaneApi) Liveness(w http.ResponseWriter, r *http.Request) {
+	d.writeHealth(w, d.sdk.Liveness(r.Context()))
 }
 
-// handleError writes an error message to the HTTP response that indicates "any other" error, such as 409, 500, etc.
-func (d *DataPlaneApi) handleError(err error, w http.ResponseWriter) {
-
-	switch {
-	case errors.Is(err, ErrValidation), errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrInvalidInput):
-		d.badRequest(err.Error(), w)
-	case errors.Is(err, ErrNotFound):
-		d.writeResponse(w, http.StatusNotFound, &DataFlowResponseMessage{Error: err.Error()})
-	case errors.Is(err, ErrConflict):
-		message := fmt.Sprintf("%s", err)
-		d.writeResponse(w, http.StatusConflict, &DataFlowResponseMessage{Error: message})
-	default:
-		message := fmt.Sprintf("Error processing flow: %s", err)
-		d.sdk.Monitor.Println(message)
-		d.writeResponse(w, http.StatusInternalServerError, &DataFlowResponseMessage{Error: message})
+// Readiness writes the readiness report of the SDK with status 200 if it is up and 503 otherwise.
+func (d *DataPlaneApi) Readiness(w http.ResponseWriter, r *http.Request) {
+	d.writeHealth(w, d.sdk.Readiness(r.Context()))
+}
+
+func (d *DataPlaneApi) writeHealth(w http.ResponseWriter, report HealthReport) {
+	code := http.StatusOK
+	if report.Status != HealthUp {
+		code = http.StatusServiceUnavailable
+	}
+	d.writeResponse(w, code, report)
+}
+
+// callerContext returns the request context with the caller identity and participant attached
+func (d *DataPlaneApi) callerContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if d.callerResolver != nil {
+		ctx = ContextWithCaller(ctx, d.callerResolver(r))
+	}
+	if d.participantResolver != nil {
+		ctx = ContextWithParticipant(ctx, d.participantResolver(r))
+	}
+	return ctx
+}
+
+func (d *DataPlaneApi) decodingError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblemDetails(fmt.Errorf("%w: failed to decode request body", ErrInvalidInput))
+	d.sdk.Monitor.Printf("Error decoding flow [%s]: %v\n", correlationID(w, r), err)
+	d.writeProblem(w, r, problem)
+}
+
+// handleError writes an RFC 7807 problem response for the error, such as 400, 404, 409, 500, etc.
+func (d *DataPlaneApi) handleError(err error, w http.ResponseWriter, r *http.Request) {
+	problem := NewProblemDetails(err)
+	if problem.Status == http.StatusInternalServerError {
+		d.sdk.Monitor.Printf("%s [%s]\n", problem.Detail, correlationID(w, r))
+	}
+	d.writeProblem(w, r, problem)
+}
+
+func (d *DataPlaneApi) writeProblem(w http.ResponseWriter, r *http.Request, problem *ProblemDetails) {
+	problem.CorrelationID = correlationID(w, r)
+	problem.Instance = r.URL.Path
+	w.Header().Set(contentType, ProblemContentType)
+	w.WriteHeader(problem.Status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		d.sdk.Monitor.Printf("Error encoding problem response [%s]: %v\n", problem.CorrelationID, err)
 	}
 }
 
-func (d *DataPlaneApi) badRequest(errMsg string, w http.ResponseWriter) {
-	d.writeResponse(w, http.StatusBadRequest, &DataFlowResponseMessage{Error: errMsg})
+// correlationID returns the correlation ID of the request, generating one if the client did not send it. The ID is
+// echoed in the response headers.
+func correlationID(w http.ResponseWriter, r *http.Request) string {
+	if id := w.Header().Get(CorrelationIDHeader); id != "" {
+		return id
+	}
+	id := r.Header.Get(CorrelationIDHeader)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	w.Header().Set(CorrelationIDHeader, id)
+	return id
 }
 
 func (d *DataPlaneApi) writeResponse(w http.ResponseWriter, code int, response any) {
@@ -0,0 +1,323 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneApi_Status(t *testing.T) {
+	api := NewDataPlaneApi(newStatusSdk(t, Started))
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/status", nil)
+	rec := httptest.NewRecorder()
+	api.Status("flow123", rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "flow123", body["dataFlowID"])
+	assert.Equal(t, float64(Started), body["state"])
+	assert.NotContains(t, body, "errorDetail")
+	assert.NotContains(t, body, "sourceDataAddress")
+	assert.NotContains(t, body, "progress", "progress is omitted until a transfer reports it")
+}
+
+func Test_DataPlaneApi_Status_Extended(t *testing.T) {
+	tests := map[string]func(r *http.Request){
+		"query parameter": func(r *http.Request) {
+			r.URL.RawQuery = StatusViewParam + "=" + ExtendedStatusView
+		},
+		"accept header": func(r *http.Request) {
+			r.Header.Set("Accept", "application/json;q=0.5, "+ExtendedStatusMediaType)
+		},
+	}
+
+	for name, negotiate := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := NewDataPlaneApi(newStatusSdk(t, Terminated))
+
+			req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/status", nil)
+			negotiate(req)
+			rec := httptest.NewRecorder()
+			api.Status("flow123", rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			var response DataFlowExtendedStatusResponseMessage
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, "flow123", response.DataFlowID)
+			assert.Equal(t, Terminated, response.State)
+			assert.Equal(t, "agreement123", response.AgreementID)
+			assert.Equal(t, "failed", response.ErrorDetail)
+			assert.Equal(t, uint(3), response.StateCount)
+			assert.Equal(t, int64(1234), response.StateTimestamp)
+			assert.Equal(t, Push, response.TransferType.FlowType)
+			assert.True(t, response.Consumer)
+			require.NotNil(t, response.SourceDataAddress)
+			assert.Equal(t, "https://example.com", response.SourceDataAddress.Properties[EndpointKey])
+			assert.Equal(t, RedactedValue, response.SourceDataAddress.Properties["token"])
+			assert.Nil(t, response.DestinationDataAddress)
+		})
+	}
+}
+
+func Test_DataPlaneApi_Status_CustomSecretKeys(t *testing.T) {
+	api := NewDataPlaneApi(newStatusSdk(t, Terminated), WithSecretKeys("endpoint"))
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/status?view=extended", nil)
+	rec := httptest.NewRecorder()
+	api.Status("flow123", rec, req)
+
+	var response DataFlowExtendedStatusResponseMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, RedactedValue, response.SourceDataAddress.Properties[EndpointKey])
+	assert.Equal(t, "secret", response.SourceDataAddress.Properties["token"])
+}
+
+func Test_DataPlaneApi_List(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	query := FlowQuery{States: []DataFlowState{Started, Suspended}, Limit: 10}
+	store.EXPECT().Query(mock.Anything, query).Return(newSliceIterator(
+		&DataFlow{ID: "flow1", State: Started, Progress: TransferProgress{BytesTransferred: 42}},
+		&DataFlow{ID: "flow2", State: Suspended},
+	), nil)
+	api := NewDataPlaneApi(&DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows?state=started,SUSPENDED&limit=10", nil)
+	rec := httptest.NewRecorder()
+	api.list(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body DataFlowListResponseMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.DataFlows, 2)
+	assert.Equal(t, "flow1", body.DataFlows[0].DataFlowID)
+	assert.Equal(t, int64(42), body.DataFlows[0].Progress.BytesTransferred)
+	assert.Equal(t, Suspended, body.DataFlows[1].State)
+}
+
+func Test_DataPlaneApi_List_InvalidParameters(t *testing.T) {
+	for name, params := range map[string]string{
+		"unknown state":  "state=RUNNING",
+		"invalid limit":  "limit=ten",
+		"negative limit": "limit=-1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			api := NewDataPlaneApi(&DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}})
+
+			req := httptest.NewRequest(http.MethodGet, "/dataflows?"+params, nil)
+			rec := httptest.NewRecorder()
+			api.list(rec, req)
+
+			problem := decodeProblem(t, rec, http.StatusBadRequest)
+			assert.Equal(t, ProblemTypeInvalidInput, problem.Type)
+		})
+	}
+}
+
+func Test_DataPlaneApi_Resume(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", State: Suspended}, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Started
+	})).Return(nil)
+	api := NewDataPlaneApi(&DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		Monitor:    defaultLogMonitor{},
+		onResume:   func(context.Context, *DataFlow) error { return nil },
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/flow123/resume", nil)
+	rec := httptest.NewRecorder()
+	api.Resume("flow123", rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func Test_DataPlaneApi_Handler(t *testing.T) {
+	sdk, store := newLifecycleSdk(t)
+	flow := &DataFlow{ID: "flow123", State: Started}
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(flow, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Completed
+	})).Return(nil)
+	handler := NewDataPlaneApi(sdk).Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/dataflows/flow123/completed", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// operator endpoints are only served by the admin API
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/dataflows", nil),
+		httptest.NewRequest(http.MethodGet, "/dataflows/flow123/history", nil),
+		httptest.NewRequest(http.MethodPost, "/dataflows/bulk", strings.NewReader(`{}`)),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Contains(t, []int{http.StatusNotFound, http.StatusMethodNotAllowed}, rec.Code, req.URL.Path)
+	}
+}
+
+func Test_DataPlaneApi_Problem_Validation(t *testing.T) {
+	api := NewDataPlaneApi(&DataPlaneSDK{Monitor: defaultLogMonitor{}})
+
+	message := createStartMessage()
+	message.CounterPartyID = ""
+	message.TransferType.FlowType = ""
+	body, _ := json.Marshal(message)
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/start", bytes.NewReader(body))
+	req.Header.Set(CorrelationIDHeader, "correlation123")
+	rec := httptest.NewRecorder()
+	api.Start(rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusBadRequest)
+	assert.Equal(t, ProblemTypeValidation, problem.Type)
+	assert.Equal(t, "correlation123", problem.CorrelationID)
+	assert.Equal(t, "correlation123", rec.Header().Get(CorrelationIDHeader))
+	assert.Equal(t, "/dataflows/start", problem.Instance)
+	assert.ElementsMatch(t, []Violation{
+		{Field: "counterPartyID", Message: "failed on the 'required' constraint"},
+		{Field: "transferType.flowType", Message: "failed on the 'required' constraint"},
+	}, problem.Violations)
+}
+
+func Test_DataPlaneApi_Problem_Prepare_Validation(t *testing.T) {
+	api := NewDataPlaneApi(&DataPlaneSDK{Monitor: defaultLogMonitor{}})
+
+	message := createPrepareMessage()
+	message.ProcessID = ""
+	body, _ := json.Marshal(message)
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/prepare", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.Prepare(rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusBadRequest)
+	assert.Equal(t, ProblemTypeValidation, problem.Type)
+	assert.NotEmpty(t, problem.CorrelationID)
+	assert.Equal(t, []Violation{{Field: "processID", Message: "failed on the 'required' constraint"}}, problem.Violations)
+}
+
+func Test_DataPlaneApi_Problem_Decoding(t *testing.T) {
+	api := NewDataPlaneApi(&DataPlaneSDK{Monitor: defaultLogMonitor{}})
+
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/start", bytes.NewReader([]byte("{invalid")))
+	rec := httptest.NewRecorder()
+	api.Start(rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusBadRequest)
+	assert.Equal(t, ProblemTypeInvalidInput, problem.Type)
+}
+
+func Test_DataPlaneApi_Problem_NotFound(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(nil, ErrNotFound)
+	api := NewDataPlaneApi(&DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/status", nil)
+	rec := httptest.NewRecorder()
+	api.Status("flow123", rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusNotFound)
+	assert.Equal(t, ProblemTypeNotFound, problem.Type)
+	assert.Empty(t, problem.State)
+}
+
+func Test_DataPlaneApi_Problem_ConflictWithState(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	store.EXPECT().FindById(mock.Anything, "process123").Return(&DataFlow{ID: "process123", State: Started}, nil)
+	api := NewDataPlaneApi(&DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}})
+
+	body, _ := json.Marshal(createPrepareMessage())
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/prepare", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.Prepare(rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusConflict)
+	assert.Equal(t, ProblemTypeConflict, problem.Type)
+	assert.Equal(t, "STARTED", problem.State)
+}
+
+func Test_DataPlaneApi_Problem_InvalidTransition(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", State: Prepared}, nil)
+	api := NewDataPlaneApi(&DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		Monitor:    defaultLogMonitor{},
+		onComplete: func(context.Context, *DataFlow) error { return nil },
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/flow123/completed", nil)
+	rec := httptest.NewRecorder()
+	api.Complete("flow123", rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusBadRequest)
+	assert.Equal(t, ProblemTypeInvalidTransition, problem.Type)
+	assert.Equal(t, "PREPARED", problem.State)
+}
+
+func Test_DataPlaneApi_Problem_Internal(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(nil, errors.New("database down"))
+	api := NewDataPlaneApi(&DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/status", nil)
+	rec := httptest.NewRecorder()
+	api.Status("flow123", rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusInternalServerError)
+	assert.Equal(t, ProblemTypeInternal, problem.Type)
+}
+
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder, status int) ProblemDetails {
+	t.Helper()
+	require.Equal(t, status, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	var problem ProblemDetails
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, status, problem.Status)
+	return problem
+}
+
+func newStatusSdk(t *testing.T, state DataFlowState) *DataPlaneSDK {
+	store := NewMockDataplaneStore(t)
+	flow := &DataFlow{
+		ID:             "flow123",
+		Consumer:       true,
+		AgreementID:    "agreement123",
+		TransferType:   TransferType{DestinationType: "test", FlowType: Push},
+		State:          state,
+		StateCount:     3,
+		StateTimestamp: 1234,
+		ErrorDetail:    "failed",
+		SourceDataAddress: DataAddress{Properties: map[string]any{
+			EndpointKey: "https://example.com",
+			"token":     "secret",
+		}},
+	}
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(flow, nil)
+	return &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+}
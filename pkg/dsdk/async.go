@@ -0,0 +1,94 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NotifyPrepared completes the asynchronous preparation of a flow for which the prepare processor returned PREPARING.
+// The flow is transitioned to PREPARED, the data address, if not nil, is stored as the destination address of the flow
+// and the control plane is notified with a prepared callback. Calls for flows that are already prepared are ignored.
+func (dsdk *DataPlaneSDK) NotifyPrepared(ctx context.Context, processID string, address *DataAddress) error {
+	return dsdk.completeAsync(ctx, processID, Preparing, Prepared, address)
+}
+
+// NotifyStarted completes the asynchronous start of a flow for which the start processor returned STARTING. The flow is
+// transitioned to STARTED, the data address, if not nil, is stored as the source address of provider and the
+// destination address of consumer flows, and the control plane is notified with a started callback. Workers registered
+// while the flow was starting are run. Calls for flows that are already started are ignored.
+func (dsdk *DataPlaneSDK) NotifyStarted(ctx context.Context, processID string, address *DataAddress) error {
+	return dsdk.completeAsync(ctx, processID, Starting, Started, address)
+}
+
+func (dsdk *DataPlaneSDK) completeAsync(ctx context.Context, processID string, pending DataFlowState, target DataFlowState, address *DataAddress) error {
+	if processID == "" {
+		return errors.New("processID cannot be empty")
+	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return fmt.Errorf("completing %s of data flow %s: %w", pending, processID, err)
+		}
+		op.attribute(flow)
+
+		if flow.State == target { // de-duplication
+			return nil
+		}
+		if flow.State != pending {
+			return &FlowStateError{
+				Err:     ErrInvalidTransition,
+				FlowID:  flow.ID,
+				State:   flow.State,
+				Message: fmt.Sprintf("data flow %s is not in %s state but in %s", flow.ID, pending, flow.State),
+			}
+		}
+
+		if target == Prepared {
+			err = flow.TransitionToPrepared()
+		} else {
+			err = flow.TransitionToStarted()
+		}
+		if err != nil {
+			return err
+		}
+		if address != nil {
+			if flow.Consumer {
+				flow.DestinationDataAddress = *address
+			} else {
+				flow.SourceDataAddress = *address
+			}
+		}
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return fmt.Errorf("completing %s of data flow %s: %w", pending, flow.ID, err)
+		}
+
+		message := &DataFlowResponseMessage{State: target, DataAddress: address}
+		if target == Started {
+			op.afterCommit(func() { dsdk.resumeFlowWorker(flow) })
+		}
+		op.afterCommit(func() {
+			dsdk.notifyAsync(flow, completionEvent(target), message)
+		})
+		return nil
+	})
+}
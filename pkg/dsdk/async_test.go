@@ -0,0 +1,103 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneSDK_NotifyPrepared(t *testing.T) {
+	notifier := newRecordingNotifier()
+	sdk, store := newLifecycleSdk(t, WithCallbackNotifier(notifier))
+	flow := &DataFlow{ID: "flow-1", Consumer: true, State: Preparing}
+	address, err := NewDataAddressBuilder().Property(EndpointKey, "https://consumer.example/inbox").Build()
+	require.NoError(t, err)
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(flow, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Prepared
+	})).Return(nil)
+
+	require.NoError(t, sdk.NotifyPrepared(context.Background(), "flow-1", address))
+
+	event := notifier.await(t)
+	assert.Equal(t, PreparedEvent, event.name)
+	assert.Equal(t, Prepared, event.message.State)
+	assert.Equal(t, address, event.message.DataAddress)
+	assert.Equal(t, *address, flow.DestinationDataAddress)
+}
+
+func Test_DataPlaneSDK_NotifyStarted_RunsWorker(t *testing.T) {
+	notifier := newRecordingNotifier()
+	running := make(chan struct{}, 1)
+	sdk, store := newLifecycleSdk(t,
+		WithCallbackNotifier(notifier),
+		WithStartProcessor(func(ctx context.Context, flow *DataFlow, sdk *DataPlaneSDK, _ *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			sdk.RegisterFlowWorker(ctx, flow, func(ctx context.Context, _ *DataFlow) error {
+				running <- struct{}{}
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			return &DataFlowResponseMessage{State: Starting}, nil
+		}))
+	var starting *DataFlow
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(mock.Anything, mock.Anything).Run(func(_ context.Context, df *DataFlow) {
+		starting = df
+	}).Return(nil)
+
+	response, err := sdk.Start(context.Background(), createStartMessage())
+	require.NoError(t, err)
+	require.Equal(t, Starting, response.State)
+	assert.Empty(t, running, "the worker must not run before the flow has been started")
+
+	address, err := NewDataAddressBuilder().Property(EndpointKey, "https://provider.example/data").Build()
+	require.NoError(t, err)
+	store.EXPECT().FindById(mock.Anything, "process123").Return(starting, nil)
+	store.EXPECT().Save(mock.Anything, starting).Return(nil)
+
+	require.NoError(t, sdk.NotifyStarted(context.Background(), "process123", address))
+
+	awaitSignal(t, running, "worker not run after the flow has been started")
+	event := notifier.await(t)
+	assert.Equal(t, StartedEvent, event.name)
+	assert.Equal(t, address, event.message.DataAddress)
+	assert.Equal(t, Started, starting.State)
+	assert.Equal(t, *address, starting.SourceDataAddress)
+	require.NoError(t, sdk.Shutdown(context.Background()))
+}
+
+func Test_DataPlaneSDK_NotifyStarted_InvalidState(t *testing.T) {
+	sdk, store := newLifecycleSdk(t)
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(&DataFlow{ID: "flow-1", State: Prepared}, nil)
+
+	err := sdk.NotifyStarted(context.Background(), "flow-1", nil)
+
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+}
+
+func Test_DataPlaneSDK_NotifyPrepared_Duplicate(t *testing.T) {
+	notifier := newRecordingNotifier()
+	sdk, store := newLifecycleSdk(t, WithCallbackNotifier(notifier))
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(&DataFlow{ID: "flow-1", Consumer: true, State: Prepared}, nil)
+
+	// no save and no notification expected
+	require.NoError(t, sdk.NotifyPrepared(context.Background(), "flow-1", nil))
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+	assert.Empty(t, notifier.events)
+}
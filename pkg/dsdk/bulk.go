@@ -0,0 +1,106 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// BulkAction is the operation applied to the flows selected by a bulk request
+type BulkAction string
+
+const (
+	BulkSuspend   BulkAction = "suspend"
+	BulkResume    BulkAction = "resume"
+	BulkTerminate BulkAction = "terminate"
+)
+
+// FlowSelector selects the flows of a bulk operation. Flows must match all fields that are set; at least one field is
+// required.
+type FlowSelector struct {
+	AgreementID    string `json:"agreementID,omitempty"`
+	CounterPartyID string `json:"counterPartyID,omitempty"`
+	DatasetID      string `json:"datasetID,omitempty"`
+}
+
+// BulkResult is the outcome of a bulk operation for a single flow. Error is empty if the operation succeeded.
+type BulkResult struct {
+	DataFlowID string `json:"dataFlowID"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BulkSuspend suspends the started flows matching the selector, invoking the suspend handler for each flow.
+func (dsdk *DataPlaneSDK) BulkSuspend(ctx context.Context, selector FlowSelector, reason string) ([]BulkResult, error) {
+	return dsdk.bulk(ctx, selector, []DataFlowState{Started}, func(ctx context.Context, id string) error {
+		return dsdk.Suspend(ctx, id, reason)
+	})
+}
+
+// BulkResume resumes the suspended flows matching the selector, invoking the resume handler for each flow. Flows
+// violating the policy stay suspended and are reported as failed.
+func (dsdk *DataPlaneSDK) BulkResume(ctx context.Context, selector FlowSelector) ([]BulkResult, error) {
+	return dsdk.bulk(ctx, selector, []DataFlowState{Suspended}, dsdk.Resume)
+}
+
+// BulkTerminate terminates the flows matching the selector that are not completed or terminated, invoking the
+// terminate handler for each flow, e.g. when an agreement is revoked.
+func (dsdk *DataPlaneSDK) BulkTerminate(ctx context.Context, selector FlowSelector, reason string) ([]BulkResult, error) {
+	return dsdk.bulk(ctx, selector, activeStates, func(ctx context.Context, id string) error {
+		return dsdk.Terminate(ctx, id, reason)
+	})
+}
+
+// Bulk applies the action to the flows matching the selector
+func (dsdk *DataPlaneSDK) Bulk(ctx context.Context, action BulkAction, selector FlowSelector, reason string) ([]BulkResult, error) {
+	switch action {
+	case BulkSuspend:
+		return dsdk.BulkSuspend(ctx, selector, reason)
+	case BulkResume:
+		return dsdk.BulkResume(ctx, selector)
+	case BulkTerminate:
+		return dsdk.BulkTerminate(ctx, selector, reason)
+	default:
+		return nil, fmt.Errorf("%w: unknown bulk action %q", ErrInvalidInput, action)
+	}
+}
+
+// bulk applies the operation to each selected flow in a separate transaction, so that a failing flow does not affect
+// the others. An error is only returned if the flows cannot be selected.
+func (dsdk *DataPlaneSDK) bulk(ctx context.Context, selector FlowSelector, states []DataFlowState, operation func(context.Context, string) error) ([]BulkResult, error) {
+	if selector == (FlowSelector{}) {
+		return nil, fmt.Errorf("%w: a bulk operation requires an agreement, counterparty or dataset", ErrInvalidInput)
+	}
+	flows, err := dsdk.List(ctx, FlowQuery{
+		States:         states,
+		AgreementID:    selector.AgreementID,
+		CounterPartyID: selector.CounterPartyID,
+		DatasetID:      selector.DatasetID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("selecting data flows: %w", err)
+	}
+
+	// a message ID identifies a single signaling message and must not deduplicate the per-flow operations
+	ctx = context.WithValue(ctx, messageIDKeyType{}, "")
+	results := make([]BulkResult, 0, len(flows))
+	for _, flow := range flows {
+		result := BulkResult{DataFlowID: flow.ID}
+		if err := operation(ctx, flow.ID); err != nil {
+			dsdk.Monitor.Printf("Error in bulk operation for data flow %s: %v\n", flow.ID, err)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,117 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneSDK_BulkTerminate(t *testing.T) {
+	var terminated []string
+	sdk, store := newLifecycleSdk(t, WithTerminateProcessor(func(ctx context.Context, flow *DataFlow) error {
+		if flow.ID == "broken" {
+			return errors.New("resources unavailable")
+		}
+		terminated = append(terminated, flow.ID)
+		return nil
+	}))
+	ok := &DataFlow{ID: "ok", AgreementID: "agreement-1", State: Started}
+	broken := &DataFlow{ID: "broken", AgreementID: "agreement-1", State: Suspended}
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, AgreementID: "agreement-1"}).
+		Return(newSliceIterator(ok, broken), nil)
+	store.EXPECT().FindById(mock.Anything, "ok").Return(ok, nil)
+	store.EXPECT().FindById(mock.Anything, "broken").Return(broken, nil)
+	store.EXPECT().Save(mock.Anything, ok).Return(nil)
+
+	ctx := ContextWithMessageID(context.Background(), "bulk-message")
+	results, err := sdk.BulkTerminate(ctx, FlowSelector{AgreementID: "agreement-1"}, "agreement revoked")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ok"}, terminated)
+	assert.Equal(t, Terminated, ok.State)
+	assert.Equal(t, "agreement revoked", ok.ErrorDetail)
+	require.Len(t, results, 2)
+	assert.Equal(t, BulkResult{DataFlowID: "ok"}, results[0])
+	assert.Equal(t, "broken", results[1].DataFlowID)
+	assert.Contains(t, results[1].Error, "resources unavailable")
+}
+
+func Test_DataPlaneSDK_BulkSuspend(t *testing.T) {
+	sdk, store := newLifecycleSdk(t)
+	flow := &DataFlow{ID: "flow-1", CounterPartyID: "counterparty-1", DatasetID: "dataset-1", State: Started}
+	store.EXPECT().Query(mock.Anything, FlowQuery{
+		States:         []DataFlowState{Started},
+		CounterPartyID: "counterparty-1",
+		DatasetID:      "dataset-1",
+	}).Return(newSliceIterator(flow), nil)
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(flow, nil)
+	store.EXPECT().Save(mock.Anything, flow).Return(nil)
+
+	results, err := sdk.Bulk(context.Background(), BulkSuspend, FlowSelector{CounterPartyID: "counterparty-1", DatasetID: "dataset-1"}, "maintenance")
+
+	require.NoError(t, err)
+	assert.Equal(t, []BulkResult{{DataFlowID: "flow-1"}}, results)
+	assert.Equal(t, Suspended, flow.State)
+}
+
+func Test_DataPlaneSDK_Bulk_InvalidInput(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+
+	_, err := sdk.BulkResume(context.Background(), FlowSelector{})
+	assert.ErrorIs(t, err, ErrInvalidInput, "an empty selector must not select all flows")
+
+	_, err = sdk.Bulk(context.Background(), BulkAction("delete"), FlowSelector{AgreementID: "agreement-1"}, "")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func Test_DataPlaneApi_Bulk(t *testing.T) {
+	sdk, store := newLifecycleSdk(t)
+	flow := &DataFlow{ID: "flow-1", AgreementID: "agreement-1", State: Suspended}
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: []DataFlowState{Suspended}, AgreementID: "agreement-1"}).
+		Return(newSliceIterator(flow), nil)
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(flow, nil)
+	store.EXPECT().Save(mock.Anything, flow).Return(nil)
+	api := NewDataPlaneApi(sdk)
+
+	body := `{"action":"resume","selector":{"agreementID":"agreement-1"}}`
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/bulk", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	api.bulk(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response DataFlowBulkResponseMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []BulkResult{{DataFlowID: "flow-1"}}, response.DataFlows)
+}
+
+func Test_DataPlaneApi_Bulk_InvalidAction(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+	api := NewDataPlaneApi(sdk)
+
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/bulk", strings.NewReader(`{"action":"delete","selector":{"agreementID":"a"}}`))
+	rec := httptest.NewRecorder()
+	api.bulk(rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusBadRequest)
+	assert.Equal(t, ProblemTypeValidation, problem.Type)
+}
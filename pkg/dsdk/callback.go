@@ -0,0 +1,100 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Data flow events sent to the control plane callback address
+const (
+	PreparedEvent   = "prepared"
+	StartedEvent    = "started"
+	SuspendedEvent  = "suspended"
+	TerminatedEvent = "terminated"
+)
+
+const defaultCallbackTimeout = 30 * time.Second
+
+// CallbackNotifier informs the control plane about data flow state changes that happen outside a signaling request,
+// e.g. when a retried processor eventually succeeds or fails.
+type CallbackNotifier interface {
+	Notify(ctx context.Context, flow *DataFlow, event string, message *DataFlowResponseMessage) error
+}
+
+// HTTPCallbackNotifier posts the message as JSON to {callbackAddress}/transfers/{processID}/dataflow/{event}.
+type HTTPCallbackNotifier struct {
+	client *http.Client
+}
+
+// NewHTTPCallbackNotifier creates a notifier using the given client. If the client is nil, a client with a default
+// timeout is used.
+func NewHTTPCallbackNotifier(client *http.Client) *HTTPCallbackNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: defaultCallbackTimeout}
+	}
+	return &HTTPCallbackNotifier{client: client}
+}
+
+func (n *HTTPCallbackNotifier) Notify(ctx context.Context, flow *DataFlow, event string, message *DataFlowResponseMessage) error {
+	callback := url.URL(flow.CallbackAddress)
+	if callback.Host == "" {
+		return fmt.Errorf("%w: data flow %s has no callback address", ErrInvalidInput, flow.ID)
+	}
+	endpoint := callback.JoinPath("transfers", flow.ID, "dataflow", event)
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encoding %s notification for data flow %s: %w", event, flow.ID, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating %s notification for data flow %s: %w", event, flow.ID, err)
+	}
+	req.Header.Set(contentType, jsonContentType)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending %s notification for data flow %s: %w", event, flow.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sending %s notification for data flow %s: unexpected status %d", event, flow.ID, resp.StatusCode)
+	}
+	return nil
+}
+
+// notify sends an event to the callback notifier, if configured. Failures are logged since the flow has already been
+// persisted.
+func (dsdk *DataPlaneSDK) notify(ctx context.Context, flow *DataFlow, event string, message *DataFlowResponseMessage) {
+	if dsdk.notifier == nil {
+		return
+	}
+	if err := dsdk.notifier.Notify(ctx, flow, event, message); err != nil {
+		dsdk.Monitor.Printf("Error notifying control plane: %v\n", err)
+	}
+}
+
+// notifyAsync sends the event in a background goroutine that is drained on shutdown
+func (dsdk *DataPlaneSDK) notifyAsync(flow *DataFlow, event string, message *DataFlowResponseMessage) {
+	dsdk.background(func(_ context.Context, abortCtx context.Context) {
+		dsdk.notify(abortCtx, flow, event, message)
+	})
+}
@@ -0,0 +1,66 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HTTPCallbackNotifier_Notify(t *testing.T) {
+	var path string
+	var received DataFlowResponseMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	callback, _ := url.Parse(server.URL + "/callback")
+	flow := &DataFlow{ID: "flow123", CallbackAddress: CallbackURL(*callback)}
+
+	err := NewHTTPCallbackNotifier(nil).Notify(context.Background(), flow, TerminatedEvent, &DataFlowResponseMessage{State: Terminated, Error: "failed"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "/callback/transfers/flow123/dataflow/terminated", path)
+	assert.Equal(t, Terminated, received.State)
+	assert.Equal(t, "failed", received.Error)
+}
+
+func Test_HTTPCallbackNotifier_Notify_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	callback, _ := url.Parse(server.URL)
+	flow := &DataFlow{ID: "flow123", CallbackAddress: CallbackURL(*callback)}
+
+	err := NewHTTPCallbackNotifier(nil).Notify(context.Background(), flow, StartedEvent, &DataFlowResponseMessage{State: Started})
+
+	assert.ErrorContains(t, err, "unexpected status 500")
+}
+
+func Test_HTTPCallbackNotifier_Notify_NoCallbackAddress(t *testing.T) {
+	err := NewHTTPCallbackNotifier(nil).Notify(context.Background(), &DataFlow{ID: "flow123"}, StartedEvent, &DataFlowResponseMessage{})
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
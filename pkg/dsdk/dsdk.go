@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 )
 
 // DataFlowProcessor is an extension point for handling SDK data flow events. Implementations may modify the data flow instance
@@ -32,7 +33,31 @@ type DataPlaneSDK struct {
 	onStart     DataFlowProcessor
 	onTerminate DataFlowHandler
 	onSuspend   DataFlowHandler
+	onResume    DataFlowHandler
 	onComplete  DataFlowHandler
+	onRecover   DataFlowHandler
+
+	retryPolicy RetryPolicy
+	notifier    CallbackNotifier
+	workers     []Worker
+	runtimeID   string
+
+	terminateUnrecoverable bool
+	tenants                map[string]Tenant
+
+	maxActivePerCounterparty int
+	maxActivePerAgreement    int
+
+	policyEvaluator    PolicyEvaluator
+	policyAction       PolicyAction
+	policyReevaluation bool
+	policyInterval     time.Duration
+	livenessChecks     []namedCheck
+	readinessChecks    []namedCheck
+	healthCheckTimeout time.Duration
+
+	lifecycle   lifecycle
+	flowWorkers flowWorkers
 }
 
 // Prepare is called on the consumer to prepare for receiving data.
@@ -42,66 +67,19 @@ func (dsdk *DataPlaneSDK) Prepare(ctx context.Context, message DataFlowPrepareMe
 	if processID == "" {
 		return nil, errors.New("processID cannot be empty")
 	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	var response *DataFlowResponseMessage
-	err := dsdk.execute(ctx, func(context.Context) error {
-		flow, err := dsdk.Store.FindById(ctx, processID)
-		if err != nil && !errors.Is(err, ErrNotFound) {
-			return fmt.Errorf("performing de-duplication for %s: %w", processID, err)
-		}
-
-		switch {
-		case flow != nil && (flow.State == Preparing || flow.State == Prepared):
-			// duplicate message, pass to handler to generate a data address if needed (on consumer)
-			response, err = dsdk.onPrepare(ctx, flow, dsdk, &ProcessorOptions{Duplicate: true})
-			if err != nil {
-				return fmt.Errorf("processing data flow: %w", err)
-			}
-			// todo: not sure about this, added because Prepare() has it too
-			if err := dsdk.Store.Save(ctx, flow); err != nil {
-				return fmt.Errorf("creating data flow: %w", err)
-			}
-			return nil
-		case flow != nil:
-			return fmt.Errorf("%w: data flow %s is not in PREPARING or PREPARED state but in %s", ErrConflict, flow.ID, flow.State.String())
-			//return NewConflictError(fmt.Sprintf("data flow %s is not in PREPARING or PREPARED state", flow.ID))
-		}
-		flow, err = NewDataFlowBuilder().ID(processID).
-			Consumer(true).
-			State(Preparing).
-			AgreementID(message.AgreementID).
-			DatasetID(message.DatasetID).
-			ParticipantID(message.ParticipantID).
-			CounterpartyID(message.CounterPartyID).
-			DataspaceContext(message.DataspaceContext).
-			TransferType(message.TransferType).
-			CallbackAddress(message.CallbackAddress).
-			Build()
-
-		if err != nil {
-			return fmt.Errorf("creating data flow: %w", err)
-		}
-
-		response, err = dsdk.onPrepare(ctx, flow, dsdk, &ProcessorOptions{})
-		if err != nil {
-			return fmt.Errorf("processing data flow %s: %w", flow.ID, err)
-		}
-		if response.State == Prepared {
-			err := flow.TransitionToPrepared()
-			if err != nil {
-				return err
-			}
-		} else if response.State == Preparing {
-			err := flow.TransitionToPreparing()
-			if err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("onPrepare returned an invalid state %s", response.State)
-		}
-		if err := dsdk.Store.Create(ctx, flow); err != nil {
-			return fmt.Errorf("creating data flow %s: %w", flow.ID, err)
-		}
-		return nil
+	op := newOperation(ctx, message.MessageID)
+	err = dsdk.transact(ctx, op, func(ctx context.Context) error {
+		var err error
+		response, err = dsdk.deduplicate(ctx, message.MessageID, processID, PrepareMessageType, message, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			return dsdk.prepare(ctx, message, op)
+		})
+		return err
 	})
 
 	// fixme: shouldn't we always return a clean nil/error or response/nil tuple?
@@ -115,45 +93,18 @@ func (dsdk *DataPlaneSDK) Start(ctx context.Context, message DataFlowStartMessag
 	if processID == "" {
 		return nil, errors.New("processID cannot be empty")
 	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	var response *DataFlowResponseMessage
-	err := dsdk.execute(ctx, func(context.Context) error {
-		flow, err := dsdk.Store.FindById(ctx, processID)
-		if err != nil && !errors.Is(err, ErrNotFound) {
-			return fmt.Errorf("performing de-duplication for %s: %w", processID, err)
-		}
-
-		if flow == nil {
-			// provider side, process
-			flow, err = NewDataFlowBuilder().ID(processID).
-				State(Starting).
-				AgreementID(message.AgreementID).
-				DatasetID(message.DatasetID).
-				ParticipantID(message.ParticipantID).
-				CounterpartyID(message.CounterPartyID).
-				DataspaceContext(message.DataspaceContext).
-				TransferType(message.TransferType).
-				CallbackAddress(message.CallbackAddress).
-				Build()
-			if err != nil {
-				return fmt.Errorf("creating data flow: %w", err)
-			}
-			response, err = dsdk.onStart(ctx, flow, dsdk, &ProcessorOptions{DataAddress: message.DataAddress})
-			if err != nil {
-				return fmt.Errorf("processing data flow: %w", err)
-			}
-
-			err = dsdk.startState(response, flow)
-			if err != nil {
-				return fmt.Errorf("onStart returned an invalid state: %w", err)
-			}
-
-			if err := dsdk.Store.Create(ctx, flow); err != nil {
-				return fmt.Errorf("creating data flow: %w", err)
-			}
-			return nil
-		}
-
-		response, err = dsdk.startExistingFlow(ctx, flow, message.DataAddress)
+	op := newOperation(ctx, message.MessageID)
+	err = dsdk.transact(ctx, op, func(ctx context.Context) error {
+		var err error
+		response, err = dsdk.deduplicate(ctx, message.MessageID, processID, StartMessageType, message, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			return dsdk.start(ctx, message, op)
+		})
 		return err
 	})
 
@@ -162,25 +113,19 @@ func (dsdk *DataPlaneSDK) Start(ctx context.Context, message DataFlowStartMessag
 }
 
 func (dsdk *DataPlaneSDK) StartById(ctx context.Context, processID string, message DataFlowStartedNotificationMessage) (*DataFlowResponseMessage, error) {
+	done, err := dsdk.admit()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 	var response *DataFlowResponseMessage
-
-	err := dsdk.execute(ctx, func(ctx context.Context) error {
-		existingFlow, err := dsdk.Store.FindById(ctx, processID)
-		if err != nil && !errors.Is(err, ErrNotFound) {
-			return fmt.Errorf("performing de-duplication for %s: %w", processID, err)
-		}
-
-		if existingFlow == nil { // this should never happen -> the store would return an error
-			return ErrNotFound
-		}
-
-		if !existingFlow.Consumer {
-			return fmt.Errorf("%w: startById is only valid for consumer data flows", ErrInvalidInput)
-		}
-
-		response, err = dsdk.startExistingFlow(ctx, existingFlow, message.DataAddress)
+	op := newOperation(ctx, message.MessageID)
+	err = dsdk.transact(ctx, op, func(ctx context.Context) error {
+		var err error
+		response, err = dsdk.deduplicate(ctx, message.MessageID, processID, StartedMessageType, message, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			return dsdk.startById(ctx, processID, message, op)
+		})
 		return err
-
 	})
 	return response, err
 
@@ -190,31 +135,43 @@ func (dsdk *DataPlaneSDK) Terminate(ctx context.Context, processID string, reaso
 	if processID == "" {
 		return errors.New("processID cannot be empty")
 	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
 
-	return dsdk.execute(ctx, func(ctx context.Context) error {
-		flow, err := dsdk.Store.FindById(ctx, processID)
-		if err != nil {
-			return fmt.Errorf("terminating data flow %s: %w", processID, err)
-		}
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		payload := transitionPayload{ProcessID: processID, Reason: reason}
+		_, err := dsdk.deduplicate(ctx, op.messageID, processID, TerminateMessageType, payload, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			flow, err := dsdk.findFlow(ctx, processID)
+			if err != nil {
+				return nil, fmt.Errorf("terminating data flow %s: %w", processID, err)
+			}
+			op.attribute(flow)
 
-		if Terminated == flow.State {
-			return nil // duplicate message, skip processing
-		}
+			if Terminated == flow.State {
+				return nil, nil // duplicate message, skip processing
+			}
 
-		if err := dsdk.onTerminate(ctx, flow); err != nil {
-			return fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
-		}
+			if err := dsdk.onTerminate(ctx, flow); err != nil {
+				return nil, fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
+			}
 
-		err = flow.TransitionToTerminated(reason)
-		if err != nil {
-			return err
-		}
+			err = flow.TransitionToTerminated(reason)
+			if err != nil {
+				return nil, err
+			}
 
-		err = dsdk.Store.Save(ctx, flow)
-		if err != nil {
-			return fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
-		}
-		return nil
+			err = dsdk.Store.Save(ctx, flow)
+			if err != nil {
+				return nil, fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
+			}
+			dsdk.stopFlowWorkerOnCommit(op, flow)
+			return nil, nil
+		})
+		return err
 	})
 }
 
@@ -222,38 +179,127 @@ func (dsdk *DataPlaneSDK) Suspend(ctx context.Context, processID string, reason
 	if processID == "" {
 		return errors.New("processID cannot be empty")
 	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
 
-	return dsdk.execute(ctx, func(ctx context.Context) error {
-		flow, err := dsdk.Store.FindById(ctx, processID)
-		if err != nil {
-			return fmt.Errorf("suspending data flow %s: %w", processID, err)
-		}
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		payload := transitionPayload{ProcessID: processID, Reason: reason}
+		_, err := dsdk.deduplicate(ctx, op.messageID, processID, SuspendMessageType, payload, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			flow, err := dsdk.findFlow(ctx, processID)
+			if err != nil {
+				return nil, fmt.Errorf("suspending data flow %s: %w", processID, err)
+			}
+			op.attribute(flow)
 
-		if Suspended == flow.State {
-			return nil // duplicate message, skip processing
-		}
+			if Suspended == flow.State {
+				return nil, nil // duplicate message, skip processing
+			}
 
-		if err := dsdk.onSuspend(ctx, flow); err != nil {
-			return fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+			if err := dsdk.onSuspend(ctx, flow); err != nil {
+				return nil, fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+			}
+			err = flow.TransitionToSuspended(reason)
+			if err != nil {
+				return nil, err
+			}
+
+			err = dsdk.Store.Save(ctx, flow)
+			if err != nil {
+				return nil, fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+			}
+			dsdk.stopFlowWorkerOnCommit(op, flow)
+			return nil, nil
+		})
+		return err
+	})
+
+}
+
+// Resume restarts a suspended data flow. Flows violating the policy are rejected with ErrPolicyViolation, otherwise
+// it invokes the onResume callback and transitions the flow to STARTED.
+func (dsdk *DataPlaneSDK) Resume(ctx context.Context, processID string) error {
+	if processID == "" {
+		return errors.New("processID cannot be empty")
+	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		payload := transitionPayload{ProcessID: processID}
+		_, err := dsdk.deduplicate(ctx, op.messageID, processID, ResumeMessageType, payload, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			flow, err := dsdk.findFlow(ctx, processID)
+			if err != nil {
+				return nil, fmt.Errorf("resuming data flow %s: %w", processID, err)
+			}
+			op.attribute(flow)
+
+			if Started == flow.State {
+				return nil, nil // duplicate message, skip processing
+			}
+			if Suspended != flow.State {
+				return nil, &FlowStateError{
+					Err:     ErrInvalidTransition,
+					FlowID:  flow.ID,
+					State:   flow.State,
+					Message: fmt.Sprintf("data flow %s is not in SUSPENDED state but in %s", flow.ID, flow.State),
+				}
+			}
+			if err := dsdk.evaluatePolicy(ctx, flow); err != nil {
+				return nil, err
+			}
+
+			if err := dsdk.onResume(withOperation(ctx, op), flow); err != nil {
+				return nil, fmt.Errorf("resuming data flow %s: %w", flow.ID, err)
+			}
+			err = flow.TransitionToStarted()
+			if err != nil {
+				return nil, err
+			}
+			flow.ErrorDetail = "" // clear the suspension reason
+
+			err = dsdk.Store.Save(ctx, flow)
+			if err != nil {
+				return nil, fmt.Errorf("resuming data flow %s: %w", flow.ID, err)
+			}
+			op.afterCommit(func() { dsdk.resumeFlowWorker(flow) })
+			return nil, nil
+		})
+		return err
+	})
+}
+
+// List returns the data flows matching the query ordered by state timestamp.
+func (dsdk *DataPlaneSDK) List(ctx context.Context, query FlowQuery) ([]*DataFlow, error) {
+	var flows []*DataFlow
+	err := dsdk.execute(ctx, func(ctx context.Context) error {
+		if participantID := ParticipantFromContext(ctx); participantID != "" {
+			query.ParticipantID = participantID
 		}
-		err = flow.TransitionToSuspended(reason)
+		iterator, err := dsdk.Store.Query(ctx, query)
 		if err != nil {
-			return err
+			return fmt.Errorf("querying data flows: %w", err)
 		}
-
-		err = dsdk.Store.Save(ctx, flow)
-		if err != nil {
-			return fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+		defer iterator.Close()
+		for iterator.Next() {
+			flows = append(flows, iterator.Get())
 		}
-		return nil
+		return iterator.Error()
 	})
-
+	return flows, err
 }
 
 func (dsdk *DataPlaneSDK) Status(ctx context.Context, id string) (*DataFlow, error) {
 	var flow *DataFlow
 	err := dsdk.execute(ctx, func(ctx context.Context) error {
-		found, err := dsdk.Store.FindById(ctx, id)
+		found, err := dsdk.findFlow(ctx, id)
 		if err != nil {
 			return err
 		}
@@ -267,39 +313,203 @@ func (dsdk *DataPlaneSDK) Complete(ctx context.Context, dataflowID string) error
 	if dataflowID == "" {
 		return errors.New("processID cannot be empty")
 	}
+	done, err := dsdk.admit()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	op := newOperation(ctx, MessageIDFromContext(ctx))
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		payload := transitionPayload{ProcessID: dataflowID}
+		_, err := dsdk.deduplicate(ctx, op.messageID, dataflowID, CompleteMessageType, payload, func(ctx context.Context) (*DataFlowResponseMessage, error) {
+			flow, err := dsdk.findFlow(ctx, dataflowID)
+			if err != nil {
+				return nil, fmt.Errorf("completing data flow %s: %w", dataflowID, err)
+			}
+			op.attribute(flow)
+
+			if flow.State == Completed { // de-duplication
+				return nil, nil
+			}
+
+			transitionError := flow.TransitionToCompleted()
+			if transitionError != nil {
+				return nil, transitionError
+			}
+			// only invoked if the transition was successful
+			e := dsdk.onComplete(ctx, flow)
+			if e != nil {
+				return nil, e
+			}
+			if err := dsdk.Store.Save(ctx, flow); err != nil {
+				return nil, fmt.Errorf("completing data flow %s: %w", flow.ID, err)
+			}
+			dsdk.stopFlowWorkerOnCommit(op, flow)
+			return nil, nil
+		})
+		return err
+	})
+}
+
+// prepare creates the consumer flow for a prepare message or processes a duplicate
+func (dsdk *DataPlaneSDK) prepare(ctx context.Context, message DataFlowPrepareMessage, op *operation) (*DataFlowResponseMessage, error) {
+	processID := message.ProcessID
+	var response *DataFlowResponseMessage
+	flow, err := dsdk.findFlow(ctx, processID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("performing de-duplication for %s: %w", processID, err)
+	}
+	if flow != nil {
+		op.attribute(flow)
+	}
 
-	return dsdk.execute(ctx, func(ctx context.Context) error {
-		flow, err := dsdk.Store.FindById(ctx, dataflowID)
+	switch {
+	case flow != nil && (flow.State == Preparing || flow.State == Prepared):
+		// duplicate message, pass to handler to generate a data address if needed (on consumer)
+		response, err = dsdk.onPrepare(ctx, flow, dsdk, &ProcessorOptions{Duplicate: true})
 		if err != nil {
-			return fmt.Errorf("completing data flow %s: %w", dataflowID, err)
+			return nil, fmt.Errorf("processing data flow: %w", err)
+		}
+		// todo: not sure about this, added because Prepare() has it too
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return nil, fmt.Errorf("creating data flow: %w", err)
+		}
+		return response, nil
+	case flow != nil:
+		return nil, &FlowStateError{
+			Err:     ErrConflict,
+			FlowID:  flow.ID,
+			State:   flow.State,
+			Message: fmt.Sprintf("data flow %s is not in PREPARING or PREPARED state but in %s", flow.ID, flow.State.String()),
 		}
+	}
+	create, err := dsdk.admitFlow(ctx, &message.DataFlowBaseMessage)
+	if err != nil {
+		return nil, err
+	}
+	flow, err = NewDataFlowBuilder().ID(processID).
+		Consumer(true).
+		RuntimeID(dsdk.runtimeID).
+		State(Preparing).
+		AgreementID(message.AgreementID).
+		DatasetID(message.DatasetID).
+		ParticipantID(message.ParticipantID).
+		CounterpartyID(message.CounterPartyID).
+		DataspaceContext(message.DataspaceContext).
+		TransferType(message.TransferType).
+		CallbackAddress(message.CallbackAddress).
+		Build()
+
+	if err != nil {
+		return nil, fmt.Errorf("creating data flow: %w", err)
+	}
+	op.attribute(flow)
+	if err := dsdk.evaluatePolicy(ctx, flow); err != nil {
+		return nil, err
+	}
+	flow.recordCreation()
 
-		if flow.State == Completed { // de-duplication
-			return nil
+	options := &ProcessorOptions{}
+	response, err = dsdk.onPrepare(ctx, flow, dsdk, options)
+	if err != nil {
+		if isClassified(err) {
+			return dsdk.processorFailure(ctx, op, flow, Preparing, dsdk.onPrepare, options, create, err)
 		}
+		return nil, fmt.Errorf("processing data flow %s: %w", flow.ID, err)
+	}
+	if err := dsdk.prepareState(response, flow); err != nil {
+		return nil, err
+	}
+	if err := create(ctx, flow); err != nil {
+		return nil, fmt.Errorf("creating data flow %s: %w", flow.ID, err)
+	}
+	return response, nil
+}
 
-		transitionError := flow.TransitionToCompleted()
-		if transitionError != nil {
-			return transitionError
+// start creates the provider flow for a start message or starts an existing flow
+func (dsdk *DataPlaneSDK) start(ctx context.Context, message DataFlowStartMessage, op *operation) (*DataFlowResponseMessage, error) {
+	processID := message.ProcessID
+	var response *DataFlowResponseMessage
+	flow, err := dsdk.findFlow(ctx, processID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("performing de-duplication for %s: %w", processID, err)
+	}
+	if flow != nil {
+		op.attribute(flow)
+	}
+
+	if flow == nil {
+		// provider side, process
+		create, err := dsdk.admitFlow(ctx, &message.DataFlowBaseMessage)
+		if err != nil {
+			return nil, err
 		}
-		// only invoked if the transition was successful
-		e := dsdk.onComplete(ctx, flow)
-		if e != nil {
-			return e
+		flow, err = NewDataFlowBuilder().ID(processID).
+			RuntimeID(dsdk.runtimeID).
+			State(Starting).
+			AgreementID(message.AgreementID).
+			DatasetID(message.DatasetID).
+			ParticipantID(message.ParticipantID).
+			CounterpartyID(message.CounterPartyID).
+			DataspaceContext(message.DataspaceContext).
+			TransferType(message.TransferType).
+			CallbackAddress(message.CallbackAddress).
+			Build()
+		if err != nil {
+			return nil, fmt.Errorf("creating data flow: %w", err)
 		}
-		storeErr := dsdk.Store.Save(ctx, flow)
+		op.attribute(flow)
+		if err := dsdk.evaluatePolicy(ctx, flow); err != nil {
+			return nil, err
+		}
+		flow.recordCreation()
+		options := &ProcessorOptions{DataAddress: message.DataAddress}
+		response, err = dsdk.onStart(withOperation(ctx, op), flow, dsdk, options)
 		if err != nil {
-			return fmt.Errorf("completing data flow %s: %w", flow.ID, storeErr)
+			if isClassified(err) {
+				return dsdk.processorFailure(ctx, op, flow, Starting, dsdk.onStart, options, create, err)
+			}
+			return nil, fmt.Errorf("processing data flow: %w", err)
 		}
-		return nil
-	})
+
+		err = dsdk.startState(response, flow)
+		if err != nil {
+			return nil, fmt.Errorf("onStart returned an invalid state: %w", err)
+		}
+
+		if err := create(ctx, flow); err != nil {
+			return nil, fmt.Errorf("creating data flow: %w", err)
+		}
+		return response, nil
+	}
+
+	return dsdk.startExistingFlow(ctx, flow, message.DataAddress, op)
 }
 
-func (dsdk *DataPlaneSDK) startExistingFlow(ctx context.Context, flow *DataFlow, sourceAddress *DataAddress) (*DataFlowResponseMessage, error) {
+func (dsdk *DataPlaneSDK) startById(ctx context.Context, processID string, message DataFlowStartedNotificationMessage, op *operation) (*DataFlowResponseMessage, error) {
+	existingFlow, err := dsdk.findFlow(ctx, processID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("performing de-duplication for %s: %w", processID, err)
+	}
+
+	if existingFlow == nil { // this should never happen -> the store would return an error
+		return nil, ErrNotFound
+	}
+
+	op.attribute(existingFlow)
+	if !existingFlow.Consumer {
+		return nil, fmt.Errorf("%w: startById is only valid for consumer data flows", ErrInvalidInput)
+	}
+
+	return dsdk.startExistingFlow(ctx, existingFlow, message.DataAddress, op)
+}
+
+func (dsdk *DataPlaneSDK) startExistingFlow(ctx context.Context, flow *DataFlow, sourceAddress *DataAddress, op *operation) (*DataFlowResponseMessage, error) {
 	switch {
 	case flow != nil && (flow.State == Starting || flow.State == Started):
 		// duplicate message, pass to handler to generate a data address if needed
-		response, err := dsdk.onStart(ctx, flow, dsdk, &ProcessorOptions{Duplicate: true, DataAddress: sourceAddress})
+		response, err := dsdk.onStart(withOperation(ctx, op), flow, dsdk, &ProcessorOptions{Duplicate: true, DataAddress: sourceAddress})
 		if err != nil {
 			return nil, fmt.Errorf("processing data flow: %w", err)
 		}
@@ -315,8 +525,15 @@ func (dsdk *DataPlaneSDK) startExistingFlow(ctx context.Context, flow *DataFlow,
 		return response, err
 	case flow != nil && flow.Consumer && flow.State == Prepared:
 		// consumer side, process
-		response, err := dsdk.onStart(ctx, flow, dsdk, &ProcessorOptions{DataAddress: sourceAddress})
+		if err := dsdk.evaluatePolicy(ctx, flow); err != nil {
+			return nil, err
+		}
+		options := &ProcessorOptions{DataAddress: sourceAddress}
+		response, err := dsdk.onStart(withOperation(ctx, op), flow, dsdk, options)
 		if err != nil {
+			if isClassified(err) {
+				return dsdk.processorFailure(ctx, op, flow, Starting, dsdk.onStart, options, dsdk.Store.Save, err)
+			}
 			return nil, fmt.Errorf("processing data flow: %w", err)
 		}
 
@@ -332,8 +549,30 @@ func (dsdk *DataPlaneSDK) startExistingFlow(ctx context.Context, flow *DataFlow,
 		return response, nil
 
 	default:
-		return nil, fmt.Errorf("%w: data flow %s is not in STARTED state: %s", ErrInvalidTransition, flow.ID, flow.State)
+		return nil, &FlowStateError{
+			Err:     ErrInvalidTransition,
+			FlowID:  flow.ID,
+			State:   flow.State,
+			Message: fmt.Sprintf("data flow %s is not in STARTED state: %s", flow.ID, flow.State),
+		}
+	}
+}
+
+func (dsdk *DataPlaneSDK) prepareState(response *DataFlowResponseMessage, flow *DataFlow) error {
+	if response.State == Prepared {
+		err := flow.TransitionToPrepared()
+		if err != nil {
+			return err
+		}
+	} else if response.State == Preparing {
+		err := flow.TransitionToPreparing()
+		if err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("onPrepare returned an invalid state %s", response.State)
 	}
+	return nil
 }
 
 func (dsdk *DataPlaneSDK) startState(response *DataFlowResponseMessage, flow *DataFlow) error {
@@ -354,12 +593,62 @@ func (dsdk *DataPlaneSDK) startState(response *DataFlowResponseMessage, flow *Da
 }
 
 func (dsdk *DataPlaneSDK) execute(ctx context.Context, callback func(ctx2 context.Context) error) error {
+	return dsdk.transact(ctx, &operation{}, callback)
+}
+
+// transact runs the callback in a transaction and invokes the functions registered with the operation once the
+// transaction has been committed. If the callback returns a committedError, the transaction is committed and the
+// wrapped error is returned.
+func (dsdk *DataPlaneSDK) transact(ctx context.Context, op *operation, callback func(ctx context.Context) error) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
-		return dsdk.TrxContext.Execute(ctx, callback)
 	}
+
+	var committed *committedError
+	err := dsdk.TrxContext.Execute(ctx, func(ctx context.Context) error {
+		err := callback(ctx)
+		if errors.As(err, &committed) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, hook := range op.hooks {
+		hook()
+	}
+	if committed != nil {
+		return committed.err
+	}
+	return nil
+}
+
+// operationKeyType is the context key of the operation of a transaction
+type operationKeyType struct{}
+
+// operation carries the state of a request through its transaction: the message ID and caller recorded in the
+// transition history and the functions to invoke after the transaction has been committed.
+type operation struct {
+	messageID string
+	caller    string
+	hooks     []func()
+}
+
+func newOperation(ctx context.Context, messageID string) *operation {
+	return &operation{messageID: messageID, caller: CallerFromContext(ctx)}
+}
+
+// afterCommit registers a function to invoke after the transaction has been committed
+func (op *operation) afterCommit(fn func()) {
+	op.hooks = append(op.hooks, fn)
+}
+
+// attribute records the message ID and caller of the operation for subsequent transitions of the flow
+func (op *operation) attribute(flow *DataFlow) {
+	flow.SetTransitionSource(op.messageID, op.caller)
 }
 
 // DataPlaneSDKOption configures a DataPlaneSDK instance
@@ -407,6 +696,57 @@ func WithSuspendProcessor(handler DataFlowHandler) DataPlaneSDKOption {
 	}
 }
 
+// WithResumeProcessor configures the handler invoked before a suspended flow is restarted
+func WithResumeProcessor(handler DataFlowHandler) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.onResume = handler
+	}
+}
+
+// WithRetryPolicy configures how processors failing with ErrTransient are retried
+func WithRetryPolicy(policy RetryPolicy) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.retryPolicy = policy
+	}
+}
+
+// WithRuntimeID sets the ID of the data plane instance. Flows created by the SDK are owned by the runtime and recovered
+// by it on startup.
+func WithRuntimeID(id string) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.runtimeID = id
+	}
+}
+
+// WithRecoveryHandler configures the handler invoked on startup for every STARTING or STARTED flow owned by the
+// runtime. Requires WithRuntimeID, so that instances sharing a store do not recover each other's flows.
+func WithRecoveryHandler(handler DataFlowHandler) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.onRecover = handler
+	}
+}
+
+// WithTerminateUnrecoverable terminates flows whose recovery failed and notifies the control plane
+func WithTerminateUnrecoverable(terminate bool) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.terminateUnrecoverable = terminate
+	}
+}
+
+// WithWorker adds a background task that runs from Startup until Shutdown
+func WithWorker(worker Worker) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.workers = append(sdk.workers, worker)
+	}
+}
+
+// WithCallbackNotifier configures how the control plane is informed about asynchronous state changes
+func WithCallbackNotifier(notifier CallbackNotifier) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.notifier = notifier
+	}
+}
+
 func NewDataPlaneSDK(options ...DataPlaneSDKOption) (*DataPlaneSDK, error) {
 	sdk := &DataPlaneSDK{}
 
@@ -422,11 +762,23 @@ func NewDataPlaneSDK(options ...DataPlaneSDKOption) (*DataPlaneSDK, error) {
 	if sdk.TrxContext == nil {
 		return nil, errors.New("transaction context is required")
 	}
+	if sdk.onRecover != nil && sdk.runtimeID == "" {
+		return nil, errors.New("runtime ID is required with a recovery handler")
+	}
+	if err := sdk.validatePolicyReevaluation(); err != nil {
+		return nil, err
+	}
 
 	// Set defaults for optional fields
 	if sdk.Monitor == nil {
 		sdk.Monitor = defaultLogMonitor{}
 	}
+	if sdk.retryPolicy == (RetryPolicy{}) {
+		sdk.retryPolicy = DefaultRetryPolicy
+	}
+	if sdk.notifier == nil {
+		sdk.notifier = NewHTTPCallbackNotifier(nil)
+	}
 	if sdk.onPrepare == nil {
 		sdk.onPrepare = func(context context.Context, flow *DataFlow, sdk *DataPlaneSDK, options *ProcessorOptions) (*DataFlowResponseMessage, error) {
 			return &DataFlowResponseMessage{
@@ -455,11 +807,17 @@ func NewDataPlaneSDK(options ...DataPlaneSDKOption) (*DataPlaneSDK, error) {
 			return nil
 		}
 	}
+	if sdk.onResume == nil {
+		sdk.onResume = func(context context.Context, flow *DataFlow) error {
+			return nil
+		}
+	}
 	if sdk.onComplete == nil {
 		sdk.onComplete = func(context context.Context, flow *DataFlow) error {
 			return nil
 		}
 	}
+	sdk.installTenantProcessors()
 	return sdk, nil
 }
 
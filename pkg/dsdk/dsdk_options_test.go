@@ -98,6 +98,27 @@ func Test_WithSuspendProcessor(t *testing.T) {
 	require.NotNil(t, sdk.onSuspend)
 }
 
+func Test_WithResumeProcessor(t *testing.T) {
+	handler := func(context.Context, *DataFlow) error {
+		return nil
+	}
+	sdk := &DataPlaneSDK{}
+
+	option := WithResumeProcessor(handler)
+	option(sdk)
+
+	require.NotNil(t, sdk.onResume)
+}
+
+func Test_WithWorker(t *testing.T) {
+	sdk := &DataPlaneSDK{}
+
+	WithWorker(func(context.Context) {})(sdk)
+	WithWorker(func(context.Context) {})(sdk)
+
+	require.Len(t, sdk.workers, 2)
+}
+
 func Test_NewDataPlaneSDK_WithoutOptionalFields(t *testing.T) {
 	store := NewMockDataplaneStore(t)
 	trxContext := &mockTrxContext{}
@@ -116,6 +137,7 @@ func Test_NewDataPlaneSDK_WithoutOptionalFields(t *testing.T) {
 	assert.NotNil(t, sdk.onStart)
 	assert.NotNil(t, sdk.onTerminate)
 	assert.NotNil(t, sdk.onSuspend)
+	assert.NotNil(t, sdk.onResume)
 }
 
 func Test_NewDataPlaneSDK_MissingStore(t *testing.T) {
@@ -358,6 +358,28 @@ func Test_DataPlaneSDK_Prepare(t *testing.T) {
 	assert.NotNil(t, response)
 }
 
+func Test_DataPlaneSDK_Prepare_RuntimeID(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		runtimeID:  "runtime-1",
+		onPrepare: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return &DataFlowResponseMessage{State: Prepared}, nil
+		},
+	}
+
+	ctx := context.Background()
+
+	store.EXPECT().FindById(mock.Anything, mock.AnythingOfType("string")).Return(nil, ErrNotFound)
+	store.EXPECT().Create(ctx, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.RuntimeID == "runtime-1"
+	})).Return(nil)
+
+	_, err := dsdk.Prepare(ctx, createPrepareMessage())
+	assert.NoError(t, err)
+}
+
 func Test_DataPlaneSDK_Prepare_VerifySdkCallback(t *testing.T) {
 	tests := []struct {
 		state       DataFlowState
@@ -660,6 +682,97 @@ func Test_DataPlaneSDK_Suspend_SdkCallbackError(t *testing.T) {
 	assert.ErrorContains(t, err, "some error")
 }
 
+func Test_DataPlaneSDK_Resume(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	resumed := false
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		onResume: func(ctx context.Context, flow *DataFlow) error {
+			resumed = true
+			return nil
+		},
+	}
+
+	ctx := context.Background()
+
+	store.EXPECT().FindById(ctx, "flow123").Return(&DataFlow{
+		ID:          "flow123",
+		State:       Suspended,
+		ErrorDetail: "maintenance",
+	}, nil)
+	store.EXPECT().Save(ctx, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Started && df.ErrorDetail == ""
+	})).Return(nil)
+
+	err := dsdk.Resume(ctx, "flow123")
+
+	assert.NoError(t, err)
+	assert.True(t, resumed)
+}
+
+func Test_DataPlaneSDK_Resume_AlreadyStarted(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	ctx := context.Background()
+
+	store.EXPECT().FindById(ctx, "flow123").Return(&DataFlow{
+		ID:    "flow123",
+		State: Started,
+	}, nil)
+
+	// no callback and no save call expected
+
+	err := dsdk.Resume(ctx, "flow123")
+
+	assert.NoError(t, err)
+}
+
+func Test_DataPlaneSDK_Resume_NotSuspended(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	ctx := context.Background()
+
+	store.EXPECT().FindById(ctx, "flow123").Return(&DataFlow{
+		ID:    "flow123",
+		State: Terminated,
+	}, nil)
+
+	err := dsdk.Resume(ctx, "flow123")
+
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+}
+
+func Test_DataPlaneSDK_Resume_SdkCallbackError(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		onResume: func(ctx context.Context, flow *DataFlow) error {
+			return fmt.Errorf("some error")
+		},
+	}
+
+	ctx := context.Background()
+
+	store.EXPECT().FindById(ctx, "flow123").Return(&DataFlow{
+		ID:    "flow123",
+		State: Suspended,
+	}, nil)
+
+	err := dsdk.Resume(ctx, "flow123")
+
+	assert.ErrorContains(t, err, "some error")
+}
+
 func Test_DataPlaneSDK_Completed(t *testing.T) {
 	store := NewMockDataplaneStore(t)
 	dsdk := DataPlaneSDK{
@@ -685,6 +798,29 @@ func Test_DataPlaneSDK_Completed(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_DataPlaneSDK_Completed_StoreError(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		onComplete: func(ctx context.Context, flow *DataFlow) error {
+			return nil
+		},
+	}
+
+	ctx := context.Background()
+
+	store.EXPECT().FindById(ctx, "flow123").Return(&DataFlow{
+		ID:    "flow123",
+		State: Started,
+	}, nil)
+	store.EXPECT().Save(ctx, mock.Anything).Return(errors.New("database unavailable"))
+
+	err := dsdk.Complete(ctx, "flow123")
+
+	assert.ErrorContains(t, err, "database unavailable")
+}
+
 func Test_DataPlaneSDK_Completed_AlreadyCompleted(t *testing.T) {
 	store := NewMockDataplaneStore(t)
 	dsdk := DataPlaneSDK{
@@ -17,6 +17,20 @@ var (
 	ErrInvalidInput = errors.New("invalid input")
 	// ErrInvalidTransition Sentinel error to indicate an invalid state transition, e.g. of a data flow
 	ErrInvalidTransition = errors.New("invalid transition")
+	// ErrUnauthorized indicates that the caller is not authorized to perform an operation
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotSupported indicates that an operation is not supported, e.g. by the configured store
+	ErrNotSupported = errors.New("not supported")
+	// ErrPolicyViolation indicates that the policy of an agreement does not permit a data flow, e.g. because it expired
+	ErrPolicyViolation = errors.New("policy violation")
+	// ErrLimitExceeded indicates that a request was rejected because a limit was reached, e.g. the number of active flows
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrShuttingDown indicates that the data plane is shutting down and does not accept new requests
+	ErrShuttingDown = errors.New("shutting down")
+	// ErrTransient Sentinel error returned by processors to indicate a temporary failure. The SDK retries the processor.
+	ErrTransient = errors.New("transient failure")
+	// ErrFatal Sentinel error returned by processors to indicate a permanent failure. The SDK terminates the flow.
+	ErrFatal = errors.New("fatal failure")
 )
 
 // NewValidationError Helper to create new ValidationError
@@ -27,3 +41,63 @@ func NewValidationError(messages ...string) error {
 func WrapValidationError(err error) error {
 	return fmt.Errorf("%w: %w", ErrValidation, err)
 }
+
+// WrapTransientError marks a processor error as temporary, e.g. because a downstream service is unavailable.
+func WrapTransientError(err error) error {
+	return fmt.Errorf("%w: %w", ErrTransient, err)
+}
+
+// WrapFatalError marks a processor error as permanent.
+func WrapFatalError(err error) error {
+	return fmt.Errorf("%w: %w", ErrFatal, err)
+}
+
+// committedError is returned by operations that persisted a data flow despite failing, e.g. a flow terminated after a
+// fatal processor error. The transaction is committed and the wrapped error is returned to the caller afterward.
+type committedError struct {
+	err error
+}
+
+func (e *committedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *committedError) Unwrap() error {
+	return e.err
+}
+
+// recordedFailure replays the problem recorded for a message whose operation failed after persisting the flow
+type recordedFailure struct {
+	problem ProblemDetails
+}
+
+func (e *recordedFailure) Error() string {
+	return e.problem.Detail
+}
+
+// FlowStateError indicates that an operation is not permitted in the current state of a data flow. It wraps either
+// ErrInvalidTransition or ErrConflict.
+type FlowStateError struct {
+	Err     error
+	FlowID  string
+	State   DataFlowState
+	Message string
+}
+
+func (e *FlowStateError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err, e.Message)
+}
+
+func (e *FlowStateError) Unwrap() error {
+	return e.Err
+}
+
+// newTransitionError Helper to create an invalid transition error for the given flow and target state
+func newTransitionError(df *DataFlow, target DataFlowState) error {
+	return &FlowStateError{
+		Err:     ErrInvalidTransition,
+		FlowID:  df.ID,
+		State:   df.State,
+		Message: fmt.Sprintf("cannot transition from %v to %v", df.State, target),
+	}
+}
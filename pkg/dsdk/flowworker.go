@@ -0,0 +1,164 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FlowWorker is a long-running task of a data flow, e.g. a publisher streaming events to the consumer. It must return
+// once its context is cancelled. Returning nil completes the flow, returning an error terminates it.
+type FlowWorker func(ctx context.Context, flow *DataFlow) error
+
+// flowWorkers tracks the workers registered for data flows. The zero value is ready to use.
+type flowWorkers struct {
+	mu      sync.Mutex
+	workers map[string]*flowWorker
+}
+
+type flowWorker struct {
+	run FlowWorker
+	// cancel stops the running worker, or is nil if the worker is not running
+	cancel context.CancelFunc
+}
+
+// RegisterFlowWorker registers the worker of a flow, typically from the start processor. The worker is run once the
+// operation invoking the processor has been committed and the flow has been started, or for flows left in STARTING once
+// NotifyStarted is called. Its context is cancelled when the flow is suspended, terminated or completed, or when the
+// data plane shuts down. It is restarted when the flow is resumed. After a restart of the data plane, the recovery
+// handler or, for suspended flows, the resume processor must register the worker again. When the worker returns nil,
+// the flow is completed; when it returns an error, the flow is terminated and the control plane notified.
+func (dsdk *DataPlaneSDK) RegisterFlowWorker(ctx context.Context, flow *DataFlow, worker FlowWorker) {
+	if op, ok := ctx.Value(operationKeyType{}).(*operation); ok {
+		op.afterCommit(func() {
+			switch flow.State {
+			case Started:
+				dsdk.startFlowWorker(flow, worker, true)
+			case Starting:
+				dsdk.startFlowWorker(flow, worker, false)
+			}
+		})
+		return
+	}
+	dsdk.startFlowWorker(flow, worker, true)
+}
+
+// withOperation passes the operation to a processor, so that the workers it registers are started once the operation
+// has been committed
+func withOperation(ctx context.Context, op *operation) context.Context {
+	return context.WithValue(ctx, operationKeyType{}, op)
+}
+
+// startFlowWorker registers the worker, replacing a worker registered previously for the flow, and runs it if requested
+func (dsdk *DataPlaneSDK) startFlowWorker(flow *DataFlow, worker FlowWorker, run bool) {
+	w := &dsdk.flowWorkers
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.workers == nil {
+		w.workers = make(map[string]*flowWorker)
+	}
+	if existing, found := w.workers[flow.ID]; found && existing.cancel != nil {
+		existing.cancel()
+	}
+	entry := &flowWorker{run: worker}
+	w.workers[flow.ID] = entry
+	if run {
+		dsdk.runFlowWorker(entry, flow)
+	}
+}
+
+// resumeFlowWorker runs the worker registered for a flow that has been resumed or started asynchronously
+func (dsdk *DataPlaneSDK) resumeFlowWorker(flow *DataFlow) {
+	w := &dsdk.flowWorkers
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if entry, found := w.workers[flow.ID]; found && entry.cancel == nil {
+		dsdk.runFlowWorker(entry, flow)
+	}
+}
+
+// stopFlowWorker cancels the worker of a flow. The worker is kept for a restart unless it is removed.
+func (dsdk *DataPlaneSDK) stopFlowWorker(processID string, remove bool) {
+	w := &dsdk.flowWorkers
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry, found := w.workers[processID]
+	if !found {
+		return
+	}
+	if entry.cancel != nil {
+		entry.cancel()
+		entry.cancel = nil
+	}
+	if remove {
+		delete(w.workers, processID)
+	}
+}
+
+// stopFlowWorkerOnCommit cancels the worker of the flow once the operation has been committed. The worker is kept for
+// a restart if the flow has been suspended.
+func (dsdk *DataPlaneSDK) stopFlowWorkerOnCommit(op *operation, flow *DataFlow) {
+	remove := flow.State != Suspended
+	op.afterCommit(func() { dsdk.stopFlowWorker(flow.ID, remove) })
+}
+
+// runFlowWorker runs the worker in the background. Must be called with the lock held.
+func (dsdk *DataPlaneSDK) runFlowWorker(entry *flowWorker, flow *DataFlow) {
+	ctx, cancel := context.WithCancel(context.Background())
+	entry.cancel = cancel
+	worker := entry.run
+	dsdk.background(func(stopCtx context.Context, abortCtx context.Context) {
+		stop := context.AfterFunc(stopCtx, cancel)
+		defer stop()
+		err := worker(ctx, flow)
+		if ctx.Err() != nil {
+			return // cancelled by a transition of the flow or the shutdown
+		}
+		cancel()
+		dsdk.flowWorkerExited(abortCtx, flow.ID, err)
+	})
+}
+
+// flowWorkerExited completes the flow if the worker succeeded and terminates it otherwise
+func (dsdk *DataPlaneSDK) flowWorkerExited(ctx context.Context, processID string, err error) {
+	if err == nil {
+		if err := dsdk.Complete(ctx, processID); err != nil {
+			dsdk.Monitor.Printf("Error completing data flow %s after its worker finished: %v\n", processID, err)
+		}
+		return
+	}
+	dsdk.Monitor.Printf("Worker of data flow %s failed: %v\n", processID, err)
+	if err := dsdk.terminateFlowWorkerFailure(ctx, processID, err); err != nil {
+		dsdk.Monitor.Printf("Error terminating data flow %s after its worker failed: %v\n", processID, err)
+	}
+}
+
+func (dsdk *DataPlaneSDK) terminateFlowWorkerFailure(ctx context.Context, processID string, cause error) error {
+	op := newOperation(ctx, "")
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return err
+		}
+		op.attribute(flow)
+		if flow.State == Terminated || flow.State == Completed {
+			return nil // transitioned in the meantime
+		}
+		if err := dsdk.onTerminate(ctx, flow); err != nil {
+			return fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
+		}
+		return dsdk.terminateFailed(ctx, op, flow, fmt.Errorf("worker failed: %w", cause), dsdk.Store.Save)
+	})
+}
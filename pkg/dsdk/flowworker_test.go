@@ -0,0 +1,114 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func awaitSignal(t *testing.T, signal <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-signal:
+	case <-time.After(5 * time.Second):
+		t.Fatal(msg)
+	}
+}
+
+func Test_FlowWorker_SuspendResumeShutdown(t *testing.T) {
+	running := make(chan struct{}, 1)
+	stopped := make(chan struct{}, 1)
+	worker := func(ctx context.Context, flow *DataFlow) error {
+		running <- struct{}{}
+		<-ctx.Done()
+		stopped <- struct{}{}
+		return ctx.Err()
+	}
+	var started *DataFlow
+	sdk, store := newLifecycleSdk(t, WithStartProcessor(func(ctx context.Context, flow *DataFlow, sdk *DataPlaneSDK, _ *ProcessorOptions) (*DataFlowResponseMessage, error) {
+		started = flow
+		sdk.RegisterFlowWorker(ctx, flow, worker)
+		select {
+		case <-running:
+			t.Error("the worker must not run before the transaction has been committed")
+		default:
+		}
+		return &DataFlowResponseMessage{State: Started}, nil
+	}))
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
+
+	_, err := sdk.Start(context.Background(), createStartMessage())
+	require.NoError(t, err)
+	awaitSignal(t, running, "worker not started")
+
+	store.EXPECT().FindById(mock.Anything, "process123").Return(started, nil)
+	store.EXPECT().Save(mock.Anything, started).Return(nil)
+
+	require.NoError(t, sdk.Suspend(context.Background(), "process123", "maintenance"))
+	awaitSignal(t, stopped, "worker not cancelled on suspend")
+
+	require.NoError(t, sdk.Resume(context.Background(), "process123"))
+	awaitSignal(t, running, "worker not restarted on resume")
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+	awaitSignal(t, stopped, "worker not cancelled on shutdown")
+	assert.Equal(t, Started, started.State, "a cancelled worker must not change the flow")
+}
+
+func Test_FlowWorker_CompletesFlow(t *testing.T) {
+	sdk, store := newLifecycleSdk(t)
+	flow := &DataFlow{ID: "flow-1", State: Started}
+	saved := make(chan struct{})
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(flow, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Completed
+	})).Return(nil).Run(func(context.Context, *DataFlow) { close(saved) })
+
+	sdk.RegisterFlowWorker(context.Background(), flow, func(ctx context.Context, flow *DataFlow) error {
+		return nil
+	})
+
+	awaitSignal(t, saved, "flow not completed")
+}
+
+func Test_FlowWorker_FailureTerminatesFlow(t *testing.T) {
+	notifier := newRecordingNotifier()
+	terminated := false
+	sdk, store := newLifecycleSdk(t,
+		WithCallbackNotifier(notifier),
+		WithTerminateProcessor(func(ctx context.Context, flow *DataFlow) error {
+			terminated = true
+			return nil
+		}))
+	flow := &DataFlow{ID: "flow-1", State: Started}
+	store.EXPECT().FindById(mock.Anything, "flow-1").Return(flow, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Terminated
+	})).Return(nil)
+
+	sdk.RegisterFlowWorker(context.Background(), flow, func(ctx context.Context, flow *DataFlow) error {
+		return errors.New("destination unreachable")
+	})
+
+	assert.Equal(t, TerminatedEvent, notifier.await(t).name)
+	assert.True(t, terminated)
+	assert.Contains(t, flow.ErrorDetail, "destination unreachable")
+}
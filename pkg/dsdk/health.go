@@ -0,0 +1,167 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultHealthCheckTimeout bounds each health check unless configured with WithHealthCheckTimeout.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck returns an error if a component is unhealthy, e.g. because a connection is lost.
+type HealthCheck func(ctx context.Context) error
+
+// Pinger is an optional extension of DataplaneStore and TransactionContext that verifies the connection to the backing
+// system. It is used by the readiness check.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "UP"
+	HealthDown HealthStatus = "DOWN"
+)
+
+// HealthReport is the result of a liveness or readiness check. The status is DOWN if one of the checks failed.
+type HealthReport struct {
+	Status HealthStatus        `json:"status"`
+	Checks []HealthCheckResult `json:"checks"`
+}
+
+// HealthCheckResult is the result of a single check. Error contains the reason if the check failed.
+type HealthCheckResult struct {
+	Name     string       `json:"name"`
+	Status   HealthStatus `json:"status"`
+	Error    string       `json:"error,omitempty"`
+	Duration int64        `json:"durationMs"`
+}
+
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// WithHealthCheck registers a check that must pass for the data plane to be ready, e.g. for a NATS connection or a
+// token service.
+func WithHealthCheck(name string, check HealthCheck) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.readinessChecks = append(sdk.readinessChecks, namedCheck{name: name, check: check})
+	}
+}
+
+// WithLivenessCheck registers a check that must pass for the data plane to be alive. A failing liveness check usually
+// causes the data plane to be restarted, so it should only fail if the data plane cannot recover on its own.
+func WithLivenessCheck(name string, check HealthCheck) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.livenessChecks = append(sdk.livenessChecks, namedCheck{name: name, check: check})
+	}
+}
+
+// WithHealthCheckTimeout configures the time after which a health check is reported as failed
+func WithHealthCheckTimeout(timeout time.Duration) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.healthCheckTimeout = timeout
+	}
+}
+
+// Liveness reports whether the background workers are running and the registered liveness checks pass.
+func (dsdk *DataPlaneSDK) Liveness(ctx context.Context) HealthReport {
+	checks := []namedCheck{{name: "workers", check: dsdk.checkWorkers}}
+	return dsdk.runChecks(ctx, append(checks, dsdk.livenessChecks...))
+}
+
+// Readiness reports whether the data plane accepts signaling requests: it is not shutting down, the store and
+// transaction context are reachable if they implement Pinger, and the registered health checks pass.
+func (dsdk *DataPlaneSDK) Readiness(ctx context.Context) HealthReport {
+	checks := []namedCheck{{name: "lifecycle", check: dsdk.checkLifecycle}}
+	if pinger, ok := dsdk.Store.(Pinger); ok {
+		checks = append(checks, namedCheck{name: "store", check: pinger.Ping})
+	}
+	if pinger, ok := dsdk.TrxContext.(Pinger); ok {
+		checks = append(checks, namedCheck{name: "transactionContext", check: pinger.Ping})
+	}
+	return dsdk.runChecks(ctx, append(checks, dsdk.readinessChecks...))
+}
+
+// runChecks runs the checks concurrently, each bounded by the health check timeout
+func (dsdk *DataPlaneSDK) runChecks(ctx context.Context, checks []namedCheck) HealthReport {
+	timeout := dsdk.healthCheckTimeout
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+	report := HealthReport{Status: HealthUp, Checks: make([]HealthCheckResult, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = runCheck(ctx, check, timeout)
+		}()
+	}
+	wg.Wait()
+	for _, result := range report.Checks {
+		if result.Status == HealthDown {
+			report.Status = HealthDown
+		}
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check namedCheck, timeout time.Duration) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not complete: %w", ctx.Err())
+	}
+
+	result := HealthCheckResult{Name: check.name, Status: HealthUp, Duration: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = HealthDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (dsdk *DataPlaneSDK) checkLifecycle(context.Context) error {
+	l := &dsdk.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closing {
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+func (dsdk *DataPlaneSDK) checkWorkers(context.Context) error {
+	l := &dsdk.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.exitedWorkers > 0 {
+		return fmt.Errorf("%d of %d workers exited unexpectedly", l.exitedWorkers, len(dsdk.workers))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pingingStore struct {
+	*MockDataplaneStore
+	err error
+}
+
+func (s *pingingStore) Ping(context.Context) error {
+	return s.err
+}
+
+func Test_DataPlaneSDK_Readiness(t *testing.T) {
+	store := &pingingStore{MockDataplaneStore: NewMockDataplaneStore(t)}
+	sdk, err := NewDataPlaneSDK(WithStore(store), WithTransactionContext(&mockTrxContext{}),
+		WithHealthCheck("nats", func(context.Context) error { return nil }))
+	require.NoError(t, err)
+
+	report := sdk.Readiness(context.Background())
+
+	assert.Equal(t, HealthUp, report.Status)
+	require.Len(t, report.Checks, 3)
+	assert.Equal(t, "lifecycle", report.Checks[0].Name)
+	assert.Equal(t, "store", report.Checks[1].Name)
+	assert.Equal(t, "nats", report.Checks[2].Name)
+}
+
+func Test_DataPlaneSDK_Readiness_Down(t *testing.T) {
+	store := &pingingStore{MockDataplaneStore: NewMockDataplaneStore(t), err: errors.New("connection refused")}
+	sdk, err := NewDataPlaneSDK(WithStore(store), WithTransactionContext(&mockTrxContext{}),
+		WithHealthCheckTimeout(10*time.Millisecond),
+		WithHealthCheck("token service", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}))
+	require.NoError(t, err)
+	require.NoError(t, sdk.Shutdown(context.Background()))
+
+	report := sdk.Readiness(context.Background())
+
+	assert.Equal(t, HealthDown, report.Status)
+	assert.Equal(t, []HealthStatus{HealthDown, HealthDown, HealthDown}, []HealthStatus{
+		report.Checks[0].Status, report.Checks[1].Status, report.Checks[2].Status,
+	})
+	assert.Equal(t, "shutting down", report.Checks[0].Error)
+	assert.Equal(t, "connection refused", report.Checks[1].Error)
+	assert.Contains(t, report.Checks[2].Error, "check did not complete")
+}
+
+func Test_DataPlaneSDK_Liveness_WorkerExited(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t, WithWorker(func(context.Context) {}))
+	assert.Equal(t, HealthUp, sdk.Liveness(context.Background()).Status)
+
+	require.NoError(t, sdk.Startup(context.Background()))
+
+	require.Eventually(t, func() bool {
+		return sdk.Liveness(context.Background()).Status == HealthDown
+	}, time.Second, 5*time.Millisecond)
+	report := sdk.Liveness(context.Background())
+	assert.Equal(t, "1 of 1 workers exited unexpectedly", report.Checks[0].Error)
+}
+
+func Test_DataPlaneApi_Readiness(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+	api := NewDataPlaneApi(sdk)
+
+	rec := httptest.NewRecorder()
+	api.Readiness(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+	rec = httptest.NewRecorder()
+	api.Readiness(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var report HealthReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, HealthDown, report.Status)
+	assert.Equal(t, "lifecycle", report.Checks[0].Name)
+}
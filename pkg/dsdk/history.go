@@ -0,0 +1,59 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+)
+
+type callerKeyType struct{}
+
+// ContextWithCaller attaches the identity of the authenticated caller to the context. It is recorded in the transition
+// history of the flows modified by the request.
+func ContextWithCaller(ctx context.Context, caller string) context.Context {
+	if caller == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, callerKeyType{}, caller)
+}
+
+// CallerFromContext returns the caller identity attached to the context or an empty string.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKeyType{}).(string)
+	return caller
+}
+
+// History returns the transition history of a data flow in chronological order. Returns ErrNotSupported if the store
+// does not implement HistoryStore.
+func (dsdk *DataPlaneSDK) History(ctx context.Context, id string) ([]TransitionRecord, error) {
+	historyStore, ok := dsdk.Store.(HistoryStore)
+	if !ok {
+		return nil, fmt.Errorf("%w: the store does not record transition history", ErrNotSupported)
+	}
+	var history []TransitionRecord
+	err := dsdk.execute(ctx, func(ctx context.Context) error {
+		if ParticipantFromContext(ctx) != "" {
+			if _, err := dsdk.findFlow(ctx, id); err != nil {
+				return fmt.Errorf("reading history of data flow %s: %w", id, err)
+			}
+		}
+		records, err := historyStore.History(ctx, id)
+		if err != nil {
+			return fmt.Errorf("reading history of data flow %s: %w", id, err)
+		}
+		history = records
+		return nil
+	})
+	return history, err
+}
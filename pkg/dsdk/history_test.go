@@ -0,0 +1,143 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneSDK_Suspend_RecordsTransitionSource(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: detachedTrxContext{},
+		onSuspend:  func(context.Context, *DataFlow) error { return nil },
+	}
+
+	ctx := ContextWithCaller(ContextWithMessageID(context.Background(), "message123"), "did:web:consumer.com")
+	store.EXPECT().FindById(mock.Anything, "process123").Return(&DataFlow{ID: "process123", State: Started}, nil)
+	var transitions []TransitionRecord
+	store.EXPECT().Save(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, flow *DataFlow) error {
+		transitions = flow.TakeTransitions()
+		return nil
+	})
+
+	require.NoError(t, dsdk.Suspend(ctx, "process123", "maintenance"))
+
+	require.Len(t, transitions, 1)
+	assert.Equal(t, Started, transitions[0].From)
+	assert.Equal(t, Suspended, transitions[0].To)
+	assert.Equal(t, "maintenance", transitions[0].Reason)
+	assert.Equal(t, "message123", transitions[0].MessageID)
+	assert.Equal(t, "did:web:consumer.com", transitions[0].Caller)
+}
+
+func Test_DataPlaneSDK_Start_RecordsCreation(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		onStart: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return &DataFlowResponseMessage{State: Started}, nil
+		},
+	}
+
+	ctx := context.Background()
+	message := createStartMessage()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound)
+	var transitions []TransitionRecord
+	store.EXPECT().Create(ctx, mock.Anything).RunAndReturn(func(_ context.Context, flow *DataFlow) error {
+		transitions = flow.TakeTransitions()
+		return nil
+	})
+
+	_, err := dsdk.Start(ctx, message)
+
+	require.NoError(t, err)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, Uninitialized, transitions[0].From)
+	assert.Equal(t, Starting, transitions[0].To)
+	assert.Equal(t, Starting, transitions[1].From)
+	assert.Equal(t, Started, transitions[1].To)
+	assert.Equal(t, message.MessageID, transitions[1].MessageID)
+}
+
+func Test_DataPlaneSDK_History_NotSupported(t *testing.T) {
+	dsdk := DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}}
+
+	_, err := dsdk.History(context.Background(), "process123")
+
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func Test_DataPlaneApi_History(t *testing.T) {
+	store := &historyStore{MockDataplaneStore: NewMockDataplaneStore(t), history: []TransitionRecord{
+		{FlowID: "flow123", From: Started, To: Suspended, Reason: "maintenance", Caller: "did:web:consumer.com", Timestamp: 42},
+	}}
+	api := NewDataPlaneApi(&DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/history", nil)
+	rec := httptest.NewRecorder()
+	api.history("flow123", rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response DataFlowHistoryResponseMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "flow123", response.DataFlowID)
+	assert.Equal(t, store.history, response.Transitions)
+}
+
+func Test_DataPlaneApi_Suspend_ResolvesCaller(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	var caller string
+	sdk := &DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		Monitor:    defaultLogMonitor{},
+		onSuspend:  func(context.Context, *DataFlow) error { return nil },
+	}
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", State: Started}, nil)
+	store.EXPECT().Save(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, flow *DataFlow) error {
+		caller = flow.TakeTransitions()[0].Caller
+		return nil
+	})
+	api := NewDataPlaneApi(sdk, WithCallerResolver(func(r *http.Request) string {
+		return r.Header.Get("X-Caller")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/flow123/suspend", strings.NewReader(`{"reason":"maintenance"}`))
+	req.Header.Set("X-Caller", "did:web:consumer.com")
+	rec := httptest.NewRecorder()
+	api.Suspend("flow123", rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "did:web:consumer.com", caller)
+}
+
+type historyStore struct {
+	*MockDataplaneStore
+	history []TransitionRecord
+}
+
+func (h *historyStore) History(context.Context, string) ([]TransitionRecord, error) {
+	return h.history, nil
+}
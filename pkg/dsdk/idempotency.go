@@ -0,0 +1,142 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Message types recorded in the message ledger
+const (
+	PrepareMessageType   = "prepare"
+	StartMessageType     = "start"
+	StartedMessageType   = "started"
+	SuspendMessageType   = "suspend"
+	ResumeMessageType    = "resume"
+	TerminateMessageType = "terminate"
+	CompleteMessageType  = "complete"
+)
+
+type messageIDKeyType struct{}
+
+// transitionPayload is the hashed payload of Terminate, Suspend, Resume and Complete requests.
+type transitionPayload struct {
+	ProcessID string `json:"processID"`
+	Reason    string `json:"reason"`
+}
+
+// ContextWithMessageID attaches the ID of the signaling message being processed to the context. Operations that do
+// not receive a message, such as Terminate and Suspend, use it for MessageID based idempotency.
+func ContextWithMessageID(ctx context.Context, messageID string) context.Context {
+	if messageID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, messageIDKeyType{}, messageID)
+}
+
+// MessageIDFromContext returns the message ID attached to the context or an empty string.
+func MessageIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(messageIDKeyType{}).(string)
+	return id
+}
+
+// deduplicate executes the operation unless a message with the same ID has already been processed, in which case the
+// recorded response is returned. Operations failing with a committedError are recorded with their problem response,
+// which is returned as error on replay. Reusing an ID for a different payload is a conflict. Must be called within a
+// transaction so the ledger entry is written atomically with the flow. If the store does not implement MessageLedger
+// or the message has no ID, the operation is executed directly.
+func (dsdk *DataPlaneSDK) deduplicate(ctx context.Context,
+	messageID string,
+	processID string,
+	messageType string,
+	payload any,
+	operation func(ctx context.Context) (*DataFlowResponseMessage, error)) (*DataFlowResponseMessage, error) {
+	ledger, ok := dsdk.Store.(MessageLedger)
+	if !ok || messageID == "" {
+		return operation(ctx)
+	}
+
+	hash, err := payloadHash(messageType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	processed, err := ledger.FindMessage(ctx, messageID)
+	switch {
+	case err == nil:
+		if processed.PayloadHash != hash || processed.MessageType != messageType {
+			return nil, fmt.Errorf("%w: message %s was already processed with a different payload", ErrConflict, messageID)
+		}
+		if processed.Failure != nil {
+			var problem ProblemDetails
+			if err := json.Unmarshal(processed.Failure, &problem); err != nil {
+				return nil, fmt.Errorf("decoding recorded failure for message %s: %w", messageID, err)
+			}
+			return nil, &recordedFailure{problem: problem}
+		}
+		if processed.Response == nil {
+			return nil, nil
+		}
+		var response DataFlowResponseMessage
+		if err := json.Unmarshal(processed.Response, &response); err != nil {
+			return nil, fmt.Errorf("decoding recorded response for message %s: %w", messageID, err)
+		}
+		return &response, nil
+	case !errors.Is(err, ErrNotFound):
+		return nil, fmt.Errorf("performing de-duplication for message %s: %w", messageID, err)
+	}
+
+	response, opErr := operation(ctx)
+	var committed *committedError
+	if opErr != nil && !errors.As(opErr, &committed) {
+		return nil, opErr
+	}
+
+	record := &ProcessedMessage{
+		MessageID:   messageID,
+		ProcessID:   processID,
+		MessageType: messageType,
+		PayloadHash: hash,
+		CreatedAt:   time.Now().UnixMilli(),
+	}
+	if opErr != nil {
+		if record.Failure, err = json.Marshal(NewProblemDetails(opErr)); err != nil {
+			return nil, fmt.Errorf("encoding failure for message %s: %w", messageID, err)
+		}
+	} else if response != nil {
+		if record.Response, err = json.Marshal(response); err != nil {
+			return nil, fmt.Errorf("encoding response for message %s: %w", messageID, err)
+		}
+	}
+	if err := ledger.SaveMessage(ctx, record); err != nil {
+		return nil, fmt.Errorf("recording message %s: %w", messageID, err)
+	}
+	return response, opErr
+}
+
+func payloadHash(messageType string, payload any) (string, error) {
+	serialized, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("hashing %s message: %w", messageType, err)
+	}
+	hash := sha256.New()
+	hash.Write([]byte(messageType))
+	hash.Write(serialized)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
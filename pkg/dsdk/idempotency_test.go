@@ -0,0 +1,186 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneSDK_Start_ReplayReturnsRecordedResponse(t *testing.T) {
+	store := &ledgerStore{MockDataplaneStore: NewMockDataplaneStore(t), messages: map[string]*ProcessedMessage{}}
+	invocations := 0
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		onStart: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			invocations++
+			return &DataFlowResponseMessage{State: Started, DataAddress: &DataAddress{Properties: map[string]any{"endpoint": "https://example.com"}}}, nil
+		},
+	}
+
+	ctx := context.Background()
+	message := createStartMessage()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(ctx, mock.Anything).Return(nil).Once()
+
+	first, err := dsdk.Start(ctx, message)
+	require.NoError(t, err)
+	replayed, err := dsdk.Start(ctx, message)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, invocations)
+	assert.Equal(t, first, replayed)
+	require.Contains(t, store.messages, message.MessageID)
+	assert.Equal(t, StartMessageType, store.messages[message.MessageID].MessageType)
+	assert.Equal(t, "process123", store.messages[message.MessageID].ProcessID)
+}
+
+func Test_DataPlaneSDK_Start_ReusedMessageIDConflicts(t *testing.T) {
+	store := &ledgerStore{MockDataplaneStore: NewMockDataplaneStore(t), messages: map[string]*ProcessedMessage{}}
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		onStart: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return &DataFlowResponseMessage{State: Started}, nil
+		},
+	}
+
+	ctx := context.Background()
+	message := createStartMessage()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(ctx, mock.Anything).Return(nil).Once()
+
+	_, err := dsdk.Start(ctx, message)
+	require.NoError(t, err)
+
+	message.AgreementID = "other-agreement"
+	_, err = dsdk.Start(ctx, message)
+
+	assert.ErrorIs(t, err, ErrConflict)
+}
+
+func Test_DataPlaneSDK_Terminate_ReplayIsSkipped(t *testing.T) {
+	store := &ledgerStore{MockDataplaneStore: NewMockDataplaneStore(t), messages: map[string]*ProcessedMessage{}}
+	dsdk := DataPlaneSDK{
+		Store:       store,
+		TrxContext:  detachedTrxContext{},
+		onTerminate: func(context.Context, *DataFlow) error { return nil },
+	}
+
+	ctx := ContextWithMessageID(context.Background(), "message123")
+	store.EXPECT().FindById(mock.Anything, "process123").Return(&DataFlow{ID: "process123", State: Started}, nil).Once()
+	store.EXPECT().Save(mock.Anything, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, dsdk.Terminate(ctx, "process123", "done"))
+	require.NoError(t, dsdk.Terminate(ctx, "process123", "done"))
+
+	assert.ErrorIs(t, dsdk.Suspend(ctx, "process123", "done"), ErrConflict)
+}
+
+func Test_DataPlaneSDK_Start_ReplayReturnsRecordedFailure(t *testing.T) {
+	store := &ledgerStore{MockDataplaneStore: NewMockDataplaneStore(t), messages: map[string]*ProcessedMessage{}}
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithStartProcessor(func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return nil, WrapFatalError(errors.New("source unavailable"))
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	message := createStartMessage()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(ctx, mock.MatchedBy(func(df *DataFlow) bool { return df.State == Terminated })).Return(nil).Once()
+
+	_, err = sdk.Start(ctx, message)
+	require.Error(t, err)
+	_, replayErr := sdk.Start(ctx, message)
+
+	require.Error(t, replayErr)
+	assert.Equal(t, NewProblemDetails(err), NewProblemDetails(replayErr))
+	require.Contains(t, store.messages, message.MessageID)
+	assert.NotNil(t, store.messages[message.MessageID].Failure)
+}
+
+func Test_DataPlaneSDK_Complete_ReplayIsSkipped(t *testing.T) {
+	store := &ledgerStore{MockDataplaneStore: NewMockDataplaneStore(t), messages: map[string]*ProcessedMessage{}}
+	completions := 0
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: detachedTrxContext{},
+		onComplete: func(context.Context, *DataFlow) error {
+			completions++
+			return nil
+		},
+	}
+
+	ctx := ContextWithMessageID(context.Background(), "message123")
+	store.EXPECT().FindById(mock.Anything, "process123").Return(&DataFlow{ID: "process123", State: Started}, nil).Once()
+	store.EXPECT().Save(mock.Anything, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, dsdk.Complete(ctx, "process123"))
+	require.NoError(t, dsdk.Complete(ctx, "process123"))
+
+	assert.Equal(t, 1, completions)
+	assert.Equal(t, CompleteMessageType, store.messages["message123"].MessageType)
+}
+
+func Test_DataPlaneSDK_Terminate_WithoutLedger(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:       store,
+		TrxContext:  &mockTrxContext{},
+		onTerminate: func(context.Context, *DataFlow) error { return nil },
+	}
+
+	ctx := ContextWithMessageID(context.Background(), "message123")
+	store.EXPECT().FindById(ctx, "process123").Return(&DataFlow{ID: "process123", State: Started}, nil).Once()
+	store.EXPECT().Save(ctx, mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, dsdk.Terminate(ctx, "process123", "done"))
+}
+
+// detachedTrxContext does not propagate the caller context, like the in-memory transaction context.
+type detachedTrxContext struct{}
+
+func (detachedTrxContext) Execute(_ context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.TODO())
+}
+
+type ledgerStore struct {
+	*MockDataplaneStore
+	messages map[string]*ProcessedMessage
+}
+
+func (l *ledgerStore) FindMessage(_ context.Context, messageID string) (*ProcessedMessage, error) {
+	message, found := l.messages[messageID]
+	if !found {
+		return nil, ErrNotFound
+	}
+	return message, nil
+}
+
+func (l *ledgerStore) SaveMessage(_ context.Context, message *ProcessedMessage) error {
+	if _, found := l.messages[message.MessageID]; found {
+		return ErrConflict
+	}
+	l.messages[message.MessageID] = message
+	return nil
+}
@@ -0,0 +1,230 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Worker is a background task owned by the SDK, e.g. RetentionJanitor.Run. It is started by Startup and must return
+// once its context is cancelled by Shutdown.
+type Worker func(ctx context.Context)
+
+// ShutdownOption configures a Shutdown call
+type ShutdownOption func(*shutdownConfig)
+
+type shutdownConfig struct {
+	suspendReason string
+	finite        func(*DataFlow) bool
+}
+
+// WithSuspendActiveFlows suspends started flows with the reason once in-flight requests have drained. Flows for which
+// finite returns true complete on their own and are left running; if finite is nil, all started flows are suspended.
+func WithSuspendActiveFlows(reason string, finite func(*DataFlow) bool) ShutdownOption {
+	return func(config *shutdownConfig) {
+		config.suspendReason = reason
+		config.finite = finite
+	}
+}
+
+// lifecycle tracks the requests and goroutines of an SDK instance so that they can be drained on shutdown. The zero
+// value is ready to use.
+type lifecycle struct {
+	mu       sync.Mutex
+	started  bool
+	closing  bool // new signaling requests are rejected
+	stopped  bool // no new background goroutines are started
+	inflight sync.WaitGroup
+	workers  sync.WaitGroup
+	// exitedWorkers counts the workers that returned before the shutdown
+	exitedWorkers int
+
+	// stopCtx is cancelled when the shutdown begins, ending workers and retry backoffs
+	stopCtx context.Context
+	stop    context.CancelFunc
+	// abortCtx is cancelled when the shutdown deadline expires, aborting running processors and notifications
+	abortCtx context.Context
+	abort    context.CancelFunc
+}
+
+// init creates the contexts of the lifecycle. Must be called with the lock held.
+func (l *lifecycle) init() {
+	if l.stopCtx == nil {
+		l.stopCtx, l.stop = context.WithCancel(context.Background())
+		l.abortCtx, l.abort = context.WithCancel(context.Background())
+	}
+}
+
+// Startup recovers the active flows owned by the runtime if a recovery handler is configured and starts the workers
+// configured with WithWorker. Signaling requests are accepted with or without a call to Startup, but background workers
+// only run once the SDK has been started. If the recovery of flows failed, the SDK is started nevertheless and a
+// *RecoveryError is returned.
+func (dsdk *DataPlaneSDK) Startup(ctx context.Context) error {
+	l := &dsdk.lifecycle
+	l.mu.Lock()
+	if l.closing {
+		l.mu.Unlock()
+		return ErrShuttingDown
+	}
+	if l.started {
+		l.mu.Unlock()
+		return errors.New("data plane already started")
+	}
+	l.init()
+	l.started = true
+	l.mu.Unlock()
+
+	err := dsdk.Recover(ctx)
+	for _, worker := range dsdk.workers {
+		dsdk.background(func(stopCtx context.Context, _ context.Context) {
+			worker(stopCtx)
+			if stopCtx.Err() == nil {
+				dsdk.Monitor.Println("Worker exited before shutdown")
+				l.mu.Lock()
+				l.exitedWorkers++
+				l.mu.Unlock()
+			}
+		})
+	}
+	return err
+}
+
+// Shutdown stops the SDK. New signaling and admin requests are rejected with ErrShuttingDown while in-flight requests
+// are drained. Afterward, active flows are optionally suspended and workers and background retries and notifications
+// are stopped. If the context expires first, the remaining operations are cancelled and the context error is returned.
+func (dsdk *DataPlaneSDK) Shutdown(ctx context.Context, options ...ShutdownOption) error {
+	config := &shutdownConfig{}
+	for _, opt := range options {
+		opt(config)
+	}
+
+	l := &dsdk.lifecycle
+	l.mu.Lock()
+	if l.closing {
+		l.mu.Unlock()
+		return ErrShuttingDown
+	}
+	l.init()
+	l.closing = true
+	l.mu.Unlock()
+	defer l.abort()
+
+	var errs []error
+	if err := wait(ctx, &l.inflight); err != nil {
+		errs = append(errs, fmt.Errorf("draining in-flight requests: %w", err))
+	} else if config.suspendReason != "" {
+		if err := dsdk.suspendActive(ctx, config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	l.mu.Lock()
+	l.stopped = true
+	l.mu.Unlock()
+	l.stop()
+	if err := wait(ctx, &l.workers); err != nil {
+		errs = append(errs, fmt.Errorf("stopping background workers: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// admit registers a signaling or admin request. The returned function must be called when the request has completed.
+func (dsdk *DataPlaneSDK) admit() (func(), error) {
+	l := &dsdk.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closing {
+		return nil, ErrShuttingDown
+	}
+	l.inflight.Add(1)
+	return l.inflight.Done, nil
+}
+
+// background runs fn in a goroutine tracked by the lifecycle. The first context passed to fn is cancelled when the
+// shutdown begins, the second when it is aborted. Once the SDK has been stopped, fn is not run.
+func (dsdk *DataPlaneSDK) background(fn func(stopCtx context.Context, abortCtx context.Context)) {
+	l := &dsdk.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		dsdk.Monitor.Println("Data plane stopped, skipping background task")
+		return
+	}
+	l.init()
+	l.workers.Add(1)
+	go func() {
+		defer l.workers.Done()
+		fn(l.stopCtx, l.abortCtx)
+	}()
+}
+
+// suspendActive suspends the started flows not reported as finite by the configuration
+func (dsdk *DataPlaneSDK) suspendActive(ctx context.Context, config *shutdownConfig) error {
+	flows, err := dsdk.List(ctx, FlowQuery{States: []DataFlowState{Started}, RuntimeID: dsdk.runtimeID})
+	if err != nil {
+		return fmt.Errorf("suspending active data flows: %w", err)
+	}
+	var errs []error
+	for _, flow := range flows {
+		if config.finite != nil && config.finite(flow) {
+			continue
+		}
+		if err := dsdk.suspendForShutdown(ctx, flow.ID, config.suspendReason); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (dsdk *DataPlaneSDK) suspendForShutdown(ctx context.Context, processID string, reason string) error {
+	op := newOperation(ctx, "")
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return fmt.Errorf("suspending data flow %s: %w", processID, err)
+		}
+		op.attribute(flow)
+		if flow.State != Started {
+			return nil // transitioned in the meantime
+		}
+		if err := dsdk.onSuspend(ctx, flow); err != nil {
+			return fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+		}
+		if err := flow.TransitionToSuspended(reason); err != nil {
+			return err
+		}
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+		}
+		dsdk.Monitor.Printf("Suspended data flow %s on shutdown\n", flow.ID)
+		return nil
+	})
+}
+
+// wait blocks until the wait group is done or the context expires
+func wait(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,148 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newLifecycleSdk(t *testing.T, options ...DataPlaneSDKOption) (*DataPlaneSDK, *MockDataplaneStore) {
+	store := NewMockDataplaneStore(t)
+	sdk, err := NewDataPlaneSDK(append([]DataPlaneSDKOption{WithStore(store), WithTransactionContext(&mockTrxContext{})}, options...)...)
+	require.NoError(t, err)
+	return sdk, store
+}
+
+func Test_DataPlaneSDK_Shutdown_RejectsRequests(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+
+	_, err := sdk.Prepare(context.Background(), DataFlowPrepareMessage{DataFlowBaseMessage: DataFlowBaseMessage{ProcessID: "flow123"}})
+	assert.ErrorIs(t, err, ErrShuttingDown)
+	assert.ErrorIs(t, sdk.Terminate(context.Background(), "flow123", ""), ErrShuttingDown)
+	assert.ErrorIs(t, sdk.ForceTransition(context.Background(), "flow123", Terminated, "incident"), ErrShuttingDown)
+	_, err = sdk.RetryStart(context.Background(), "flow123")
+	assert.ErrorIs(t, err, ErrShuttingDown)
+	assert.ErrorIs(t, sdk.Delete(context.Background(), "flow123"), ErrShuttingDown)
+	assert.ErrorIs(t, sdk.Shutdown(context.Background()), ErrShuttingDown)
+	assert.ErrorIs(t, sdk.Startup(context.Background()), ErrShuttingDown)
+}
+
+func Test_DataPlaneSDK_Shutdown_DrainsInflight(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+	done, err := sdk.admit()
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- sdk.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-result:
+		t.Fatal("shutdown returned before the in-flight request completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	done()
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("shutdown did not return after the in-flight request completed")
+	}
+}
+
+func Test_DataPlaneSDK_Shutdown_Deadline(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+	_, err := sdk.admit()
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = sdk.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_DataPlaneSDK_Shutdown_StopsWorkers(t *testing.T) {
+	stopped := make(chan struct{})
+	sdk, _ := newLifecycleSdk(t, WithWorker(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	}))
+	require.NoError(t, sdk.Startup(context.Background()))
+	require.Error(t, sdk.Startup(context.Background()))
+
+	require.NoError(t, sdk.Shutdown(context.Background()))
+
+	select {
+	case <-stopped:
+	default:
+		t.Fatal("worker was not stopped")
+	}
+}
+
+func Test_DataPlaneSDK_Shutdown_StopsRetries(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Hour}))
+	sdk.background(func(stopCtx context.Context, abortCtx context.Context) {
+		sdk.retry(stopCtx, abortCtx, "flow123", Starting, sdk.onStart, nil)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, sdk.Shutdown(ctx))
+}
+
+func Test_DataPlaneSDK_Shutdown_SuspendsActiveFlows(t *testing.T) {
+	var suspended []string
+	sdk, store := newLifecycleSdk(t, WithSuspendProcessor(func(ctx context.Context, flow *DataFlow) error {
+		suspended = append(suspended, flow.ID)
+		return nil
+	}))
+	streaming := &DataFlow{ID: "streaming", State: Started}
+	finite := &DataFlow{ID: "finite", State: Started, TransferType: TransferType{FlowType: Pull}}
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: []DataFlowState{Started}}).Return(newSliceIterator(streaming, finite), nil)
+	store.EXPECT().FindById(mock.Anything, "streaming").Return(streaming, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.ID == "streaming" && df.State == Suspended && df.ErrorDetail == "maintenance"
+	})).Return(nil)
+
+	err := sdk.Shutdown(context.Background(), WithSuspendActiveFlows("maintenance", func(flow *DataFlow) bool {
+		return flow.TransferType.FlowType == Pull
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"streaming"}, suspended)
+}
+
+func Test_DataPlaneApi_ShuttingDown(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+	require.NoError(t, sdk.Shutdown(context.Background()))
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/flow123/suspend", strings.NewReader(`{"reason":"test"}`))
+	rec := httptest.NewRecorder()
+
+	NewDataPlaneApi(sdk).Suspend("flow123", rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusServiceUnavailable)
+	assert.Equal(t, ProblemTypeUnavailable, problem.Type)
+}
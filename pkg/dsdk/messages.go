@@ -1,10 +1,26 @@
 package dsdk
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
-var v = validator.New()
+var v = newValidator()
+
+// newValidator creates a validator that reports JSON property names in validation errors
+func newValidator() *validator.Validate {
+	val := validator.New()
+	val.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return val
+}
 
 type DataFlowBaseMessage struct {
 	MessageID        string       `json:"messageID" validate:"required"`
@@ -51,6 +67,7 @@ func (d *DataFlowStartMessage) Validate() error {
 }
 
 type DataFlowStartedNotificationMessage struct {
+	MessageID   string       `json:"messageID,omitempty"`
 	DataAddress *DataAddress `json:"dataAddress,omitempty"`
 }
 
@@ -67,13 +84,44 @@ type DataFlowPrepareMessage struct {
 }
 
 type DataFlowTransitionMessage struct {
-	Reason string `json:"reason"`
+	MessageID string `json:"messageID,omitempty"`
+	Reason    string `json:"reason"`
 }
 
 func (d *DataFlowTransitionMessage) Validate() error {
 	return nil // no special behaviour yet
 }
 
+// AdminTransitionMessage forces a data flow into a state through the admin API.
+type AdminTransitionMessage struct {
+	MessageID string `json:"messageID,omitempty"`
+	State     string `json:"state" validate:"required"`
+	Reason    string `json:"reason" validate:"required"`
+}
+
+func (d *AdminTransitionMessage) Validate() error {
+	err := v.Struct(d)
+	if err != nil {
+		return WrapValidationError(err)
+	}
+	return nil
+}
+
+// DataFlowBulkMessage applies an action to the data flows matching the selector.
+type DataFlowBulkMessage struct {
+	Action   BulkAction   `json:"action" validate:"required,oneof=suspend resume terminate"`
+	Selector FlowSelector `json:"selector"`
+	Reason   string       `json:"reason"`
+}
+
+func (d *DataFlowBulkMessage) Validate() error {
+	err := v.Struct(d)
+	if err != nil {
+		return WrapValidationError(err)
+	}
+	return nil
+}
+
 type DataFlowResponseMessage struct {
 	DataplaneID string        `json:"dataplaneID"`
 	DataAddress *DataAddress  `json:"dataAddress,omitempty"`
@@ -82,6 +130,43 @@ type DataFlowResponseMessage struct {
 }
 
 type DataFlowStatusResponseMessage struct {
-	State      DataFlowState `json:"state"`
-	DataFlowID string        `json:"dataFlowID"`
+	State      DataFlowState     `json:"state"`
+	DataFlowID string            `json:"dataFlowID"`
+	Progress   *TransferProgress `json:"progress,omitempty"`
+}
+
+// DataFlowExtendedStatusResponseMessage is the detailed status representation of a data flow. Data addresses are
+// redacted before they are returned.
+type DataFlowExtendedStatusResponseMessage struct {
+	DataFlowStatusResponseMessage
+	Consumer               bool         `json:"consumer"`
+	AgreementID            string       `json:"agreementID"`
+	DatasetID              string       `json:"datasetID"`
+	ParticipantID          string       `json:"participantID"`
+	CounterPartyID         string       `json:"counterPartyID"`
+	DataspaceContext       string       `json:"dataspaceContext"`
+	TransferType           TransferType `json:"transferType"`
+	StateCount             uint         `json:"stateCount"`
+	StateTimestamp         int64        `json:"stateTimestamp"`
+	ErrorDetail            string       `json:"errorDetail,omitempty"`
+	CreatedAt              int64        `json:"createdAt"`
+	UpdatedAt              int64        `json:"updatedAt"`
+	SourceDataAddress      *DataAddress `json:"sourceDataAddress,omitempty"`
+	DestinationDataAddress *DataAddress `json:"destinationDataAddress,omitempty"`
+}
+
+// DataFlowListResponseMessage contains the status of the data flows matching a list request.
+type DataFlowListResponseMessage struct {
+	DataFlows []DataFlowStatusResponseMessage `json:"dataFlows"`
+}
+
+// DataFlowBulkResponseMessage contains the outcome of a bulk request for each selected data flow.
+type DataFlowBulkResponseMessage struct {
+	DataFlows []BulkResult `json:"dataFlows"`
+}
+
+// DataFlowHistoryResponseMessage contains the transition history of a data flow in chronological order.
+type DataFlowHistoryResponseMessage struct {
+	DataFlowID  string             `json:"dataFlowID"`
+	Transitions []TransitionRecord `json:"transitions"`
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -75,6 +76,66 @@ func (b *DataAddressBuilder) Build() (*DataAddress, error) {
 	}, nil
 }
 
+// RedactedValue replaces secret property values in redacted data addresses.
+const RedactedValue = "***"
+
+// DefaultSecretKeys contains the property key fragments treated as secrets when redacting data addresses.
+var DefaultSecretKeys = []string{"token", "secret", "password", "authorization", "credential", "apikey", "api_key", "privatekey", "private_key"}
+
+// Redacted returns a deep copy of the data address where values of properties whose key contains one of the given
+// fragments (case-insensitive) are replaced. Entries of endpoint properties are matched by their "key".
+func (d *DataAddress) Redacted(secretKeys []string) *DataAddress {
+	if d == nil {
+		return nil
+	}
+	isSecret := func(key string) bool {
+		key = strings.ToLower(key)
+		for _, fragment := range secretKeys {
+			if strings.Contains(key, strings.ToLower(fragment)) {
+				return true
+			}
+		}
+		return false
+	}
+	return &DataAddress{Properties: redactMap(d.Properties, isSecret)}
+}
+
+func redactMap(props map[string]any, isSecret func(string) bool) map[string]any {
+	if props == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(props))
+	for k, v := range props {
+		if isSecret(k) {
+			redacted[k] = RedactedValue
+			continue
+		}
+		redacted[k] = redactValue(v, isSecret)
+	}
+	// endpoint property entries have the form {"key": ..., "type": ..., "value": ...}
+	if key, ok := props["key"].(string); ok && isSecret(key) {
+		if _, found := props["value"]; found {
+			redacted["value"] = RedactedValue
+		}
+	}
+	return redacted
+}
+
+func redactValue(v any, isSecret func(string) bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return redactMap(val, isSecret)
+	case []any:
+		items := make([]any, len(val))
+		for i, item := range val {
+			items[i] = redactValue(item, isSecret)
+		}
+		return items
+	default:
+		return v
+	}
+}
+
 type TransferType struct {
 	DestinationType string   `json:"destinationType" validate:"required"`
 	FlowType        FlowType `json:"flowType" validate:"required"`
@@ -105,6 +166,16 @@ func (s DataFlowState) String() string {
 	}
 }
 
+// ParseDataFlowState returns the state with the given name, e.g. STARTED. The name is matched case-insensitively.
+func ParseDataFlowState(name string) (DataFlowState, error) {
+	for _, state := range dataFlowStates {
+		if strings.EqualFold(state.String(), name) {
+			return state, nil
+		}
+	}
+	return Uninitialized, fmt.Errorf("%w: unknown data flow state %q", ErrInvalidInput, name)
+}
+
 const (
 	Uninitialized DataFlowState = 0
 	Preparing     DataFlowState = 50
@@ -116,6 +187,8 @@ const (
 	Terminated    DataFlowState = 350
 )
 
+var dataFlowStates = []DataFlowState{Uninitialized, Preparing, Prepared, Starting, Started, Completed, Suspended, Terminated}
+
 type DataFlow struct {
 	ID                     string
 	Version                int64
@@ -136,6 +209,80 @@ type DataFlow struct {
 	SourceDataAddress      DataAddress
 	DestinationDataAddress DataAddress
 	ErrorDetail            string
+	Progress               TransferProgress
+
+	transitions []TransitionRecord
+	messageID   string
+	caller      string
+}
+
+// TransitionRecord is an entry in the append-only transition history of a data flow. MessageID and Caller identify the
+// signaling message and the authenticated caller that caused the transition, if known.
+type TransitionRecord struct {
+	FlowID    string        `json:"dataFlowID"`
+	From      DataFlowState `json:"from"`
+	To        DataFlowState `json:"to"`
+	Reason    string        `json:"reason,omitempty"`
+	MessageID string        `json:"messageID,omitempty"`
+	Caller    string        `json:"caller,omitempty"`
+	Timestamp int64         `json:"timestamp"`
+}
+
+// SetTransitionSource sets the message ID and caller recorded for subsequent transitions.
+func (df *DataFlow) SetTransitionSource(messageID string, caller string) {
+	df.messageID = messageID
+	df.caller = caller
+}
+
+// TakeTransitions returns the transitions recorded since the flow was loaded and clears them. Stores call it when
+// persisting the flow to append the transitions to the history.
+func (df *DataFlow) TakeTransitions() []TransitionRecord {
+	transitions := df.transitions
+	df.transitions = nil
+	return transitions
+}
+
+// recordCreation records the initial state of a new flow
+func (df *DataFlow) recordCreation() {
+	df.recordTransition(Uninitialized, df.State, "")
+}
+
+func (df *DataFlow) recordTransition(from DataFlowState, to DataFlowState, reason string) {
+	df.transitions = append(df.transitions, TransitionRecord{
+		FlowID:    df.ID,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		MessageID: df.messageID,
+		Caller:    df.caller,
+		Timestamp: time.Now().UnixMilli(),
+	})
+}
+
+// TransferProgress tracks how much data a flow has transferred. ExpectedTotal is optional and expressed in the unit
+// the transfer reports, i.e. bytes or messages. LastActivity is the epoch millis timestamp of the last update.
+type TransferProgress struct {
+	BytesTransferred    int64 `json:"bytesTransferred"`
+	MessagesTransferred int64 `json:"messagesTransferred"`
+	ExpectedTotal       int64 `json:"expectedTotal,omitempty"`
+	LastActivity        int64 `json:"lastActivity,omitempty"`
+}
+
+// Apply adds the given update to the progress and records the activity timestamp.
+func (p *TransferProgress) Apply(update ProgressUpdate, timestamp int64) {
+	p.BytesTransferred += update.Bytes
+	p.MessagesTransferred += update.Messages
+	if update.ExpectedTotal > 0 {
+		p.ExpectedTotal = update.ExpectedTotal
+	}
+	p.LastActivity = timestamp
+}
+
+// ProgressUpdate contains increments reported by a transfer. A positive ExpectedTotal replaces the previous value.
+type ProgressUpdate struct {
+	Bytes         int64
+	Messages      int64
+	ExpectedTotal int64
 }
 
 func (df *DataFlow) TransitionToPreparing() error {
@@ -143,8 +290,9 @@ func (df *DataFlow) TransitionToPreparing() error {
 		return nil
 	}
 	if df.State != Uninitialized {
-		return fmt.Errorf("%w: cannot transition from %v to PREPARING", ErrInvalidTransition, df.State)
+		return newTransitionError(df, Preparing)
 	}
+	df.recordTransition(df.State, Preparing, "")
 	df.State = Preparing
 	df.StateTimestamp = time.Now().UnixMilli()
 	df.StateCount++
@@ -156,8 +304,9 @@ func (df *DataFlow) TransitionToPrepared() error {
 		return nil
 	}
 	if df.State != Uninitialized && df.State != Preparing {
-		return fmt.Errorf("%w: cannot transition from %v to PREPARED", ErrInvalidTransition, df.State)
+		return newTransitionError(df, Prepared)
 	}
+	df.recordTransition(df.State, Prepared, "")
 	df.State = Prepared
 	df.StateTimestamp = time.Now().UnixMilli()
 	df.StateCount++
@@ -169,8 +318,9 @@ func (df *DataFlow) TransitionToStarting() error {
 		return nil
 	}
 	if df.State != Uninitialized && df.State != Prepared {
-		return fmt.Errorf("%w: cannot transition from %v to STARTING", ErrInvalidTransition, df.State)
+		return newTransitionError(df, Starting)
 	}
+	df.recordTransition(df.State, Starting, "")
 	df.State = Starting
 	df.StateTimestamp = time.Now().UnixMilli()
 	df.StateCount++
@@ -182,8 +332,9 @@ func (df *DataFlow) TransitionToStarted() error {
 		return nil
 	}
 	if df.State != Uninitialized && df.State != Prepared && df.State != Starting && df.State != Suspended {
-		return fmt.Errorf("%w: cannot transition from %v to STARTED", ErrInvalidTransition, df.State)
+		return newTransitionError(df, Started)
 	}
+	df.recordTransition(df.State, Started, "")
 	df.State = Started
 	df.StateTimestamp = time.Now().UnixMilli()
 	df.StateCount++
@@ -195,8 +346,9 @@ func (df *DataFlow) TransitionToSuspended(reason string) error {
 		return nil
 	}
 	if df.State != Started {
-		return fmt.Errorf("%w: cannot transition from %v to SUSPENDED", ErrInvalidTransition, df.State)
+		return newTransitionError(df, Suspended)
 	}
+	df.recordTransition(df.State, Suspended, reason)
 	df.State = Suspended
 	//todo: what to do with the reason string?
 	df.ErrorDetail = reason
@@ -210,8 +362,9 @@ func (df *DataFlow) TransitionToCompleted() error {
 		return nil
 	}
 	if df.State != Started {
-		return fmt.Errorf("%w: cannot transition from %v to COMPLETED", ErrInvalidTransition, df.State)
+		return newTransitionError(df, Completed)
 	}
+	df.recordTransition(df.State, Completed, "")
 	df.State = Completed
 	df.StateTimestamp = time.Now().UnixMilli()
 	df.StateCount++
@@ -223,6 +376,7 @@ func (df *DataFlow) TransitionToTerminated(reason string) error {
 		return nil // todo: does returning an error make sense here?
 	}
 	// Any state can transition to terminated
+	df.recordTransition(df.State, Terminated, reason)
 	df.State = Terminated
 	df.ErrorDetail = reason
 	df.StateTimestamp = time.Now().UnixMilli()
@@ -230,6 +384,19 @@ func (df *DataFlow) TransitionToTerminated(reason string) error {
 	return nil
 }
 
+// forceTransition sets the state without checking the state machine. The reason is recorded in the history and kept as
+// error detail of suspended and terminated flows.
+func (df *DataFlow) forceTransition(to DataFlowState, reason string) {
+	df.recordTransition(df.State, to, reason)
+	df.State = to
+	df.ErrorDetail = ""
+	if to == Suspended || to == Terminated {
+		df.ErrorDetail = reason
+	}
+	df.StateTimestamp = time.Now().UnixMilli()
+	df.StateCount++
+}
+
 type DataFlowBuilder struct {
 	dataFlow DataFlow
 }
@@ -313,6 +480,11 @@ func (b *DataFlowBuilder) ErrorDetail(error string) *DataFlowBuilder {
 	return b
 }
 
+func (b *DataFlowBuilder) Progress(progress TransferProgress) *DataFlowBuilder {
+	b.dataFlow.Progress = progress
+	return b
+}
+
 func (b *DataFlowBuilder) RuntimeID(id string) *DataFlowBuilder {
 	b.dataFlow.RuntimeID = id
 	return b
@@ -194,6 +194,39 @@ func TestDataAddressBuilder_EndpointProperty(t *testing.T) {
 }
 
 // Helper function to create a valid builder for testing
+func TestDataAddress_Redacted(t *testing.T) {
+	original, _ := NewDataAddressBuilder().
+		Property(EndpointKey, "https://example.com").
+		Property("authToken", "secret-token").
+		Property("nested", map[string]any{"password": "pwd", "user": "joe"}).
+		EndpointProperty("token", "string", "jwt").
+		EndpointProperty("channel", "string", "channel.forward").
+		Build()
+
+	redacted := original.Redacted(DefaultSecretKeys)
+
+	assert.Equal(t, "https://example.com", redacted.Properties[EndpointKey])
+	assert.Equal(t, RedactedValue, redacted.Properties["authToken"])
+	nested := redacted.Properties["nested"].(map[string]any)
+	assert.Equal(t, RedactedValue, nested["password"])
+	assert.Equal(t, "joe", nested["user"])
+
+	endpoints := redacted.Properties[EndpointProperties].([]any)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, RedactedValue, endpoints[0].(map[string]any)["value"])
+	assert.Equal(t, "token", endpoints[0].(map[string]any)["key"])
+	assert.Equal(t, "channel.forward", endpoints[1].(map[string]any)["value"])
+
+	// the original is not modified
+	assert.Equal(t, "secret-token", original.Properties["authToken"])
+	assert.Equal(t, "jwt", original.Properties[EndpointProperties].([]any)[0].(map[string]any)["value"])
+}
+
+func TestDataAddress_Redacted_Nil(t *testing.T) {
+	var da *DataAddress
+	assert.Nil(t, da.Redacted(DefaultSecretKeys))
+}
+
 func createValidBuilder(validURL *url.URL) *DataFlowBuilder {
 	return NewDataFlowBuilder().
 		ID("test-id").
@@ -213,3 +246,16 @@ func createValidBuilder(validURL *url.URL) *DataFlowBuilder {
 		}).
 		RuntimeID("runtime-123")
 }
+
+func TestParseDataFlowState(t *testing.T) {
+	state, err := ParseDataFlowState("suspended")
+	require.NoError(t, err)
+	assert.Equal(t, Suspended, state)
+
+	state, err = ParseDataFlowState("STARTED")
+	require.NoError(t, err)
+	assert.Equal(t, Started, state)
+
+	_, err = ParseDataFlowState("RUNNING")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
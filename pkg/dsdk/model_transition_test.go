@@ -786,3 +786,33 @@ func TestDataFlow_TransitionFromZeroTimestamp(t *testing.T) {
 		t.Errorf("expected state count 1, got %d", df.StateCount)
 	}
 }
+
+func TestDataFlow_TransitionsAreRecorded(t *testing.T) {
+	df := &DataFlow{ID: "flow123", State: Started}
+	df.SetTransitionSource("message123", "did:web:consumer.com")
+
+	if err := df.TransitionToSuspended("maintenance"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := df.TransitionToSuspended("maintenance"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := df.TransitionToCompleted(); err == nil {
+		t.Fatal("expected invalid transition")
+	}
+
+	transitions := df.TakeTransitions()
+	if len(transitions) != 1 {
+		t.Fatalf("expected 1 transition, got %d", len(transitions))
+	}
+	record := transitions[0]
+	if record.FlowID != "flow123" || record.From != Started || record.To != Suspended || record.Reason != "maintenance" {
+		t.Errorf("unexpected transition %+v", record)
+	}
+	if record.MessageID != "message123" || record.Caller != "did:web:consumer.com" || record.Timestamp <= 0 {
+		t.Errorf("unexpected transition source %+v", record)
+	}
+	if len(df.TakeTransitions()) != 0 {
+		t.Error("expected pending transitions to be cleared")
+	}
+}
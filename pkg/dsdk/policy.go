@@ -0,0 +1,160 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PolicyEvaluator verifies that the agreement of a flow permits the transfer, e.g. that the agreement is within its
+// validity window and the purpose is allowed. It returns an error wrapping ErrPolicyViolation if the policy does not
+// hold; other errors are reported as evaluation failures and do not change the flow.
+type PolicyEvaluator func(ctx context.Context, flow *DataFlow) error
+
+// PolicyAction is applied to started flows whose policy no longer holds
+type PolicyAction int
+
+const (
+	// SuspendOnViolation suspends the flow, so that it can be resumed once the policy holds again
+	SuspendOnViolation PolicyAction = iota
+	// TerminateOnViolation terminates the flow and notifies the control plane
+	TerminateOnViolation
+)
+
+// WithPolicyEvaluator evaluates the policy of the agreement before a flow is prepared, started or resumed. Messages for
+// flows violating the policy are rejected with ErrPolicyViolation before the processor runs.
+func WithPolicyEvaluator(evaluator PolicyEvaluator) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.policyEvaluator = evaluator
+	}
+}
+
+// WithPolicyReevaluation registers a worker that re-evaluates the policy of the started flows owned by the runtime at
+// the interval and applies the action to the flows violating it. Requires WithPolicyEvaluator and a positive interval.
+func WithPolicyReevaluation(interval time.Duration, action PolicyAction) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		sdk.policyReevaluation = true
+		sdk.policyAction = action
+		sdk.policyInterval = interval
+		sdk.workers = append(sdk.workers, func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				if err := sdk.ReevaluatePolicies(ctx); err != nil {
+					sdk.Monitor.Printf("Error re-evaluating data flow policies: %v\n", err)
+				}
+			}
+		})
+	}
+}
+
+// validatePolicyReevaluation rejects a re-evaluation without evaluator or with an interval the worker cannot tick at
+func (dsdk *DataPlaneSDK) validatePolicyReevaluation() error {
+	if !dsdk.policyReevaluation {
+		return nil
+	}
+	if dsdk.policyEvaluator == nil {
+		return errors.New("policy re-evaluation requires a policy evaluator")
+	}
+	if dsdk.policyInterval <= 0 {
+		return fmt.Errorf("policy re-evaluation interval must be positive: %s", dsdk.policyInterval)
+	}
+	return nil
+}
+
+// evaluatePolicy returns an error if the policy evaluator rejects the flow
+func (dsdk *DataPlaneSDK) evaluatePolicy(ctx context.Context, flow *DataFlow) error {
+	if dsdk.policyEvaluator == nil {
+		return nil
+	}
+	if err := dsdk.policyEvaluator(ctx, flow); err != nil {
+		if errors.Is(err, ErrPolicyViolation) {
+			return fmt.Errorf("agreement %s of data flow %s: %w", flow.AgreementID, flow.ID, err)
+		}
+		return fmt.Errorf("evaluating policy of data flow %s: %w", flow.ID, err)
+	}
+	return nil
+}
+
+// ReevaluatePolicies evaluates the policy of the started flows owned by the runtime and suspends or terminates the flows
+// violating it, depending on the configured PolicyAction. The suspend or terminate handler is invoked for each revoked
+// flow; if it fails, the flow is left unchanged and evaluated again in the next run. The control plane is notified of
+// the suspension or termination once it has been committed.
+func (dsdk *DataPlaneSDK) ReevaluatePolicies(ctx context.Context) error {
+	if dsdk.policyEvaluator == nil {
+		return nil
+	}
+	flows, err := dsdk.List(ctx, FlowQuery{States: []DataFlowState{Started}, RuntimeID: dsdk.runtimeID})
+	if err != nil {
+		return fmt.Errorf("re-evaluating data flow policies: %w", err)
+	}
+	var errs []error
+	for _, flow := range flows {
+		if err := dsdk.reevaluatePolicy(ctx, flow.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (dsdk *DataPlaneSDK) reevaluatePolicy(ctx context.Context, processID string) error {
+	op := newOperation(ctx, "")
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return fmt.Errorf("re-evaluating policy of data flow %s: %w", processID, err)
+		}
+		op.attribute(flow)
+		if flow.State != Started {
+			return nil // transitioned in the meantime
+		}
+		violation := dsdk.evaluatePolicy(ctx, flow)
+		if violation == nil || !errors.Is(violation, ErrPolicyViolation) {
+			return violation
+		}
+
+		if dsdk.policyAction == TerminateOnViolation {
+			if err := dsdk.onTerminate(ctx, flow); err != nil {
+				return fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
+			}
+			if err := dsdk.terminateFailed(ctx, op, flow, violation, dsdk.Store.Save); err != nil {
+				return err
+			}
+			dsdk.Monitor.Printf("Terminated data flow %s: %v\n", flow.ID, violation)
+			return nil
+		}
+		if err := dsdk.onSuspend(ctx, flow); err != nil {
+			return fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+		}
+		if err := flow.TransitionToSuspended(violation.Error()); err != nil {
+			return err
+		}
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return fmt.Errorf("suspending data flow %s: %w", flow.ID, err)
+		}
+		dsdk.stopFlowWorkerOnCommit(op, flow)
+		op.afterCommit(func() {
+			dsdk.notifyAsync(flow, SuspendedEvent, &DataFlowResponseMessage{State: Suspended, Error: flow.ErrorDetail})
+		})
+		dsdk.Monitor.Printf("Suspended data flow %s: %v\n", flow.ID, violation)
+		return nil
+	})
+}
@@ -0,0 +1,203 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var activeFlowsQuery = FlowQuery{States: []DataFlowState{Started}, RuntimeID: "runtime-1"}
+
+// expiredAgreements rejects the flows of the agreements
+func expiredAgreements(agreements ...string) PolicyEvaluator {
+	return func(ctx context.Context, flow *DataFlow) error {
+		for _, agreement := range agreements {
+			if flow.AgreementID == agreement {
+				return fmt.Errorf("%w: agreement expired", ErrPolicyViolation)
+			}
+		}
+		return nil
+	}
+}
+
+func Test_Policy_RejectsStart(t *testing.T) {
+	started := false
+	message := createStartMessage()
+	sdk, store := newLifecycleSdk(t,
+		WithPolicyEvaluator(expiredAgreements(message.AgreementID)),
+		WithStartProcessor(func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			started = true
+			return &DataFlowResponseMessage{State: Started}, nil
+		}))
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+
+	_, err := sdk.Start(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrPolicyViolation)
+	assert.Equal(t, http.StatusForbidden, NewProblemDetails(err).Status)
+	assert.False(t, started, "the processor must not run for rejected flows")
+}
+
+func Test_Policy_RejectsPrepare(t *testing.T) {
+	message := createPrepareMessage()
+	sdk, store := newLifecycleSdk(t, WithPolicyEvaluator(expiredAgreements(message.AgreementID)))
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+
+	_, err := sdk.Prepare(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrPolicyViolation)
+}
+
+func Test_Policy_EvaluationFailure(t *testing.T) {
+	sdk, store := newLifecycleSdk(t, WithPolicyEvaluator(func(context.Context, *DataFlow) error {
+		return errors.New("policy service unavailable")
+	}))
+	store.EXPECT().FindById(mock.Anything, "process123").Return(nil, ErrNotFound)
+
+	_, err := sdk.Prepare(context.Background(), createPrepareMessage())
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrPolicyViolation)
+}
+
+func Test_Policy_ReevaluationSuspends(t *testing.T) {
+	notifier := newRecordingNotifier()
+	suspended := false
+	sdk, store := newLifecycleSdk(t,
+		WithRuntimeID("runtime-1"),
+		WithCallbackNotifier(notifier),
+		WithPolicyEvaluator(expiredAgreements("expired")),
+		WithPolicyReevaluation(time.Hour, SuspendOnViolation),
+		WithSuspendProcessor(func(ctx context.Context, flow *DataFlow) error {
+			suspended = true
+			return nil
+		}))
+	valid := &DataFlow{ID: "valid", AgreementID: "valid", State: Started}
+	expired := &DataFlow{ID: "expired", AgreementID: "expired", State: Started}
+	store.EXPECT().Query(mock.Anything, activeFlowsQuery).Return(newSliceIterator(valid, expired), nil)
+	store.EXPECT().FindById(mock.Anything, "valid").Return(valid, nil)
+	store.EXPECT().FindById(mock.Anything, "expired").Return(expired, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.ID == "expired" && df.State == Suspended
+	})).Return(nil)
+
+	err := sdk.ReevaluatePolicies(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, suspended)
+	assert.Equal(t, Started, valid.State)
+	assert.Contains(t, expired.ErrorDetail, "agreement expired")
+	event := notifier.await(t)
+	assert.Equal(t, SuspendedEvent, event.name)
+	assert.Equal(t, Suspended, event.message.State)
+}
+
+func Test_Policy_ReevaluationTerminates(t *testing.T) {
+	notifier := newRecordingNotifier()
+	terminated := false
+	sdk, store := newLifecycleSdk(t,
+		WithRuntimeID("runtime-1"),
+		WithCallbackNotifier(notifier),
+		WithPolicyEvaluator(expiredAgreements("expired")),
+		WithPolicyReevaluation(time.Hour, TerminateOnViolation),
+		WithTerminateProcessor(func(ctx context.Context, flow *DataFlow) error {
+			terminated = true
+			return nil
+		}))
+	expired := &DataFlow{ID: "expired", AgreementID: "expired", State: Started}
+	store.EXPECT().Query(mock.Anything, activeFlowsQuery).Return(newSliceIterator(expired), nil)
+	store.EXPECT().FindById(mock.Anything, "expired").Return(expired, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Terminated
+	})).Return(nil)
+
+	err := sdk.ReevaluatePolicies(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, terminated)
+	assert.Equal(t, TerminatedEvent, notifier.await(t).name)
+}
+
+func Test_Policy_RejectsResume(t *testing.T) {
+	resumed := false
+	sdk, store := newLifecycleSdk(t,
+		WithPolicyEvaluator(expiredAgreements("expired")),
+		WithResumeProcessor(func(ctx context.Context, flow *DataFlow) error {
+			resumed = true
+			return nil
+		}))
+	expired := &DataFlow{ID: "expired", AgreementID: "expired", State: Suspended}
+	store.EXPECT().FindById(mock.Anything, "expired").Return(expired, nil)
+
+	err := sdk.Resume(context.Background(), "expired")
+
+	assert.ErrorIs(t, err, ErrPolicyViolation)
+	assert.False(t, resumed, "the processor must not run for flows violating the policy")
+	assert.Equal(t, Suspended, expired.State)
+}
+
+func Test_Policy_BulkResumeSkipsViolations(t *testing.T) {
+	sdk, store := newLifecycleSdk(t, WithPolicyEvaluator(expiredAgreements("expired")))
+	valid := &DataFlow{ID: "valid", AgreementID: "valid", CounterPartyID: "counterparty-1", State: Suspended}
+	expired := &DataFlow{ID: "expired", AgreementID: "expired", CounterPartyID: "counterparty-1", State: Suspended}
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: []DataFlowState{Suspended}, CounterPartyID: "counterparty-1"}).
+		Return(newSliceIterator(valid, expired), nil)
+	store.EXPECT().FindById(mock.Anything, "valid").Return(valid, nil)
+	store.EXPECT().FindById(mock.Anything, "expired").Return(expired, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.ID == "valid" && df.State == Started
+	})).Return(nil)
+
+	results, err := sdk.BulkResume(context.Background(), FlowSelector{CounterPartyID: "counterparty-1"})
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, BulkResult{DataFlowID: "valid"}, results[0])
+	assert.Contains(t, results[1].Error, "agreement expired")
+	assert.Equal(t, Started, valid.State)
+	assert.Equal(t, Suspended, expired.State)
+}
+
+func Test_Policy_ReevaluationValidation(t *testing.T) {
+	evaluator := WithPolicyEvaluator(expiredAgreements("expired"))
+	tests := []struct {
+		name    string
+		options []DataPlaneSDKOption
+		err     string
+	}{
+		{"without evaluator", []DataPlaneSDKOption{WithPolicyReevaluation(time.Hour, SuspendOnViolation)}, "requires a policy evaluator"},
+		{"zero interval", []DataPlaneSDKOption{evaluator, WithPolicyReevaluation(0, SuspendOnViolation)}, "must be positive"},
+		{"negative interval", []DataPlaneSDKOption{evaluator, WithPolicyReevaluation(-time.Second, TerminateOnViolation)}, "must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]DataPlaneSDKOption{
+				WithStore(NewMockDataplaneStore(t)),
+				WithTransactionContext(&mockTrxContext{}),
+			}, tt.options...)
+
+			_, err := NewDataPlaneSDK(options...)
+
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
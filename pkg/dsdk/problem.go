@@ -0,0 +1,134 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem responses.
+const ProblemContentType = "application/problem+json"
+
+// CorrelationIDHeader carries the correlation ID of a request. If the client does not send one, it is generated.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// Problem type URIs, one per sentinel error.
+const (
+	problemTypeBase              = "https://github.com/metaform/dataplane-sdk-go/problems/"
+	ProblemTypeValidation        = problemTypeBase + "validation"
+	ProblemTypeInvalidInput      = problemTypeBase + "invalid-input"
+	ProblemTypeInvalidTransition = problemTypeBase + "invalid-transition"
+	ProblemTypeNotFound          = problemTypeBase + "not-found"
+	ProblemTypeConflict          = problemTypeBase + "conflict"
+	ProblemTypeNotSupported      = problemTypeBase + "not-supported"
+	ProblemTypeUnauthorized      = problemTypeBase + "unauthorized"
+	ProblemTypePolicyViolation   = problemTypeBase + "policy-violation"
+	ProblemTypeLimitExceeded     = problemTypeBase + "limit-exceeded"
+	ProblemTypeUnavailable       = problemTypeBase + "unavailable"
+	ProblemTypeInternal          = problemTypeBase + "internal"
+)
+
+// ProblemDetails is an RFC 7807 problem response. State contains the current data flow state if the problem was
+// caused by it.
+type ProblemDetails struct {
+	Type          string      `json:"type"`
+	Title         string      `json:"title"`
+	Status        int         `json:"status"`
+	Detail        string      `json:"detail,omitempty"`
+	Instance      string      `json:"instance,omitempty"`
+	CorrelationID string      `json:"correlationID"`
+	State         string      `json:"state,omitempty"`
+	Violations    []Violation `json:"violations,omitempty"`
+}
+
+// Violation describes a validation failure of a single message property.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// NewProblemDetails maps an error to a problem based on the sentinel error it wraps.
+func NewProblemDetails(err error) *ProblemDetails {
+	var recorded *recordedFailure
+	if errors.As(err, &recorded) {
+		problem := recorded.problem
+		return &problem
+	}
+	problem := &ProblemDetails{Detail: err.Error()}
+	switch {
+	case errors.Is(err, ErrValidation):
+		problem.Type, problem.Title, problem.Status = ProblemTypeValidation, "Validation failed", http.StatusBadRequest
+		problem.Violations = violations(err)
+	case errors.Is(err, ErrInvalidTransition):
+		problem.Type, problem.Title, problem.Status = ProblemTypeInvalidTransition, "Invalid state transition", http.StatusBadRequest
+	case errors.Is(err, ErrInvalidInput):
+		problem.Type, problem.Title, problem.Status = ProblemTypeInvalidInput, "Invalid input", http.StatusBadRequest
+	case errors.Is(err, ErrNotFound):
+		problem.Type, problem.Title, problem.Status = ProblemTypeNotFound, "Not found", http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		problem.Type, problem.Title, problem.Status = ProblemTypeConflict, "Conflict", http.StatusConflict
+	case errors.Is(err, ErrUnauthorized):
+		problem.Type, problem.Title, problem.Status = ProblemTypeUnauthorized, "Unauthorized", http.StatusUnauthorized
+	case errors.Is(err, ErrPolicyViolation):
+		problem.Type, problem.Title, problem.Status = ProblemTypePolicyViolation, "Policy violation", http.StatusForbidden
+	case errors.Is(err, ErrLimitExceeded):
+		problem.Type, problem.Title, problem.Status = ProblemTypeLimitExceeded, "Limit exceeded", http.StatusTooManyRequests
+	case errors.Is(err, ErrShuttingDown):
+		problem.Type, problem.Title, problem.Status = ProblemTypeUnavailable, "Service unavailable", http.StatusServiceUnavailable
+	case errors.Is(err, ErrNotSupported):
+		problem.Type, problem.Title, problem.Status = ProblemTypeNotSupported, "Not supported", http.StatusNotImplemented
+	default:
+		problem.Type, problem.Title, problem.Status = ProblemTypeInternal, "Internal error", http.StatusInternalServerError
+		problem.Detail = fmt.Sprintf("Error processing flow: %s", err)
+	}
+
+	var stateErr *FlowStateError
+	if errors.As(err, &stateErr) {
+		problem.State = stateErr.State.String()
+	}
+	return problem
+}
+
+func violations(err error) []Violation {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	result := make([]Violation, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		result = append(result, Violation{
+			Field:   fieldPath(fe.Namespace()),
+			Message: fmt.Sprintf("failed on the '%s' constraint", fe.Tag()),
+		})
+	}
+	return result
+}
+
+// fieldPath converts a validator namespace such as "DataFlowStartMessage.DataFlowBaseMessage.transferType.flowType"
+// to a JSON path. Segments naming Go types (the root and embedded structs) are dropped; JSON properties are lower case.
+func fieldPath(namespace string) string {
+	var segments []string
+	for _, segment := range strings.Split(namespace, ".") {
+		if segment == "" || unicode.IsUpper([]rune(segment)[0]) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return strings.Join(segments, ".")
+}
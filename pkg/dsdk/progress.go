@@ -0,0 +1,141 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UpdateProgress adds the given increments to the progress of a data flow. If the store implements ProgressStore, the
+// update is applied directly; otherwise the flow is loaded and saved.
+func (dsdk *DataPlaneSDK) UpdateProgress(ctx context.Context, processID string, update ProgressUpdate) error {
+	if processID == "" {
+		return errors.New("processID cannot be empty")
+	}
+	timestamp := time.Now().UnixMilli()
+
+	return dsdk.execute(ctx, func(ctx context.Context) error {
+		if ps, ok := dsdk.Store.(ProgressStore); ok {
+			if err := ps.UpdateProgress(ctx, processID, update, timestamp); err != nil {
+				return fmt.Errorf("updating progress of data flow %s: %w", processID, err)
+			}
+			return nil
+		}
+
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return fmt.Errorf("updating progress of data flow %s: %w", processID, err)
+		}
+		flow.Progress.Apply(update, timestamp)
+		if err := dsdk.Store.Save(ctx, flow); err != nil {
+			return fmt.Errorf("updating progress of data flow %s: %w", processID, err)
+		}
+		return nil
+	})
+}
+
+// ProgressTracker accumulates progress in memory so transfers can report every chunk or message without a store
+// round-trip. Accumulated increments are flushed periodically and when the tracker is closed.
+type ProgressTracker struct {
+	sdk       *DataPlaneSDK
+	processID string
+
+	bytes    atomic.Int64
+	messages atomic.Int64
+	expected atomic.Int64
+
+	flushMu   sync.Mutex
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewProgressTracker creates a tracker for the given flow that flushes every interval. If interval is not positive,
+// progress is only written on Flush and Close.
+func (dsdk *DataPlaneSDK) NewProgressTracker(processID string, interval time.Duration) *ProgressTracker {
+	tracker := &ProgressTracker{
+		sdk:       dsdk,
+		processID: processID,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if interval <= 0 {
+		close(tracker.done)
+		return tracker
+	}
+	go tracker.run(interval)
+	return tracker
+}
+
+// Add records transferred bytes and messages.
+func (t *ProgressTracker) Add(bytes int64, messages int64) {
+	t.bytes.Add(bytes)
+	t.messages.Add(messages)
+}
+
+// SetExpectedTotal records the expected total of the transfer.
+func (t *ProgressTracker) SetExpectedTotal(total int64) {
+	t.expected.Store(total)
+}
+
+// Flush writes the accumulated progress. On failure, the increments are retained for the next flush.
+func (t *ProgressTracker) Flush(ctx context.Context) error {
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
+
+	update := ProgressUpdate{
+		Bytes:         t.bytes.Swap(0),
+		Messages:      t.messages.Swap(0),
+		ExpectedTotal: t.expected.Swap(0),
+	}
+	if update == (ProgressUpdate{}) {
+		return nil
+	}
+	if err := t.sdk.UpdateProgress(ctx, t.processID, update); err != nil {
+		t.bytes.Add(update.Bytes)
+		t.messages.Add(update.Messages)
+		t.expected.CompareAndSwap(0, update.ExpectedTotal)
+		return err
+	}
+	return nil
+}
+
+// Close stops periodic flushing and writes the remaining progress.
+func (t *ProgressTracker) Close(ctx context.Context) error {
+	t.closeOnce.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+	return t.Flush(ctx)
+}
+
+func (t *ProgressTracker) run(interval time.Duration) {
+	defer close(t.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+			if err := t.Flush(context.Background()); err != nil {
+				t.sdk.Monitor.Printf("Error flushing progress of data flow %s: %v\n", t.processID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneSDK_UpdateProgress(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	ctx := context.Background()
+	flow := &DataFlow{ID: "flow123", State: Started, Progress: TransferProgress{BytesTransferred: 10, ExpectedTotal: 100}}
+	store.EXPECT().FindById(ctx, "flow123").Return(flow, nil)
+	store.EXPECT().Save(ctx, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.Progress.BytesTransferred == 30 &&
+			df.Progress.MessagesTransferred == 2 &&
+			df.Progress.ExpectedTotal == 100 &&
+			df.Progress.LastActivity > 0
+	})).Return(nil)
+
+	err := dsdk.UpdateProgress(ctx, "flow123", ProgressUpdate{Bytes: 20, Messages: 2})
+
+	assert.NoError(t, err)
+}
+
+func Test_DataPlaneSDK_UpdateProgress_NotFound(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	ctx := context.Background()
+	store.EXPECT().FindById(ctx, "flow123").Return(nil, ErrNotFound)
+
+	err := dsdk.UpdateProgress(ctx, "flow123", ProgressUpdate{Bytes: 20})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_DataPlaneSDK_UpdateProgress_ProgressStore(t *testing.T) {
+	store := &progressStore{MockDataplaneStore: NewMockDataplaneStore(t)}
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	err := dsdk.UpdateProgress(context.Background(), "flow123", ProgressUpdate{Bytes: 20})
+
+	assert.NoError(t, err)
+	require.Len(t, store.updates, 1)
+	assert.Equal(t, int64(20), store.updates[0].Bytes)
+}
+
+func Test_ProgressTracker_Flush(t *testing.T) {
+	store := &progressStore{MockDataplaneStore: NewMockDataplaneStore(t)}
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	tracker := dsdk.NewProgressTracker("flow123", 0)
+	tracker.SetExpectedTotal(100)
+	tracker.Add(10, 1)
+	tracker.Add(15, 1)
+
+	require.NoError(t, tracker.Flush(context.Background()))
+	// nothing accumulated, no update
+	require.NoError(t, tracker.Close(context.Background()))
+
+	require.Len(t, store.updates, 1)
+	assert.Equal(t, ProgressUpdate{Bytes: 25, Messages: 2, ExpectedTotal: 100}, store.updates[0])
+}
+
+func Test_ProgressTracker_RetainsOnError(t *testing.T) {
+	store := &progressStore{MockDataplaneStore: NewMockDataplaneStore(t), err: errors.New("unavailable")}
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+	}
+
+	tracker := dsdk.NewProgressTracker("flow123", 0)
+	tracker.Add(10, 1)
+
+	assert.Error(t, tracker.Flush(context.Background()))
+
+	store.err = nil
+	tracker.Add(5, 0)
+	assert.NoError(t, tracker.Close(context.Background()))
+
+	require.Len(t, store.updates, 1)
+	assert.Equal(t, ProgressUpdate{Bytes: 15, Messages: 1}, store.updates[0])
+}
+
+// progressStore records progress updates and delegates all other operations to the mock store
+type progressStore struct {
+	*MockDataplaneStore
+	updates []ProgressUpdate
+	err     error
+}
+
+func (p *progressStore) UpdateProgress(_ context.Context, _ string, update ProgressUpdate, _ int64) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.updates = append(p.updates, update)
+	return nil
+}
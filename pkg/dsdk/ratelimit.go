@@ -0,0 +1,139 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxIdleBuckets is the number of buckets above which buckets that have been refilled are discarded
+const maxIdleBuckets = 10_000
+
+// RateLimiter decides whether a request of a client identified by the key is admitted. If not, it returns the time
+// after which the client may retry.
+type RateLimiter interface {
+	Allow(key string) (bool, time.Duration)
+}
+
+// RateLimitKey identifies the client a request is counted against, e.g. the authenticated counterparty
+type RateLimitKey func(r *http.Request) string
+
+// TokenBucketLimiter is a RateLimiter admitting a sustained rate of requests per second and key, with bursts of up to
+// burst requests. Limits are tracked in memory and apply to each instance separately.
+type TokenBucketLimiter struct {
+	rate    float64
+	burst   float64
+	now     func() time.Time
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewTokenBucketLimiter creates a limiter with the rate in requests per second and the burst size
+func NewTokenBucketLimiter(rate float64, burst int) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+func (l *TokenBucketLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	bucket, found := l.buckets[key]
+	if !found {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.discardRefilled(now)
+		}
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[key] = bucket
+	}
+	bucket.refill(now, l.rate, l.burst)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	if l.rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+}
+
+// discardRefilled removes the buckets that are full, which behave like new buckets
+func (l *TokenBucketLimiter) discardRefilled(now time.Time) {
+	for key, bucket := range l.buckets {
+		bucket.refill(now, l.rate, l.burst)
+		if bucket.tokens >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time, rate float64, burst float64) {
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+}
+
+// WithRateLimiter limits the signaling requests of each client, rejecting requests exceeding the limit with 429. The
+// key identifies the client; if nil, the caller resolved by WithCallerResolver is used, or the remote address if no
+// caller resolver is configured.
+func WithRateLimiter(limiter RateLimiter, key RateLimitKey) DataPlaneApiOption {
+	return func(api *DataPlaneApi) {
+		api.rateLimiter = limiter
+		api.rateLimitKey = key
+	}
+}
+
+// allowRequest applies the rate limit to a signaling request, writing a problem response if it is rejected
+func (d *DataPlaneApi) allowRequest(w http.ResponseWriter, r *http.Request) bool {
+	if d.rateLimiter == nil {
+		return true
+	}
+	allowed, retryAfter := d.rateLimiter.Allow(d.rateLimitKeyOf(r))
+	if allowed {
+		return true
+	}
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if retryAfter < 0 || seconds > math.MaxInt32 {
+		seconds = math.MaxInt32
+	}
+	w.Header().Set("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+	d.handleError(ErrLimitExceeded, w, r)
+	return false
+}
+
+func (d *DataPlaneApi) rateLimitKeyOf(r *http.Request) string {
+	switch {
+	case d.rateLimitKey != nil:
+		return d.rateLimitKey(r)
+	case d.callerResolver != nil:
+		return d.callerResolver(r)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,62 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TokenBucketLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := NewTokenBucketLimiter(2, 2)
+	limiter.now = func() time.Time { return now }
+
+	allowed, _ := limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, retryAfter := limiter.Allow("a")
+	assert.False(t, allowed, "the burst must be exhausted")
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	allowed, _ = limiter.Allow("b")
+	assert.True(t, allowed, "keys must be limited separately")
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed, "tokens must be refilled at the rate")
+}
+
+func Test_DataPlaneApi_RateLimit(t *testing.T) {
+	sdk := &DataPlaneSDK{Store: NewMockDataplaneStore(t), TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	api := NewDataPlaneApi(sdk, WithRateLimiter(NewTokenBucketLimiter(0.5, 1), func(r *http.Request) string {
+		return r.Header.Get("X-Counterparty")
+	}))
+
+	first := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/dataflows/start", http.NoBody)
+	req.Header.Set("X-Counterparty", "counterparty-1")
+	api.Start(first, req)
+	assert.Equal(t, http.StatusBadRequest, first.Code, "the first request must reach the handler")
+
+	second := httptest.NewRecorder()
+	api.Start(second, req)
+	problem := decodeProblem(t, second, http.StatusTooManyRequests)
+	assert.Equal(t, ProblemTypeLimitExceeded, problem.Type)
+	assert.Equal(t, "2", second.Header().Get("Retry-After"))
+}
@@ -0,0 +1,110 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RecoveryError reports the flows whose recovery failed on startup.
+type RecoveryError struct {
+	// Failed maps the IDs of the flows to the errors returned by the recovery handler
+	Failed map[string]error
+	// Terminated contains the IDs of the failed flows that were terminated
+	Terminated []string
+}
+
+func (e *RecoveryError) Error() string {
+	ids := slices.Sorted(maps.Keys(e.Failed))
+	causes := make([]string, 0, len(ids))
+	for _, id := range ids {
+		causes = append(causes, fmt.Sprintf("%s: %v", id, e.Failed[id]))
+	}
+	return fmt.Sprintf("recovery failed for %d data flows: %s", len(ids), strings.Join(causes, "; "))
+}
+
+func (e *RecoveryError) Unwrap() []error {
+	return slices.Collect(maps.Values(e.Failed))
+}
+
+// Recover invokes the recovery handler for every STARTING or STARTED flow owned by the runtime, so that data planes can
+// re-establish publishers, proxies or transfers lost with a restart. Modifications of the flow by the handler are
+// persisted. If the handler fails for a flow, its changes are rolled back and the flow is reported in a RecoveryError.
+// With WithTerminateUnrecoverable the flow is also terminated through the terminate handler and the control plane
+// notified. Recover is called by Startup if a recovery handler is configured.
+func (dsdk *DataPlaneSDK) Recover(ctx context.Context) error {
+	if dsdk.onRecover == nil {
+		return nil
+	}
+	flows, err := dsdk.List(ctx, FlowQuery{States: []DataFlowState{Starting, Started}, RuntimeID: dsdk.runtimeID})
+	if err != nil {
+		return fmt.Errorf("recovering data flows: %w", err)
+	}
+
+	failures := &RecoveryError{Failed: make(map[string]error)}
+	for _, flow := range flows {
+		if err := dsdk.recoverFlow(ctx, flow.ID); err != nil {
+			dsdk.Monitor.Printf("Error recovering data flow %s: %v\n", flow.ID, err)
+			failures.Failed[flow.ID] = err
+			if !dsdk.terminateUnrecoverable {
+				continue
+			}
+			if err := dsdk.terminateUnrecovered(ctx, flow.ID, err); err != nil {
+				dsdk.Monitor.Printf("Error terminating unrecoverable data flow %s: %v\n", flow.ID, err)
+				continue
+			}
+			failures.Terminated = append(failures.Terminated, flow.ID)
+		}
+	}
+	if len(failures.Failed) > 0 {
+		return failures
+	}
+	dsdk.Monitor.Printf("Recovered %d data flows\n", len(flows))
+	return nil
+}
+
+func (dsdk *DataPlaneSDK) recoverFlow(ctx context.Context, processID string) error {
+	op := newOperation(ctx, "")
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return err
+		}
+		if flow.State != Starting && flow.State != Started {
+			return nil // transitioned in the meantime
+		}
+		if err := dsdk.onRecover(withOperation(ctx, op), flow); err != nil {
+			return err
+		}
+		return dsdk.Store.Save(ctx, flow)
+	})
+}
+
+func (dsdk *DataPlaneSDK) terminateUnrecovered(ctx context.Context, processID string, cause error) error {
+	op := newOperation(ctx, "")
+	return dsdk.transact(ctx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, processID)
+		if err != nil {
+			return err
+		}
+		op.attribute(flow)
+		if err := dsdk.onTerminate(ctx, flow); err != nil {
+			return fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
+		}
+		return dsdk.terminateFailed(ctx, op, flow, fmt.Errorf("recovery failed: %w", cause), dsdk.Store.Save)
+	})
+}
@@ -0,0 +1,118 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var recoveryQuery = FlowQuery{States: []DataFlowState{Starting, Started}, RuntimeID: "runtime-1"}
+
+func Test_DataPlaneSDK_Startup_RecoversFlows(t *testing.T) {
+	var recovered []string
+	sdk, store := newLifecycleSdk(t, WithRuntimeID("runtime-1"), WithRecoveryHandler(func(ctx context.Context, flow *DataFlow) error {
+		recovered = append(recovered, flow.ID)
+		flow.ErrorDetail = ""
+		return nil
+	}))
+	started := &DataFlow{ID: "started", State: Started, RuntimeID: "runtime-1", ErrorDetail: "restarted"}
+	starting := &DataFlow{ID: "starting", State: Starting, RuntimeID: "runtime-1"}
+	store.EXPECT().Query(mock.Anything, recoveryQuery).Return(newSliceIterator(started, starting), nil)
+	store.EXPECT().FindById(mock.Anything, "started").Return(started, nil)
+	store.EXPECT().FindById(mock.Anything, "starting").Return(starting, nil)
+	store.EXPECT().Save(mock.Anything, started).Return(nil)
+	store.EXPECT().Save(mock.Anything, starting).Return(nil)
+
+	err := sdk.Startup(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"started", "starting"}, recovered)
+	assert.Empty(t, started.ErrorDetail)
+}
+
+func Test_DataPlaneSDK_Recover_ReportsFailures(t *testing.T) {
+	cause := errors.New("publisher unavailable")
+	sdk, store := newLifecycleSdk(t, WithRuntimeID("runtime-1"), WithRecoveryHandler(func(ctx context.Context, flow *DataFlow) error {
+		if flow.ID == "broken" {
+			return cause
+		}
+		return nil
+	}))
+	ok := &DataFlow{ID: "ok", State: Started}
+	broken := &DataFlow{ID: "broken", State: Started}
+	store.EXPECT().Query(mock.Anything, recoveryQuery).Return(newSliceIterator(ok, broken), nil)
+	store.EXPECT().FindById(mock.Anything, "ok").Return(ok, nil)
+	store.EXPECT().FindById(mock.Anything, "broken").Return(broken, nil)
+	store.EXPECT().Save(mock.Anything, ok).Return(nil)
+
+	err := sdk.Recover(context.Background())
+
+	var recoveryErr *RecoveryError
+	require.ErrorAs(t, err, &recoveryErr)
+	assert.Equal(t, map[string]error{"broken": cause}, recoveryErr.Failed)
+	assert.Empty(t, recoveryErr.Terminated)
+	assert.ErrorIs(t, err, cause)
+	assert.EqualError(t, err, "recovery failed for 1 data flows: broken: publisher unavailable")
+}
+
+func Test_DataPlaneSDK_Recover_TerminatesUnrecoverable(t *testing.T) {
+	notifier := newRecordingNotifier()
+	released := false
+	sdk, store := newLifecycleSdk(t,
+		WithRuntimeID("runtime-1"),
+		WithTerminateUnrecoverable(true),
+		WithCallbackNotifier(notifier),
+		WithTerminateProcessor(func(ctx context.Context, flow *DataFlow) error {
+			released = true
+			return nil
+		}),
+		WithRecoveryHandler(func(ctx context.Context, flow *DataFlow) error {
+			return errors.New("publisher unavailable")
+		}))
+	broken := &DataFlow{ID: "broken", State: Started}
+	store.EXPECT().Query(mock.Anything, recoveryQuery).Return(newSliceIterator(broken), nil)
+	store.EXPECT().FindById(mock.Anything, "broken").Return(broken, nil)
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(df *DataFlow) bool {
+		return df.State == Terminated && df.ErrorDetail == "recovery failed: publisher unavailable"
+	})).Return(nil)
+
+	err := sdk.Recover(context.Background())
+
+	var recoveryErr *RecoveryError
+	require.ErrorAs(t, err, &recoveryErr)
+	assert.Equal(t, []string{"broken"}, recoveryErr.Terminated)
+	assert.True(t, released, "the terminate handler must release the resources of the flow")
+	assert.Equal(t, TerminatedEvent, notifier.await(t).name)
+}
+
+func Test_DataPlaneSDK_Recover_WithoutHandler(t *testing.T) {
+	sdk, _ := newLifecycleSdk(t)
+
+	// no query expected
+	assert.NoError(t, sdk.Recover(context.Background()))
+}
+
+func Test_NewDataPlaneSDK_RecoveryHandlerRequiresRuntimeID(t *testing.T) {
+	_, err := NewDataPlaneSDK(
+		WithStore(NewMockDataplaneStore(t)),
+		WithTransactionContext(&mockTrxContext{}),
+		WithRecoveryHandler(func(ctx context.Context, flow *DataFlow) error { return nil }))
+
+	require.ErrorContains(t, err, "runtime ID is required")
+}
@@ -0,0 +1,308 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetentionInterval  = time.Hour
+	defaultRetentionBatchSize = 100
+)
+
+// LeaderLock ensures that a background task runs on only one instance of a multi-instance deployment at a time.
+type LeaderLock interface {
+	// TryLock acquires the lock without blocking. Returns false if another instance holds it.
+	TryLock(ctx context.Context) (bool, error)
+	// Unlock releases a lock acquired with TryLock.
+	Unlock(ctx context.Context) error
+}
+
+// RetentionPolicy configures how long data flows are kept after entering a terminal state. A zero TTL keeps flows in
+// that state forever.
+type RetentionPolicy struct {
+	CompletedTTL  time.Duration
+	TerminatedTTL time.Duration
+}
+
+// Archiver is invoked with every flow before it is purged, e.g. to copy it to cold storage. If it returns an error,
+// the flow is kept and retried in the next run. It runs inside the purge transaction, so a flow of a batch that is rolled
+// back is archived again in the next run; archivers must therefore be idempotent, e.g. by keying on the flow ID.
+type Archiver func(ctx context.Context, flow *DataFlow) error
+
+// RetentionMetrics is a snapshot of the counters of a RetentionJanitor. In dry-run mode, Purged counts the flows that
+// would have been purged.
+type RetentionMetrics struct {
+	Runs         int64
+	SkippedRuns  int64
+	Purged       int64
+	Archived     int64
+	Failed       int64
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    string
+}
+
+// RetentionJanitor periodically purges data flows in terminal states once their TTL has expired. Flows are deleted in
+// batches through DataplaneStore.Delete. If a LeaderLock is configured, runs are skipped while another instance holds
+// the lock.
+type RetentionJanitor struct {
+	store      DataplaneStore
+	trxContext TransactionContext
+	policy     RetentionPolicy
+	interval   time.Duration
+	batchSize  int
+	dryRun     bool
+	archiver   Archiver
+	lock       LeaderLock
+	monitor    LogMonitor
+
+	mu      sync.Mutex
+	metrics RetentionMetrics
+}
+
+// RetentionOption configures a RetentionJanitor
+type RetentionOption func(*RetentionJanitor)
+
+// WithRetentionInterval sets the interval between runs
+func WithRetentionInterval(interval time.Duration) RetentionOption {
+	return func(j *RetentionJanitor) {
+		j.interval = interval
+	}
+}
+
+// WithBatchSize sets the maximum number of flows deleted per transaction
+func WithBatchSize(size int) RetentionOption {
+	return func(j *RetentionJanitor) {
+		j.batchSize = size
+	}
+}
+
+// WithDryRun logs and counts expired flows without deleting them
+func WithDryRun(dryRun bool) RetentionOption {
+	return func(j *RetentionJanitor) {
+		j.dryRun = dryRun
+	}
+}
+
+// WithArchiver sets a function invoked with every flow before it is purged
+func WithArchiver(archiver Archiver) RetentionOption {
+	return func(j *RetentionJanitor) {
+		j.archiver = archiver
+	}
+}
+
+// WithLeaderLock restricts runs to the instance holding the lock
+func WithLeaderLock(lock LeaderLock) RetentionOption {
+	return func(j *RetentionJanitor) {
+		j.lock = lock
+	}
+}
+
+// WithRetentionMonitor sets the monitor used to log purged flows and errors
+func WithRetentionMonitor(monitor LogMonitor) RetentionOption {
+	return func(j *RetentionJanitor) {
+		j.monitor = monitor
+	}
+}
+
+// NewRetentionJanitor creates a janitor for the store. Call Run to start it. Returns an error if the interval or the
+// batch size is not positive.
+func NewRetentionJanitor(store DataplaneStore, trxContext TransactionContext, policy RetentionPolicy, options ...RetentionOption) (*RetentionJanitor, error) {
+	janitor := &RetentionJanitor{
+		store:      store,
+		trxContext: trxContext,
+		policy:     policy,
+		interval:   defaultRetentionInterval,
+		batchSize:  defaultRetentionBatchSize,
+		monitor:    defaultLogMonitor{},
+	}
+	for _, opt := range options {
+		opt(janitor)
+	}
+	if janitor.interval <= 0 {
+		return nil, fmt.Errorf("retention interval must be positive: %s", janitor.interval)
+	}
+	if janitor.batchSize <= 0 {
+		return nil, fmt.Errorf("retention batch size must be positive: %d", janitor.batchSize)
+	}
+	return janitor, nil
+}
+
+// Run purges expired flows at the configured interval until the context is cancelled.
+func (j *RetentionJanitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			j.monitor.Printf("Error purging expired data flows: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce performs a single retention run.
+func (j *RetentionJanitor) RunOnce(ctx context.Context) error {
+	if j.lock != nil {
+		acquired, err := j.lock.TryLock(ctx)
+		if err != nil {
+			return fmt.Errorf("acquiring retention lock: %w", err)
+		}
+		if !acquired {
+			j.record(func(m *RetentionMetrics) { m.SkippedRuns++ })
+			return nil
+		}
+		defer func() {
+			if err := j.lock.Unlock(ctx); err != nil {
+				j.monitor.Printf("Error releasing retention lock: %v\n", err)
+			}
+		}()
+	}
+
+	start := time.Now()
+	err := errors.Join(
+		j.purge(ctx, Completed, j.policy.CompletedTTL, start),
+		j.purge(ctx, Terminated, j.policy.TerminatedTTL, start),
+	)
+	j.record(func(m *RetentionMetrics) {
+		m.Runs++
+		m.LastRun = start
+		m.LastDuration = time.Since(start)
+		m.LastError = ""
+		if err != nil {
+			m.LastError = err.Error()
+		}
+	})
+	return err
+}
+
+// Metrics returns a snapshot of the janitor counters.
+func (j *RetentionJanitor) Metrics() RetentionMetrics {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.metrics
+}
+
+// purge deletes the expired flows of a state in batches until no expired flows are left. Flows that cannot be archived
+// are skipped for the rest of the run, so that they do not hide the expired flows after them.
+func (j *RetentionJanitor) purge(ctx context.Context, state DataFlowState, ttl time.Duration, now time.Time) error {
+	if ttl <= 0 {
+		return nil
+	}
+	query := FlowQuery{States: []DataFlowState{state}, StateTimestampBefore: now.Add(-ttl).UnixMilli()}
+	if j.dryRun {
+		return j.report(ctx, query)
+	}
+
+	skippedIDs := make(map[string]struct{})
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		// the skipped flows are returned again, so the batch is extended by them
+		query.Limit = j.batchSize + len(skippedIDs)
+		var purged, archived, found int
+		var skipped []string
+		err := j.trxContext.Execute(ctx, func(ctx context.Context) error {
+			purged, archived, skipped = 0, 0, nil
+			flows, err := j.find(ctx, query)
+			if err != nil {
+				return err
+			}
+			found = len(flows)
+			for _, flow := range flows {
+				if _, ok := skippedIDs[flow.ID]; ok {
+					continue
+				}
+				if j.archiver != nil {
+					if err := j.archiver(ctx, flow); err != nil {
+						j.monitor.Printf("Error archiving data flow %s, skipping it: %v\n", flow.ID, err)
+						skipped = append(skipped, flow.ID)
+						continue
+					}
+					archived++
+				}
+				if err := j.store.Delete(ctx, flow.ID); err != nil && !errors.Is(err, ErrNotFound) {
+					return fmt.Errorf("purging data flow %s: %w", flow.ID, err)
+				}
+				purged++
+			}
+			return nil
+		})
+		if err != nil {
+			j.record(func(m *RetentionMetrics) { m.Failed++ })
+			return err
+		}
+		// counters are recorded once the batch has been committed so that rolled-back batches are not counted
+		j.record(func(m *RetentionMetrics) {
+			m.Purged += int64(purged)
+			m.Archived += int64(archived)
+			m.Failed += int64(len(skipped))
+		})
+		for _, id := range skipped {
+			skippedIDs[id] = struct{}{}
+		}
+		if found < query.Limit {
+			return nil
+		}
+	}
+}
+
+// report logs and counts the flows matching the query without deleting them.
+func (j *RetentionJanitor) report(ctx context.Context, query FlowQuery) error {
+	return j.trxContext.Execute(ctx, func(ctx context.Context) error {
+		flows, err := j.find(ctx, query)
+		if err != nil {
+			return err
+		}
+		for _, flow := range flows {
+			j.monitor.Printf("Dry run: would purge data flow %s in state %s\n", flow.ID, flow.State)
+		}
+		j.record(func(m *RetentionMetrics) { m.Purged += int64(len(flows)) })
+		return nil
+	})
+}
+
+func (j *RetentionJanitor) find(ctx context.Context, query FlowQuery) ([]*DataFlow, error) {
+	iterator, err := j.store.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying expired data flows: %w", err)
+	}
+	defer iterator.Close()
+
+	var flows []*DataFlow
+	for iterator.Next() {
+		flows = append(flows, iterator.Get())
+	}
+	if err := iterator.Error(); err != nil {
+		return nil, fmt.Errorf("querying expired data flows: %w", err)
+	}
+	return flows, nil
+}
+
+func (j *RetentionJanitor) record(update func(m *RetentionMetrics)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	update(&j.metrics)
+}
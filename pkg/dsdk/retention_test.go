@@ -0,0 +1,245 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceIterator struct {
+	flows []*DataFlow
+	index int
+}
+
+func newSliceIterator(flows ...*DataFlow) *sliceIterator {
+	return &sliceIterator{flows: flows, index: -1}
+}
+
+func (i *sliceIterator) Next() bool {
+	i.index++
+	return i.index < len(i.flows)
+}
+
+func (i *sliceIterator) Get() *DataFlow {
+	return i.flows[i.index]
+}
+
+func (i *sliceIterator) Error() error {
+	return nil
+}
+
+func (i *sliceIterator) Close() error {
+	return nil
+}
+
+type stubLock struct {
+	held     bool
+	unlocked bool
+}
+
+func (l *stubLock) TryLock(context.Context) (bool, error) {
+	return !l.held, nil
+}
+
+func (l *stubLock) Unlock(context.Context) error {
+	l.unlocked = true
+	return nil
+}
+
+func inState(state DataFlowState) any {
+	return mock.MatchedBy(func(query FlowQuery) bool {
+		return len(query.States) == 1 && query.States[0] == state
+	})
+}
+
+func Test_RetentionJanitor_Purge(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+	before := time.Now()
+
+	store.EXPECT().Query(ctx, mock.MatchedBy(func(query FlowQuery) bool {
+		return query.States[0] == Completed && query.Limit == 100 &&
+			query.StateTimestampBefore <= before.Add(-time.Hour).UnixMilli()+1000
+	})).Return(newSliceIterator(&DataFlow{ID: "flow1"}, &DataFlow{ID: "flow2"}), nil).Once()
+	store.EXPECT().Delete(ctx, "flow1").Return(nil)
+	store.EXPECT().Delete(ctx, "flow2").Return(ErrNotFound)
+
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour})
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(ctx))
+	metrics := janitor.Metrics()
+	assert.Equal(t, int64(1), metrics.Runs)
+	assert.Equal(t, int64(2), metrics.Purged)
+	assert.Empty(t, metrics.LastError)
+}
+
+func Test_RetentionJanitor_Batches(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+
+	store.EXPECT().Query(ctx, inState(Terminated)).Return(newSliceIterator(&DataFlow{ID: "flow1"}, &DataFlow{ID: "flow2"}), nil).Once()
+	store.EXPECT().Query(ctx, inState(Terminated)).Return(newSliceIterator(&DataFlow{ID: "flow3"}), nil).Once()
+	store.EXPECT().Delete(ctx, mock.Anything).Return(nil).Times(3)
+
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{TerminatedTTL: time.Hour}, WithBatchSize(2))
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(ctx))
+	assert.Equal(t, int64(3), janitor.Metrics().Purged)
+}
+
+func Test_RetentionJanitor_DryRun(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+
+	store.EXPECT().Query(ctx, inState(Completed)).Return(newSliceIterator(&DataFlow{ID: "flow1"}), nil)
+	store.EXPECT().Query(ctx, inState(Terminated)).Return(newSliceIterator(&DataFlow{ID: "flow2"}), nil)
+
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour, TerminatedTTL: time.Hour},
+		WithDryRun(true))
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(ctx))
+	assert.Equal(t, int64(2), janitor.Metrics().Purged)
+	store.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func Test_RetentionJanitor_ArchiverFailure(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+
+	store.EXPECT().Query(ctx, inState(Completed)).Return(newSliceIterator(&DataFlow{ID: "flow1"}, &DataFlow{ID: "flow2"}), nil).Once()
+	store.EXPECT().Delete(ctx, "flow2").Return(nil)
+
+	var archived []string
+	archiver := func(_ context.Context, flow *DataFlow) error {
+		if flow.ID == "flow1" {
+			return errors.New("archive unavailable")
+		}
+		archived = append(archived, flow.ID)
+		return nil
+	}
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour}, WithArchiver(archiver))
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(ctx))
+	assert.Equal(t, []string{"flow2"}, archived)
+	metrics := janitor.Metrics()
+	assert.Equal(t, int64(1), metrics.Purged)
+	assert.Equal(t, int64(1), metrics.Archived)
+	assert.Equal(t, int64(1), metrics.Failed)
+}
+
+func Test_RetentionJanitor_SkipsFailedBatch(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+
+	// the skipped flows are returned again and the batch is extended by them
+	store.EXPECT().Query(ctx, mock.MatchedBy(func(query FlowQuery) bool { return query.Limit == 2 })).
+		Return(newSliceIterator(&DataFlow{ID: "flow1"}, &DataFlow{ID: "flow2"}), nil).Once()
+	store.EXPECT().Query(ctx, mock.MatchedBy(func(query FlowQuery) bool { return query.Limit == 4 })).
+		Return(newSliceIterator(&DataFlow{ID: "flow1"}, &DataFlow{ID: "flow2"}, &DataFlow{ID: "flow3"}), nil).Once()
+	store.EXPECT().Delete(ctx, "flow3").Return(nil)
+
+	archiver := func(_ context.Context, flow *DataFlow) error {
+		if flow.ID != "flow3" {
+			return errors.New("archive unavailable")
+		}
+		return nil
+	}
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour},
+		WithArchiver(archiver), WithBatchSize(2))
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(ctx))
+	metrics := janitor.Metrics()
+	assert.Equal(t, int64(1), metrics.Purged)
+	assert.Equal(t, int64(2), metrics.Failed)
+}
+
+func Test_NewRetentionJanitor_InvalidOptions(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+
+	_, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{}, WithRetentionInterval(0))
+	assert.ErrorContains(t, err, "interval must be positive")
+	_, err = NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{}, WithBatchSize(-1))
+	assert.ErrorContains(t, err, "batch size must be positive")
+}
+
+func Test_RetentionJanitor_RolledBackBatchNotCounted(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+
+	store.EXPECT().Query(ctx, inState(Completed)).Return(newSliceIterator(&DataFlow{ID: "flow1"}, &DataFlow{ID: "flow2"}), nil).Once()
+	store.EXPECT().Delete(ctx, "flow1").Return(nil)
+	store.EXPECT().Delete(ctx, "flow2").Return(errors.New("connection refused"))
+
+	archiver := func(context.Context, *DataFlow) error { return nil }
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour}, WithArchiver(archiver))
+	require.NoError(t, err)
+
+	require.Error(t, janitor.RunOnce(ctx))
+	metrics := janitor.Metrics()
+	assert.Equal(t, int64(0), metrics.Purged)
+	assert.Equal(t, int64(0), metrics.Archived)
+	assert.Equal(t, int64(1), metrics.Failed)
+}
+
+func Test_RetentionJanitor_StoreError(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	ctx := context.Background()
+
+	store.EXPECT().Query(ctx, inState(Completed)).Return(nil, errors.New("connection refused"))
+
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour})
+	require.NoError(t, err)
+
+	err = janitor.RunOnce(ctx)
+
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Contains(t, janitor.Metrics().LastError, "connection refused")
+}
+
+func Test_RetentionJanitor_LockHeld(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	lock := &stubLock{held: true}
+
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{CompletedTTL: time.Hour}, WithLeaderLock(lock))
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(context.Background()))
+	metrics := janitor.Metrics()
+	assert.Equal(t, int64(0), metrics.Runs)
+	assert.Equal(t, int64(1), metrics.SkippedRuns)
+	assert.False(t, lock.unlocked)
+}
+
+func Test_RetentionJanitor_ReleasesLock(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	lock := &stubLock{}
+
+	janitor, err := NewRetentionJanitor(store, &mockTrxContext{}, RetentionPolicy{}, WithLeaderLock(lock))
+	require.NoError(t, err)
+
+	require.NoError(t, janitor.RunOnce(context.Background()))
+	assert.Equal(t, int64(1), janitor.Metrics().Runs)
+	assert.True(t, lock.unlocked)
+}
@@ -0,0 +1,209 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy controls how processors that failed with ErrTransient are retried in the background. The delay before
+// attempt n is InitialDelay * Multiplier^(n-1), capped at MaxDelay. When all attempts fail, the flow is terminated.
+type RetryPolicy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+}
+
+// DefaultRetryPolicy is used unless a policy is configured with WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  5,
+	InitialDelay: time.Second,
+	MaxDelay:     30 * time.Second,
+	Multiplier:   2,
+}
+
+// Delay returns the backoff before the given attempt, starting at 1.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := float64(p.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		delay *= max(p.Multiplier, 1)
+		if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+			return p.MaxDelay
+		}
+	}
+	return time.Duration(delay)
+}
+
+// isClassified returns true if a processor error is marked as transient or fatal.
+func isClassified(err error) bool {
+	return errors.Is(err, ErrTransient) || errors.Is(err, ErrFatal)
+}
+
+// processorFailure handles a classified processor error for a flow that is about to be persisted. For transient errors
+// the flow is persisted in the pending state (Preparing or Starting) and the processor is retried once the transaction
+// commits. For fatal errors the flow is persisted as Terminated, the control plane is notified and the error is
+// returned to the caller.
+func (dsdk *DataPlaneSDK) processorFailure(ctx context.Context,
+	op *operation,
+	flow *DataFlow,
+	pending DataFlowState,
+	processor DataFlowProcessor,
+	options *ProcessorOptions,
+	persist func(context.Context, *DataFlow) error,
+	cause error) (*DataFlowResponseMessage, error) {
+
+	if errors.Is(cause, ErrFatal) {
+		if err := dsdk.terminateFailed(ctx, op, flow, cause, persist); err != nil {
+			return nil, err
+		}
+		return nil, &committedError{err: fmt.Errorf("processing data flow %s: %w", flow.ID, cause)}
+	}
+
+	if err := transitionToPending(flow, pending); err != nil {
+		return nil, err
+	}
+	flow.ErrorDetail = cause.Error()
+	if err := persist(ctx, flow); err != nil {
+		return nil, fmt.Errorf("persisting data flow %s for retry: %w", flow.ID, err)
+	}
+	flowID := flow.ID
+	op.afterCommit(func() {
+		dsdk.background(func(stopCtx context.Context, abortCtx context.Context) {
+			dsdk.retry(stopCtx, abortCtx, flowID, pending, processor, options)
+		})
+	})
+	return &DataFlowResponseMessage{State: pending}, nil
+}
+
+// retry re-invokes the processor with backoff until the flow leaves the pending state or the attempts are exhausted.
+// Retries end when stopCtx is cancelled on shutdown, leaving the flow in the pending state; attempts in progress run
+// with abortCtx.
+func (dsdk *DataPlaneSDK) retry(stopCtx context.Context,
+	abortCtx context.Context,
+	flowID string,
+	pending DataFlowState,
+	processor DataFlowProcessor,
+	options *ProcessorOptions) {
+
+	for attempt := 1; attempt <= dsdk.retryPolicy.MaxAttempts; attempt++ {
+		timer := time.NewTimer(dsdk.retryPolicy.Delay(attempt))
+		select {
+		case <-stopCtx.Done():
+			timer.Stop()
+			dsdk.Monitor.Printf("Stopped retrying data flow %s on shutdown\n", flowID)
+			return
+		case <-timer.C:
+		}
+		if !dsdk.retryAttempt(abortCtx, flowID, pending, processor, options, attempt == dsdk.retryPolicy.MaxAttempts) {
+			return
+		}
+	}
+}
+
+// retryAttempt invokes the processor once. Returns true if the processor should be retried again.
+func (dsdk *DataPlaneSDK) retryAttempt(abortCtx context.Context,
+	flowID string,
+	pending DataFlowState,
+	processor DataFlowProcessor,
+	options *ProcessorOptions,
+	final bool) bool {
+
+	again := false
+	op := &operation{}
+	err := dsdk.transact(abortCtx, op, func(ctx context.Context) error {
+		flow, err := dsdk.findFlow(ctx, flowID)
+		if err != nil {
+			return fmt.Errorf("retrying data flow %s: %w", flowID, err)
+		}
+		if flow.State != pending {
+			return nil // the flow was transitioned in the meantime, e.g. terminated by the control plane
+		}
+
+		response, err := processor(withOperation(ctx, op), flow, dsdk, options)
+		switch {
+		case err == nil:
+			if err := dsdk.pendingState(response, flow, pending); err != nil {
+				return dsdk.terminateFailed(ctx, op, flow, WrapFatalError(err), dsdk.Store.Save)
+			}
+			flow.ErrorDetail = ""
+			if err := dsdk.Store.Save(ctx, flow); err != nil {
+				return fmt.Errorf("retrying data flow %s: %w", flow.ID, err)
+			}
+			if event := completionEvent(flow.State); event != "" {
+				op.afterCommit(func() {
+					dsdk.notify(abortCtx, flow, event, response)
+				})
+			}
+			return nil
+		case errors.Is(err, ErrTransient) && !final:
+			dsdk.Monitor.Printf("Retryable failure processing data flow %s: %v\n", flow.ID, err)
+			again = true
+			return nil
+		default:
+			return dsdk.terminateFailed(ctx, op, flow, err, dsdk.Store.Save)
+		}
+	})
+	if err != nil {
+		dsdk.Monitor.Printf("Error retrying data flow %s: %v\n", flowID, err)
+		return !final
+	}
+	return again
+}
+
+// terminateFailed persists the flow as Terminated with the error as detail and notifies the control plane after commit.
+func (dsdk *DataPlaneSDK) terminateFailed(ctx context.Context, op *operation, flow *DataFlow, cause error, persist func(context.Context, *DataFlow) error) error {
+	if err := flow.TransitionToTerminated(cause.Error()); err != nil {
+		return err
+	}
+	if err := persist(ctx, flow); err != nil {
+		return fmt.Errorf("terminating data flow %s: %w", flow.ID, err)
+	}
+	dsdk.stopFlowWorkerOnCommit(op, flow)
+	op.afterCommit(func() {
+		dsdk.notifyAsync(flow, TerminatedEvent, &DataFlowResponseMessage{State: Terminated, Error: flow.ErrorDetail})
+	})
+	return nil
+}
+
+func transitionToPending(flow *DataFlow, pending DataFlowState) error {
+	if pending == Preparing {
+		return flow.TransitionToPreparing()
+	}
+	return flow.TransitionToStarting()
+}
+
+// pendingState applies the state returned by a retried processor
+func (dsdk *DataPlaneSDK) pendingState(response *DataFlowResponseMessage, flow *DataFlow, pending DataFlowState) error {
+	if response == nil {
+		return fmt.Errorf("processor returned no response for data flow %s", flow.ID)
+	}
+	if pending == Preparing {
+		return dsdk.prepareState(response, flow)
+	}
+	return dsdk.startState(response, flow)
+}
+
+func completionEvent(state DataFlowState) string {
+	switch state {
+	case Prepared:
+		return PreparedEvent
+	case Started:
+		return StartedEvent
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,192 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, Multiplier: 1}
+
+func Test_RetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{InitialDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2}
+
+	assert.Equal(t, time.Second, policy.Delay(1))
+	assert.Equal(t, 2*time.Second, policy.Delay(2))
+	assert.Equal(t, 4*time.Second, policy.Delay(3))
+	assert.Equal(t, 5*time.Second, policy.Delay(4))
+}
+
+func Test_DataPlaneSDK_Start_TransientFailureIsRetried(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	notifier := newRecordingNotifier()
+	attempts := 0
+	dsdk := DataPlaneSDK{
+		Store:       store,
+		TrxContext:  &mockTrxContext{},
+		Monitor:     defaultLogMonitor{},
+		retryPolicy: testRetryPolicy,
+		notifier:    notifier,
+		onStart: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, WrapTransientError(errors.New("nats unavailable"))
+			}
+			return &DataFlowResponseMessage{State: Started}, nil
+		},
+	}
+
+	ctx := context.Background()
+	var persisted *DataFlow
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(ctx, mock.MatchedBy(func(flow *DataFlow) bool {
+		persisted = flow
+		return flow.State == Starting && flow.ErrorDetail != ""
+	})).Return(nil).Once()
+	store.EXPECT().FindById(mock.Anything, "process123").RunAndReturn(func(context.Context, string) (*DataFlow, error) {
+		flowCopy := *persisted
+		return &flowCopy, nil
+	}).Once()
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(flow *DataFlow) bool {
+		return flow.State == Started && flow.ErrorDetail == ""
+	})).Return(nil).Once()
+
+	response, err := dsdk.Start(ctx, createStartMessage())
+
+	require.NoError(t, err)
+	assert.Equal(t, Starting, response.State)
+	event := notifier.await(t)
+	assert.Equal(t, StartedEvent, event.name)
+	assert.Equal(t, Started, event.message.State)
+	assert.Equal(t, 2, attempts)
+}
+
+func Test_DataPlaneSDK_Prepare_RetriesExhausted(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	notifier := newRecordingNotifier()
+	dsdk := DataPlaneSDK{
+		Store:       store,
+		TrxContext:  &mockTrxContext{},
+		Monitor:     defaultLogMonitor{},
+		retryPolicy: testRetryPolicy,
+		notifier:    notifier,
+		onPrepare: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return nil, WrapTransientError(errors.New("nats unavailable"))
+		},
+	}
+
+	ctx := context.Background()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(ctx, mock.MatchedBy(func(flow *DataFlow) bool {
+		return flow.State == Preparing && flow.Consumer
+	})).Return(nil).Once()
+	store.EXPECT().FindById(mock.Anything, "process123").RunAndReturn(func(context.Context, string) (*DataFlow, error) {
+		return &DataFlow{ID: "process123", Consumer: true, State: Preparing}, nil
+	}).Twice()
+	store.EXPECT().Save(mock.Anything, mock.MatchedBy(func(flow *DataFlow) bool {
+		return flow.State == Terminated
+	})).Return(nil).Once()
+
+	response, err := dsdk.Prepare(ctx, createPrepareMessage())
+
+	require.NoError(t, err)
+	assert.Equal(t, Preparing, response.State)
+	event := notifier.await(t)
+	assert.Equal(t, TerminatedEvent, event.name)
+	assert.Contains(t, event.message.Error, "nats unavailable")
+}
+
+func Test_DataPlaneSDK_Start_FatalFailureTerminates(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	notifier := newRecordingNotifier()
+	dsdk := DataPlaneSDK{
+		Store:       store,
+		TrxContext:  &mockTrxContext{},
+		Monitor:     defaultLogMonitor{},
+		retryPolicy: testRetryPolicy,
+		notifier:    notifier,
+		onStart: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return nil, WrapFatalError(errors.New("invalid source"))
+		},
+	}
+
+	ctx := context.Background()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+	store.EXPECT().Create(ctx, mock.MatchedBy(func(flow *DataFlow) bool {
+		return flow.State == Terminated && flow.ErrorDetail == "fatal failure: invalid source"
+	})).Return(nil).Once()
+
+	response, err := dsdk.Start(ctx, createStartMessage())
+
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, ErrFatal)
+	event := notifier.await(t)
+	assert.Equal(t, TerminatedEvent, event.name)
+	assert.Equal(t, Terminated, event.message.State)
+}
+
+func Test_DataPlaneSDK_Start_UnclassifiedFailureIsNotPersisted(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	dsdk := DataPlaneSDK{
+		Store:      store,
+		TrxContext: &mockTrxContext{},
+		notifier:   newRecordingNotifier(),
+		onStart: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			return nil, errors.New("failed")
+		},
+	}
+
+	ctx := context.Background()
+	store.EXPECT().FindById(ctx, "process123").Return(nil, ErrNotFound).Once()
+
+	_, err := dsdk.Start(ctx, createStartMessage())
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrFatal)
+}
+
+type notification struct {
+	name    string
+	message *DataFlowResponseMessage
+}
+
+type recordingNotifier struct {
+	events chan notification
+}
+
+func newRecordingNotifier() *recordingNotifier {
+	return &recordingNotifier{events: make(chan notification, 10)}
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, _ *DataFlow, event string, message *DataFlowResponseMessage) error {
+	r.events <- notification{name: event, message: message}
+	return nil
+}
+
+func (r *recordingNotifier) await(t *testing.T) notification {
+	t.Helper()
+	select {
+	case event := <-r.events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+		return notification{}
+	}
+}
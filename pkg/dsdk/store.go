@@ -2,6 +2,7 @@ package dsdk
 
 import (
 	"context"
+	"slices"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@latest --name=DataplaneStore --output=. --outpkg=dsdk --filename=mock_dataplane_store_test.go --structname=MockDataplaneStore --with-expecter --inpackage
@@ -11,8 +12,109 @@ type DataplaneStore interface {
 	// FindById returns a DataFlow for the given id or an error.
 	FindById(context.Context, string) (*DataFlow, error)
 	Create(context.Context, *DataFlow) error
+	// Save persists a DataFlow. Stores providing optimistic concurrency return ErrConflict if the flow has been saved
+	// since it was read, as indicated by DataFlow.Version, and increment the Version on success.
 	Save(context.Context, *DataFlow) error
 	Delete(ctx context.Context, id string) error
+	// Query returns the data flows matching the query ordered by state timestamp. The iterator must be closed.
+	Query(ctx context.Context, query FlowQuery) (Iterator[*DataFlow], error)
+}
+
+// FlowQuery selects data flows. Empty fields do not restrict the result.
+type FlowQuery struct {
+	// States restricts the result to flows in one of the states
+	States []DataFlowState
+	// StateTimestampBefore restricts the result to flows that entered their state before the epoch millis timestamp
+	StateTimestampBefore int64
+	// RuntimeID restricts the result to flows owned by the runtime
+	RuntimeID string
+	// ParticipantID restricts the result to flows of the participant
+	ParticipantID string
+	// CounterPartyID restricts the result to flows with the counterparty
+	CounterPartyID string
+	// AgreementID restricts the result to flows of the agreement
+	AgreementID string
+	// DatasetID restricts the result to flows of the dataset
+	DatasetID string
+	// Limit is the maximum number of flows returned
+	Limit int
+}
+
+// Matches returns true if the flow satisfies the query criteria, ignoring the limit.
+func (q FlowQuery) Matches(flow *DataFlow) bool {
+	if len(q.States) > 0 && !slices.Contains(q.States, flow.State) {
+		return false
+	}
+	if q.StateTimestampBefore > 0 && flow.StateTimestamp >= q.StateTimestampBefore {
+		return false
+	}
+	if q.RuntimeID != "" && flow.RuntimeID != q.RuntimeID {
+		return false
+	}
+	if q.ParticipantID != "" && flow.ParticipantID != q.ParticipantID {
+		return false
+	}
+	if q.CounterPartyID != "" && flow.CounterPartyID != q.CounterPartyID {
+		return false
+	}
+	if q.AgreementID != "" && flow.AgreementID != q.AgreementID {
+		return false
+	}
+	if q.DatasetID != "" && flow.DatasetID != q.DatasetID {
+		return false
+	}
+	return true
+}
+
+// ProgressStore is an optional extension of DataplaneStore that applies progress updates to a DataFlow without
+// loading and saving the entire entity. Returns ErrNotFound if the flow does not exist. Since updates may run
+// concurrently with operations on the flow, Save of stores implementing ProgressStore must not overwrite the transferred
+// counters and the last activity; a positive expected total is written.
+type ProgressStore interface {
+	UpdateProgress(ctx context.Context, id string, update ProgressUpdate, timestamp int64) error
+}
+
+// ProcessedMessage records a signaling message handled by the SDK. Response contains the JSON encoded response returned
+// for the message, or nil if the operation does not return one.
+type ProcessedMessage struct {
+	MessageID   string
+	ProcessID   string
+	MessageType string
+	PayloadHash string
+	Response    []byte
+	// Failure is the problem response of an operation that failed after persisting the flow, e.g. a flow terminated
+	// by a fatal processor failure. Replays of the message return it as error.
+	Failure   []byte
+	CreatedAt int64
+}
+
+// MessageLedger is an optional extension of DataplaneStore that records processed messages for MessageID based
+// idempotency. FindMessage returns ErrNotFound for unknown IDs and SaveMessage returns ErrConflict if the ID exists.
+type MessageLedger interface {
+	FindMessage(ctx context.Context, messageID string) (*ProcessedMessage, error)
+	SaveMessage(ctx context.Context, message *ProcessedMessage) error
+}
+
+// HistoryStore is an optional extension of DataplaneStore that persists the transition history of data flows. Stores
+// implementing it append the records returned by DataFlow.TakeTransitions in Create and Save, within the same
+// transaction as the flow. History returns the records in chronological order or ErrNotFound if the flow is unknown.
+type HistoryStore interface {
+	History(ctx context.Context, id string) ([]TransitionRecord, error)
+}
+
+// AdmissionLocker is an optional extension of DataplaneStore that serializes admission decisions across instances
+// sharing the store. LockAdmission blocks until no other transaction holds the lock for the key; the lock is released
+// when the current transaction ends. Without it, concurrent prepare and start messages may exceed the active flow
+// limits, as the active flows are counted before the new flows are visible to each other.
+type AdmissionLocker interface {
+	LockAdmission(ctx context.Context, key string) error
+}
+
+// FlowCounter is an optional extension of DataplaneStore that counts the data flows matching a query without loading
+// them, e.g. with a COUNT query. The limit of the query is ignored. Stores not implementing it are counted by iterating
+// Query.
+type FlowCounter interface {
+	CountFlows(ctx context.Context, query FlowQuery) (int, error)
 }
 
 // TransactionContext defines an extension point for executing operations within a transactional context.
@@ -0,0 +1,126 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// activeStates are the states of flows counted against the active flow limit of a tenant
+var activeStates = []DataFlowState{Preparing, Prepared, Starting, Started, Suspended}
+
+// Tenant configures a participant hosted by a multi-tenant data plane. Processors and handlers that are nil fall back
+// to the ones configured for the SDK.
+type Tenant struct {
+	ParticipantID string
+
+	OnPrepare   DataFlowProcessor
+	OnStart     DataFlowProcessor
+	OnTerminate DataFlowHandler
+	OnSuspend   DataFlowHandler
+	OnResume    DataFlowHandler
+	OnComplete  DataFlowHandler
+
+	// MaxActiveFlows limits the number of flows of the participant that are not completed or terminated. Zero means
+	// no limit.
+	MaxActiveFlows int
+}
+
+// WithTenant registers a participant hosted by the data plane. Once a tenant is registered, prepare and start messages
+// for participants that are not registered are rejected and flows are processed by the processors of their tenant.
+func WithTenant(tenant Tenant) DataPlaneSDKOption {
+	return func(sdk *DataPlaneSDK) {
+		if sdk.tenants == nil {
+			sdk.tenants = make(map[string]Tenant)
+		}
+		sdk.tenants[tenant.ParticipantID] = tenant
+	}
+}
+
+type participantKeyType struct{}
+
+// ContextWithParticipant scopes the SDK operations performed with the context to the flows of the participant. Flows of
+// other participants are reported as not found and excluded from queries.
+func ContextWithParticipant(ctx context.Context, participantID string) context.Context {
+	if participantID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, participantKeyType{}, participantID)
+}
+
+// ParticipantFromContext returns the participant the context is scoped to, or an empty string if it is not scoped.
+func ParticipantFromContext(ctx context.Context) string {
+	participantID, _ := ctx.Value(participantKeyType{}).(string)
+	return participantID
+}
+
+// findFlow loads a flow, reporting flows of participants other than the one the context is scoped to as not found
+func (dsdk *DataPlaneSDK) findFlow(ctx context.Context, processID string) (*DataFlow, error) {
+	flow, err := dsdk.Store.FindById(ctx, processID)
+	if err != nil {
+		return nil, err
+	}
+	if participantID := ParticipantFromContext(ctx); participantID != "" && flow.ParticipantID != participantID {
+		return nil, fmt.Errorf("%w: data flow %s", ErrNotFound, processID)
+	}
+	return flow, nil
+}
+
+// admitTenant verifies that a new flow may be created for the participant: the context must not be scoped to a
+// different participant and the participant must be a registered tenant if tenants are configured. Returns the active
+// flow limit of the tenant.
+func (dsdk *DataPlaneSDK) admitTenant(ctx context.Context, participantID string) (int, error) {
+	if scoped := ParticipantFromContext(ctx); scoped != "" && scoped != participantID {
+		return 0, fmt.Errorf("%w: participant %s does not match the authenticated participant", ErrInvalidInput, participantID)
+	}
+	if len(dsdk.tenants) == 0 {
+		return 0, nil
+	}
+	tenant, found := dsdk.tenants[participantID]
+	if !found {
+		return 0, fmt.Errorf("%w: unknown participant %s", ErrInvalidInput, participantID)
+	}
+	return tenant.MaxActiveFlows, nil
+}
+
+// installTenantProcessors dispatches the processors and handlers of the SDK to the tenant of the flow
+func (dsdk *DataPlaneSDK) installTenantProcessors() {
+	if len(dsdk.tenants) == 0 {
+		return
+	}
+	dsdk.onPrepare = dsdk.tenantProcessor(dsdk.onPrepare, func(t Tenant) DataFlowProcessor { return t.OnPrepare })
+	dsdk.onStart = dsdk.tenantProcessor(dsdk.onStart, func(t Tenant) DataFlowProcessor { return t.OnStart })
+	dsdk.onTerminate = dsdk.tenantHandler(dsdk.onTerminate, func(t Tenant) DataFlowHandler { return t.OnTerminate })
+	dsdk.onSuspend = dsdk.tenantHandler(dsdk.onSuspend, func(t Tenant) DataFlowHandler { return t.OnSuspend })
+	dsdk.onResume = dsdk.tenantHandler(dsdk.onResume, func(t Tenant) DataFlowHandler { return t.OnResume })
+	dsdk.onComplete = dsdk.tenantHandler(dsdk.onComplete, func(t Tenant) DataFlowHandler { return t.OnComplete })
+}
+
+func (dsdk *DataPlaneSDK) tenantProcessor(fallback DataFlowProcessor, selector func(Tenant) DataFlowProcessor) DataFlowProcessor {
+	return func(ctx context.Context, flow *DataFlow, sdk *DataPlaneSDK, options *ProcessorOptions) (*DataFlowResponseMessage, error) {
+		if processor := selector(dsdk.tenants[flow.ParticipantID]); processor != nil {
+			return processor(ctx, flow, sdk, options)
+		}
+		return fallback(ctx, flow, sdk, options)
+	}
+}
+
+func (dsdk *DataPlaneSDK) tenantHandler(fallback DataFlowHandler, selector func(Tenant) DataFlowHandler) DataFlowHandler {
+	return func(ctx context.Context, flow *DataFlow) error {
+		if handler := selector(dsdk.tenants[flow.ParticipantID]); handler != nil {
+			return handler(ctx, flow)
+		}
+		return fallback(ctx, flow)
+	}
+}
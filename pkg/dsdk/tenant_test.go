@@ -0,0 +1,159 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tenant_Processors(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	var processedBy string
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithPrepareProcessor(func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+			processedBy = "default"
+			return &DataFlowResponseMessage{State: Prepared}, nil
+		}),
+		WithTenant(Tenant{
+			ParticipantID: "participant-1",
+			OnPrepare: func(context.Context, *DataFlow, *DataPlaneSDK, *ProcessorOptions) (*DataFlowResponseMessage, error) {
+				processedBy = "participant-1"
+				return &DataFlowResponseMessage{State: Prepared}, nil
+			},
+		}),
+		WithTenant(Tenant{ParticipantID: "participant-2"}),
+	)
+	require.NoError(t, err)
+
+	store.EXPECT().FindById(mock.Anything, mock.Anything).Return(nil, ErrNotFound)
+	store.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
+
+	message := createPrepareMessage()
+	message.ParticipantID = "participant-1"
+	_, err = sdk.Prepare(context.Background(), message)
+	require.NoError(t, err)
+	assert.Equal(t, "participant-1", processedBy)
+
+	message = createPrepareMessage()
+	message.ParticipantID = "participant-2"
+	_, err = sdk.Prepare(context.Background(), message)
+	require.NoError(t, err)
+	assert.Equal(t, "default", processedBy, "tenants without a processor must use the default processor")
+}
+
+func Test_Tenant_UnknownParticipant(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithTenant(Tenant{ParticipantID: "participant-1"}),
+	)
+	require.NoError(t, err)
+
+	store.EXPECT().FindById(mock.Anything, mock.Anything).Return(nil, ErrNotFound)
+
+	message := createPrepareMessage()
+	message.ParticipantID = "unknown"
+	_, err = sdk.Prepare(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func Test_Tenant_ParticipantMismatch(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk, err := NewDataPlaneSDK(WithStore(store), WithTransactionContext(&mockTrxContext{}))
+	require.NoError(t, err)
+
+	store.EXPECT().FindById(mock.Anything, mock.Anything).Return(nil, ErrNotFound)
+
+	message := createPrepareMessage()
+	message.ParticipantID = "participant-2"
+	_, err = sdk.Prepare(ContextWithParticipant(context.Background(), "participant-1"), message)
+
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+func Test_Tenant_MaxActiveFlows(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk, err := NewDataPlaneSDK(
+		WithStore(store),
+		WithTransactionContext(&mockTrxContext{}),
+		WithTenant(Tenant{ParticipantID: "participant-1", MaxActiveFlows: 2}),
+	)
+	require.NoError(t, err)
+
+	store.EXPECT().FindById(mock.Anything, mock.Anything).Return(nil, ErrNotFound)
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: activeStates, ParticipantID: "participant-1"}).
+		Return(newSliceIterator(&DataFlow{ID: "flow-1"}, &DataFlow{ID: "flow-2"}), nil)
+
+	message := createPrepareMessage()
+	message.ParticipantID = "participant-1"
+	_, err = sdk.Prepare(context.Background(), message)
+
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, http.StatusTooManyRequests, NewProblemDetails(err).Status)
+}
+
+func Test_Tenant_ScopedLookup(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk := &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", ParticipantID: "participant-1", State: Started}, nil)
+
+	_, err := sdk.Status(ContextWithParticipant(context.Background(), "participant-2"), "flow123")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	flow, err := sdk.Status(ContextWithParticipant(context.Background(), "participant-1"), "flow123")
+	require.NoError(t, err)
+	assert.Equal(t, "flow123", flow.ID)
+}
+
+func Test_Tenant_ScopedList(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk := &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+
+	store.EXPECT().Query(mock.Anything, FlowQuery{States: []DataFlowState{Started}, ParticipantID: "participant-1"}).
+		Return(newSliceIterator(&DataFlow{ID: "flow-1"}), nil)
+
+	flows, err := sdk.List(ContextWithParticipant(context.Background(), "participant-1"), FlowQuery{States: []DataFlowState{Started}})
+
+	require.NoError(t, err)
+	assert.Len(t, flows, 1)
+}
+
+func Test_DataPlaneApi_ParticipantResolver(t *testing.T) {
+	store := NewMockDataplaneStore(t)
+	sdk := &DataPlaneSDK{Store: store, TrxContext: &mockTrxContext{}, Monitor: defaultLogMonitor{}}
+	api := NewDataPlaneApi(sdk, WithParticipantResolver(func(r *http.Request) string {
+		return r.Header.Get("X-Participant")
+	}))
+
+	store.EXPECT().FindById(mock.Anything, "flow123").Return(&DataFlow{ID: "flow123", ParticipantID: "participant-1", State: Started}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/dataflows/flow123/status", nil)
+	req.Header.Set("X-Participant", "participant-2")
+	rec := httptest.NewRecorder()
+	api.Status("flow123", rec, req)
+
+	problem := decodeProblem(t, rec, http.StatusNotFound)
+	assert.Equal(t, ProblemTypeNotFound, problem.Type)
+}
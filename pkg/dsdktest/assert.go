@@ -0,0 +1,81 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdktest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertState asserts that the flow of the process is in the expected state.
+func AssertState(t testing.TB, dataPlane *DataPlane, processID string, expected dsdk.DataFlowState) bool {
+	t.Helper()
+	flow, err := dataPlane.SDK.Status(context.Background(), processID)
+	if !assert.NoError(t, err, "reading data flow %s", processID) {
+		return false
+	}
+	return assert.Equal(t, expected.String(), flow.State.String(), "state of data flow %s", processID)
+}
+
+// WaitForState waits until the flow of the process reaches the expected state, e.g. after an asynchronous retry. The
+// test fails if the state is not reached within the timeout.
+func WaitForState(t testing.TB, dataPlane *DataPlane, processID string, expected dsdk.DataFlowState, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		flow, err := dataPlane.SDK.Status(context.Background(), processID)
+		if err == nil && flow.State == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				t.Fatalf("data flow %s did not reach %s: %v", processID, expected, err)
+			}
+			t.Fatalf("data flow %s did not reach %s, last state %s", processID, expected, flow.State)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// AssertTransitions asserts the states the flow of the process went through, starting with the state it was created
+// in. The data plane store must record transition history.
+func AssertTransitions(t testing.TB, dataPlane *DataPlane, processID string, expected ...dsdk.DataFlowState) bool {
+	t.Helper()
+	history, err := dataPlane.SDK.History(context.Background(), processID)
+	if !assert.NoError(t, err, "reading history of data flow %s", processID) {
+		return false
+	}
+	return assert.Equal(t, stateNames(expected), stateNames(Transitions(history)), "transitions of data flow %s", processID)
+}
+
+// Transitions returns the states a flow entered in the order of its history.
+func Transitions(history []dsdk.TransitionRecord) []dsdk.DataFlowState {
+	states := make([]dsdk.DataFlowState, 0, len(history))
+	for _, record := range history {
+		states = append(states, record.To)
+	}
+	return states
+}
+
+// stateNames converts states to names to make assertion failures readable
+func stateNames(states []dsdk.DataFlowState) []string {
+	names := make([]string, 0, len(states))
+	for _, state := range states {
+		names = append(names, state.String())
+	}
+	return names
+}
@@ -0,0 +1,259 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/signaling"
+	"github.com/stretchr/testify/require"
+)
+
+// Participant identifiers and dataspace context set on the messages created by the control plane
+const (
+	ConsumerParticipantID = "did:web:consumer.com"
+	ProviderParticipantID = "did:web:provider.com"
+	DataspaceContext      = "dscontext"
+)
+
+// Callback is a notification a data plane sent to the control plane callback address.
+type Callback struct {
+	ProcessID string
+	Event     string
+	Message   dsdk.DataFlowResponseMessage
+}
+
+// SignalingError is returned when a data plane rejects a signaling message. Problem is nil if the response did not
+// contain a problem body.
+type SignalingError = signaling.StatusError
+
+// ControlPlane is an in-process fake control plane. It sends signaling messages to data planes and records the
+// callbacks they send to its callback address, which is set on all messages it creates.
+type ControlPlane struct {
+	t   testing.TB
+	url string
+
+	mu             sync.Mutex
+	callbacks      []Callback
+	callbackStatus int
+}
+
+// NewControlPlane starts a fake control plane. It is closed when the test ends.
+func NewControlPlane(t testing.TB) *ControlPlane {
+	t.Helper()
+	c := &ControlPlane{t: t, callbackStatus: http.StatusOK}
+
+	r := chi.NewRouter()
+	r.Post("/transfers/{id}/dataflow/{event}", c.receive)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	c.url = server.URL
+	return c
+}
+
+// CallbackAddress returns the address data planes send callbacks to.
+func (c *ControlPlane) CallbackAddress() dsdk.CallbackURL {
+	callbackURL, err := url.Parse(c.url)
+	require.NoError(c.t, err)
+	return dsdk.CallbackURL(*callbackURL)
+}
+
+// RejectCallbacks makes the control plane answer subsequent callbacks with the status, e.g. to test retries of failed
+// notifications. Pass http.StatusOK to accept them again.
+func (c *ControlPlane) RejectCallbacks(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.callbackStatus = status
+}
+
+// Callbacks returns the callbacks received for a process in the order they arrived.
+func (c *ControlPlane) Callbacks(processID string) []Callback {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var callbacks []Callback
+	for _, callback := range c.callbacks {
+		if callback.ProcessID == processID {
+			callbacks = append(callbacks, callback)
+		}
+	}
+	return callbacks
+}
+
+// WaitForCallback waits until a callback for the event of the process has been received and returns it. The test
+// fails if none arrives within the timeout.
+func (c *ControlPlane) WaitForCallback(processID string, event string, timeout time.Duration) Callback {
+	c.t.Helper()
+	var received Callback
+	require.Eventually(c.t, func() bool {
+		for _, callback := range c.Callbacks(processID) {
+			if callback.Event == event {
+				received = callback
+				return true
+			}
+		}
+		return false
+	}, timeout, 10*time.Millisecond, "no %s callback received for data flow %s", event, processID)
+	return received
+}
+
+func (c *ControlPlane) receive(w http.ResponseWriter, r *http.Request) {
+	var message dsdk.DataFlowResponseMessage
+	if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.callbackStatus != http.StatusOK {
+		w.WriteHeader(c.callbackStatus)
+		return
+	}
+	c.callbacks = append(c.callbacks, Callback{
+		ProcessID: chi.URLParam(r, "id"),
+		Event:     chi.URLParam(r, "event"),
+		Message:   message,
+	})
+	w.WriteHeader(http.StatusOK)
+}
+
+// NewPrepareMessage creates a prepare message sent by the consumer control plane for a process.
+func (c *ControlPlane) NewPrepareMessage(processID string, transferType dsdk.TransferType) dsdk.DataFlowPrepareMessage {
+	return dsdk.DataFlowPrepareMessage{DataFlowBaseMessage: c.newBaseMessage(processID, transferType, true)}
+}
+
+// NewStartMessage creates a start message sent by the provider control plane for a process.
+func (c *ControlPlane) NewStartMessage(processID string, transferType dsdk.TransferType, address *dsdk.DataAddress) dsdk.DataFlowStartMessage {
+	message := dsdk.DataFlowStartMessage{DataFlowBaseMessage: c.newBaseMessage(processID, transferType, false)}
+	message.DataAddress = address
+	return message
+}
+
+func (c *ControlPlane) newBaseMessage(processID string, transferType dsdk.TransferType, consumer bool) dsdk.DataFlowBaseMessage {
+	participantID, counterPartyID := ProviderParticipantID, ConsumerParticipantID
+	if consumer {
+		participantID, counterPartyID = counterPartyID, participantID
+	}
+	return dsdk.DataFlowBaseMessage{
+		MessageID:        uuid.NewString(),
+		ParticipantID:    participantID,
+		CounterPartyID:   counterPartyID,
+		DataspaceContext: DataspaceContext,
+		ProcessID:        processID,
+		AgreementID:      "agreement-" + processID,
+		DatasetID:        "dataset-" + processID,
+		CallbackAddress:  c.CallbackAddress(),
+		TransferType:     transferType,
+	}
+}
+
+// Prepare sends a prepare message to the data plane.
+func (c *ControlPlane) Prepare(dataPlane *DataPlane, message dsdk.DataFlowPrepareMessage) (*dsdk.DataFlowResponseMessage, error) {
+	return dataPlane.Client.Prepare(c.t.Context(), message)
+}
+
+// Start sends a start message to the data plane.
+func (c *ControlPlane) Start(dataPlane *DataPlane, message dsdk.DataFlowStartMessage) (*dsdk.DataFlowResponseMessage, error) {
+	return dataPlane.Client.Start(c.t.Context(), message)
+}
+
+// Started notifies the consumer data plane that the provider has started the transfer of a process.
+func (c *ControlPlane) Started(dataPlane *DataPlane, processID string, address *dsdk.DataAddress) (*dsdk.DataFlowResponseMessage, error) {
+	message := dsdk.DataFlowStartedNotificationMessage{MessageID: uuid.NewString(), DataAddress: address}
+	return dataPlane.Client.Started(c.t.Context(), processID, message)
+}
+
+// Suspend sends a suspend message for a process to the data plane.
+func (c *ControlPlane) Suspend(dataPlane *DataPlane, processID string, reason string) error {
+	return dataPlane.Client.Suspend(c.t.Context(), processID, newTransitionMessage(reason))
+}
+
+// Resume sends a resume message for a suspended process to the data plane.
+func (c *ControlPlane) Resume(dataPlane *DataPlane, processID string) error {
+	return dataPlane.Client.Resume(c.t.Context(), processID, newTransitionMessage(""))
+}
+
+// Terminate sends a terminate message for a process to the data plane.
+func (c *ControlPlane) Terminate(dataPlane *DataPlane, processID string, reason string) error {
+	return dataPlane.Client.Terminate(c.t.Context(), processID, newTransitionMessage(reason))
+}
+
+// Complete signals the data plane that the transfer of a process has completed.
+func (c *ControlPlane) Complete(dataPlane *DataPlane, processID string) error {
+	return dataPlane.Client.Complete(c.t.Context(), processID)
+}
+
+// Status queries the status of a process from the data plane.
+func (c *ControlPlane) Status(dataPlane *DataPlane, processID string) (*dsdk.DataFlowStatusResponseMessage, error) {
+	response, err := dataPlane.Client.Status(c.t.Context(), processID)
+	if err != nil {
+		return nil, err
+	}
+	return &response.DataFlowStatusResponseMessage, nil
+}
+
+// Transfer is a transfer between a consumer and a provider data plane driven by the control plane. The flows of both
+// data planes have the same process ID.
+type Transfer struct {
+	ProcessID string
+	Consumer  *DataPlane
+	Provider  *DataPlane
+	// DataAddress is the address returned by the consumer prepare for push transfers and by the provider start for
+	// pull transfers
+	DataAddress *dsdk.DataAddress
+}
+
+// StartTransfer runs the signaling sequence of a transfer: the consumer is prepared, the provider is started and the
+// consumer is notified that the provider has started. For push transfers the provider receives the destination
+// address returned by the consumer, for pull transfers the consumer receives the address returned by the provider.
+func (c *ControlPlane) StartTransfer(consumer *DataPlane, provider *DataPlane, transferType dsdk.TransferType) (*Transfer, error) {
+	transfer := &Transfer{ProcessID: uuid.NewString(), Consumer: consumer, Provider: provider}
+
+	prepared, err := c.Prepare(consumer, c.NewPrepareMessage(transfer.ProcessID, transferType))
+	if err != nil {
+		return nil, fmt.Errorf("preparing consumer: %w", err)
+	}
+	var destination *dsdk.DataAddress
+	if transferType.FlowType == dsdk.Push {
+		destination = prepared.DataAddress
+		transfer.DataAddress = prepared.DataAddress
+	}
+
+	started, err := c.Start(provider, c.NewStartMessage(transfer.ProcessID, transferType, destination))
+	if err != nil {
+		return nil, fmt.Errorf("starting provider: %w", err)
+	}
+	var source *dsdk.DataAddress
+	if transferType.FlowType == dsdk.Pull {
+		source = started.DataAddress
+		transfer.DataAddress = started.DataAddress
+	}
+
+	if _, err := c.Started(consumer, transfer.ProcessID, source); err != nil {
+		return nil, fmt.Errorf("starting consumer: %w", err)
+	}
+	return transfer, nil
+}
+
+func newTransitionMessage(reason string) dsdk.DataFlowTransitionMessage {
+	return dsdk.DataFlowTransitionMessage{MessageID: uuid.NewString(), Reason: reason}
+}
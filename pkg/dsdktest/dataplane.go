@@ -0,0 +1,88 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+// Package dsdktest provides support for testing data planes end to end: data planes served by httptest servers, a
+// fake control plane that drives signaling sequences and records callbacks, and assertions on flow state transitions.
+package dsdktest
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/memory"
+	"github.com/metaform/dataplane-sdk-go/pkg/signaling"
+	"github.com/stretchr/testify/require"
+)
+
+// shutdownTimeout bounds the shutdown of a data plane when the test ends
+const shutdownTimeout = 5 * time.Second
+
+// AdminToken is the bearer token authorizing requests to the admin API of a DataPlane
+const AdminToken = "admin-token"
+
+// DataPlane is a data plane SDK whose signaling API is served by an httptest server. It is started with the test and
+// shut down when the test ends.
+type DataPlane struct {
+	SDK *dsdk.DataPlaneSDK
+	// Store is the in-memory store of the SDK, or nil if it was replaced with dsdk.WithStore
+	Store *memory.InMemoryStore
+	// URL is the base URL of the signaling API
+	URL string
+	// Client sends signaling messages to the data plane
+	Client *signaling.Client
+	// AdminURL is the base URL of the admin API, which requires AdminToken as bearer token
+	AdminURL string
+	// Admin sends operator requests such as listing and bulk operations to the admin API of the data plane
+	Admin *signaling.Client
+}
+
+// NewDataPlane creates an SDK backed by an in-memory store and serves its signaling API. The options are applied after
+// the defaults, so processors, stores and notifiers can be replaced.
+func NewDataPlane(t testing.TB, options ...dsdk.DataPlaneSDKOption) *DataPlane {
+	t.Helper()
+	store := memory.NewInMemoryStore()
+	defaults := []dsdk.DataPlaneSDKOption{
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
+	}
+	sdk, err := dsdk.NewDataPlaneSDK(append(defaults, options...)...)
+	require.NoError(t, err)
+	require.NoError(t, sdk.Startup(t.Context()))
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := sdk.Shutdown(ctx); err != nil && !errors.Is(err, dsdk.ErrShuttingDown) {
+			t.Errorf("shutting down data plane: %v", err)
+		}
+	})
+
+	server := httptest.NewServer(dsdk.NewDataPlaneApi(sdk).Handler())
+	t.Cleanup(server.Close)
+	authorizer := dsdk.BearerTokenAuthorizer(map[string]string{AdminToken: "operator"})
+	adminServer := httptest.NewServer(dsdk.NewAdminApi(sdk, authorizer).Handler())
+	t.Cleanup(adminServer.Close)
+	dataPlane := &DataPlane{
+		SDK:      sdk,
+		URL:      server.URL,
+		Client:   signaling.NewClient(server.URL),
+		AdminURL: adminServer.URL,
+		Admin:    signaling.NewClient(adminServer.URL, signaling.WithHeader("Authorization", "Bearer "+AdminToken)),
+	}
+	if sdk.Store == dsdk.DataplaneStore(store) {
+		dataPlane.Store = store
+	}
+	return dataPlane
+}
@@ -0,0 +1,150 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package dsdktest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pullTransfer = dsdk.TransferType{DestinationType: "HttpData", FlowType: dsdk.Pull}
+
+func TestStartTransfer_Pull(t *testing.T) {
+	controlPlane := NewControlPlane(t)
+	consumer := NewDataPlane(t)
+	provider := NewDataPlane(t, dsdk.WithStartProcessor(func(ctx context.Context, flow *dsdk.DataFlow, sdk *dsdk.DataPlaneSDK, options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
+		address, err := dsdk.NewDataAddressBuilder().Property("endpoint", "http://provider.com/"+flow.DatasetID).Build()
+		if err != nil {
+			return nil, err
+		}
+		return &dsdk.DataFlowResponseMessage{State: dsdk.Started, DataAddress: address}, nil
+	}))
+
+	transfer, err := controlPlane.StartTransfer(consumer, provider, pullTransfer)
+
+	require.NoError(t, err)
+	require.NotNil(t, transfer.DataAddress)
+	assert.Equal(t, "http://provider.com/dataset-"+transfer.ProcessID, transfer.DataAddress.Properties["endpoint"])
+	AssertTransitions(t, consumer, transfer.ProcessID, dsdk.Preparing, dsdk.Prepared, dsdk.Started)
+	AssertTransitions(t, provider, transfer.ProcessID, dsdk.Starting, dsdk.Started)
+
+	require.NoError(t, controlPlane.Suspend(provider, transfer.ProcessID, "maintenance"))
+	require.NoError(t, controlPlane.Terminate(consumer, transfer.ProcessID, "cancelled"))
+	AssertState(t, provider, transfer.ProcessID, dsdk.Suspended)
+	AssertTransitions(t, consumer, transfer.ProcessID, dsdk.Preparing, dsdk.Prepared, dsdk.Started, dsdk.Terminated)
+}
+
+func TestStartTransfer_Push(t *testing.T) {
+	controlPlane := NewControlPlane(t)
+	var received *dsdk.DataAddress
+	consumer := NewDataPlane(t, dsdk.WithPrepareProcessor(func(ctx context.Context, flow *dsdk.DataFlow, sdk *dsdk.DataPlaneSDK, options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
+		address, err := dsdk.NewDataAddressBuilder().Property("endpoint", "http://consumer.com/inbox").Build()
+		if err != nil {
+			return nil, err
+		}
+		return &dsdk.DataFlowResponseMessage{State: dsdk.Prepared, DataAddress: address}, nil
+	}))
+	provider := NewDataPlane(t, dsdk.WithStartProcessor(func(ctx context.Context, flow *dsdk.DataFlow, sdk *dsdk.DataPlaneSDK, options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
+		received = options.DataAddress
+		return &dsdk.DataFlowResponseMessage{State: dsdk.Started}, nil
+	}))
+
+	transfer, err := controlPlane.StartTransfer(consumer, provider, dsdk.TransferType{DestinationType: "HttpData", FlowType: dsdk.Push})
+
+	require.NoError(t, err)
+	require.NotNil(t, received)
+	assert.Equal(t, "http://consumer.com/inbox", received.Properties["endpoint"])
+	require.NoError(t, controlPlane.Complete(provider, transfer.ProcessID))
+	AssertTransitions(t, provider, transfer.ProcessID, dsdk.Starting, dsdk.Started, dsdk.Completed)
+	AssertState(t, consumer, transfer.ProcessID, dsdk.Started)
+}
+
+func TestControlPlane_SignalingError(t *testing.T) {
+	controlPlane := NewControlPlane(t)
+	provider := NewDataPlane(t)
+
+	err := controlPlane.Terminate(provider, "unknown", "")
+
+	var signalingErr *SignalingError
+	require.ErrorAs(t, err, &signalingErr)
+	assert.Equal(t, http.StatusNotFound, signalingErr.StatusCode)
+	require.NotNil(t, signalingErr.Problem)
+	assert.Equal(t, dsdk.ProblemTypeNotFound, signalingErr.Problem.Type)
+}
+
+func TestControlPlane_RecordsCallbacks(t *testing.T) {
+	controlPlane := NewControlPlane(t)
+	var attempts atomic.Int32
+	provider := NewDataPlane(t,
+		dsdk.WithRetryPolicy(dsdk.RetryPolicy{MaxAttempts: 3, InitialDelay: 10 * time.Millisecond}),
+		dsdk.WithStartProcessor(func(ctx context.Context, flow *dsdk.DataFlow, sdk *dsdk.DataPlaneSDK, options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
+			if attempts.Add(1) == 1 {
+				return nil, fmt.Errorf("%w: backend unavailable", dsdk.ErrTransient)
+			}
+			return &dsdk.DataFlowResponseMessage{State: dsdk.Started}, nil
+		}))
+
+	response, err := controlPlane.Start(provider, controlPlane.NewStartMessage("process-1", pullTransfer, nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Starting, response.State)
+	callback := controlPlane.WaitForCallback("process-1", dsdk.StartedEvent, time.Second)
+	assert.Equal(t, dsdk.Started, callback.Message.State)
+	WaitForState(t, provider, "process-1", dsdk.Started, time.Second)
+	assert.Len(t, controlPlane.Callbacks("process-1"), 1)
+	assert.Empty(t, controlPlane.Callbacks("process-2"))
+}
+
+func TestControlPlane_RejectCallbacks(t *testing.T) {
+	controlPlane := NewControlPlane(t)
+	controlPlane.RejectCallbacks(http.StatusServiceUnavailable)
+	flow := &dsdk.DataFlow{ID: "process-1", CallbackAddress: controlPlane.CallbackAddress()}
+
+	err := dsdk.NewHTTPCallbackNotifier(nil).Notify(context.Background(), flow, dsdk.StartedEvent, &dsdk.DataFlowResponseMessage{})
+
+	require.Error(t, err)
+	assert.Empty(t, controlPlane.Callbacks("process-1"))
+}
+
+func TestNewDataPlane_ReplacedStore(t *testing.T) {
+	dataPlane := NewDataPlane(t, dsdk.WithStore(NewDataPlane(t).Store))
+
+	assert.Nil(t, dataPlane.Store)
+}
+
+func TestNewDataPlane_AdminApi(t *testing.T) {
+	controlPlane := NewControlPlane(t)
+	consumer := NewDataPlane(t)
+	provider := NewDataPlane(t)
+	transfer, err := controlPlane.StartTransfer(consumer, provider, pullTransfer)
+	require.NoError(t, err)
+
+	flows, err := provider.Admin.List(context.Background(), dsdk.FlowQuery{States: []dsdk.DataFlowState{dsdk.Started}})
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	assert.Equal(t, transfer.ProcessID, flows[0].DataFlowID)
+
+	// operator endpoints are not served by the signaling API
+	_, err = provider.Client.List(context.Background(), dsdk.FlowQuery{})
+	var signalingErr *SignalingError
+	require.ErrorAs(t, err, &signalingErr)
+	assert.Equal(t, http.StatusNotFound, signalingErr.StatusCode)
+}
@@ -0,0 +1,159 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+)
+
+const progressInterval = time.Second
+
+// SourceResolver resolves the source DataAddress of a provider data flow, typically based on its dataset ID.
+type SourceResolver func(ctx context.Context, flow *dsdk.DataFlow) (*dsdk.DataAddress, error)
+
+// Connector integrates filesystem transfers with the data flow lifecycle. Transfers run as flow workers once the flow
+// has been started, so the SDK cancels them when the flow is suspended or terminated. The flow is completed when the
+// transfer succeeds and terminated when it fails. Destinations are confined to the destination root.
+type Connector struct {
+	resolve SourceResolver
+	root    string
+	monitor dsdk.LogMonitor
+}
+
+// ConnectorOption configures a Connector instance
+type ConnectorOption func(*Connector)
+
+// WithMonitor configures the monitor of the connector. Defaults to the monitor of the SDK.
+func WithMonitor(monitor dsdk.LogMonitor) ConnectorOption {
+	return func(c *Connector) {
+		c.monitor = monitor
+	}
+}
+
+// NewConnector creates a connector which transfers the resolved sources to destinations below destinationRoot.
+func NewConnector(resolver SourceResolver, destinationRoot string, options ...ConnectorOption) *Connector {
+	c := &Connector{resolve: resolver, root: destinationRoot}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// StartProcessor starts a push transfer from the resolved source to the file destination contained in the start message.
+func (c *Connector) StartProcessor(ctx context.Context,
+	flow *dsdk.DataFlow,
+	sdk *dsdk.DataPlaneSDK,
+	options *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
+	if options.Duplicate {
+		// the transfer is already running or has finished
+		return &dsdk.DataFlowResponseMessage{State: dsdk.Started}, nil
+	}
+
+	destination := options.DataAddress
+	if destination == nil {
+		destination = &flow.DestinationDataAddress
+	}
+	path, err := PathOf(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination for data flow %s: %w", flow.ID, err)
+	}
+	if err := within(c.root, path); err != nil {
+		return nil, fmt.Errorf("invalid destination for data flow %s: %w", flow.ID, err)
+	}
+
+	source, err := c.resolve(ctx, flow)
+	if err != nil {
+		return nil, fmt.Errorf("resolving source for data flow %s: %w", flow.ID, err)
+	}
+	src, err := NewSource(source)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", dsdk.ErrInvalidInput, err)
+	}
+	size, err := src.Size()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", dsdk.ErrInvalidInput, err)
+	}
+
+	flow.SourceDataAddress = *source
+	flow.DestinationDataAddress = *destination
+	flow.Progress.ExpectedTotal = size
+
+	sdk.RegisterFlowWorker(ctx, flow, c.transfer(sdk, source, destination))
+	return &dsdk.DataFlowResponseMessage{State: dsdk.Started}, nil
+}
+
+// transfer returns the worker copying the source to the destination. A resumed flow transfers all files again.
+func (c *Connector) transfer(sdk *dsdk.DataPlaneSDK, source *dsdk.DataAddress, destination *dsdk.DataAddress) dsdk.FlowWorker {
+	return func(ctx context.Context, flow *dsdk.DataFlow) error {
+		monitor := c.monitor
+		if monitor == nil {
+			monitor = sdk.Monitor
+		}
+		tracker := sdk.NewProgressTracker(flow.ID, progressInterval)
+		result, err := Transfer(ctx, source, destination, WithProgress(func(bytes int64) {
+			tracker.Add(bytes, 0)
+		}))
+		if closeErr := tracker.Close(context.Background()); closeErr != nil {
+			monitor.Printf("[Filesystem] Unable to record progress of data flow %s: %v\n", flow.ID, closeErr)
+		}
+		if err != nil {
+			return err
+		}
+		monitor.Printf("[Filesystem] Transferred %d files (%d bytes) for data flow %s\n", len(result.Files), result.Bytes, flow.ID)
+		return nil
+	}
+}
+
+// NewPrepareProcessor returns a consumer prepare processor which allocates a destination directory for each data flow
+// below the given base directory.
+func NewPrepareProcessor(baseDir string) dsdk.DataFlowProcessor {
+	return func(_ context.Context, flow *dsdk.DataFlow, _ *dsdk.DataPlaneSDK, _ *dsdk.ProcessorOptions) (*dsdk.DataFlowResponseMessage, error) {
+		if flow.ID == "." || flow.ID == ".." || strings.ContainsAny(flow.ID, `/\`) {
+			return nil, fmt.Errorf("%w: data flow ID %q cannot be used as a directory name", dsdk.ErrInvalidInput, flow.ID)
+		}
+		dir := filepath.Join(baseDir, flow.ID)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating destination for data flow %s: %w", flow.ID, err)
+		}
+		da, err := NewDataAddress(dir)
+		if err != nil {
+			return nil, err
+		}
+		flow.DestinationDataAddress = *da
+		return &dsdk.DataFlowResponseMessage{State: dsdk.Prepared, DataAddress: da}, nil
+	}
+}
+
+// within returns an error if path is not located below root. Symbolic links are not resolved.
+func within(root string, path string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s is outside of %s", dsdk.ErrInvalidInput, path, root)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnector_CompletesOnSuccess(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	writeFile(t, srcDir, "a.txt", "a")
+	writeFile(t, srcDir, "nested/b.txt", "bb")
+
+	sdk := newProviderSdk(t, dstDir, func(context.Context, *dsdk.DataFlow) (*dsdk.DataAddress, error) {
+		return NewDataAddress(srcDir)
+	})
+
+	message := newStartMessage(fileAddress(t, dstDir))
+	response, err := sdk.Start(context.Background(), message)
+
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Started, response.State)
+	flow := awaitState(t, sdk, message.ProcessID, dsdk.Completed)
+	assert.Equal(t, int64(3), flow.Progress.BytesTransferred)
+	assert.Equal(t, int64(3), flow.Progress.ExpectedTotal)
+	assertFile(t, filepath.Join(dstDir, "a.txt"), "a")
+	assertFile(t, filepath.Join(dstDir, "nested", "b.txt"), "bb")
+}
+
+func TestConnector_TerminatesOnFailure(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcFile := writeFile(t, srcDir, "data.txt", "hello world")
+
+	sdk := newProviderSdk(t, dstDir, func(context.Context, *dsdk.DataFlow) (*dsdk.DataAddress, error) {
+		da, err := NewDataAddress(srcFile)
+		da.Properties[ChecksumKey] = checksum("tampered")
+		return da, err
+	})
+
+	message := newStartMessage(fileAddress(t, filepath.Join(dstDir, "out.txt")))
+	_, err := sdk.Start(context.Background(), message)
+	require.NoError(t, err)
+
+	flow := awaitState(t, sdk, message.ProcessID, dsdk.Terminated)
+	assert.Contains(t, flow.ErrorDetail, ErrChecksumMismatch.Error())
+}
+
+func TestConnector_InvalidDestination(t *testing.T) {
+	sdk := newProviderSdk(t, t.TempDir(), func(context.Context, *dsdk.DataFlow) (*dsdk.DataAddress, error) {
+		return NewDataAddress(t.TempDir())
+	})
+
+	da, _ := dsdk.NewDataAddressBuilder().Property(dsdk.EndpointType, "http").Build()
+	_, err := sdk.Start(context.Background(), newStartMessage(da))
+
+	assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+}
+
+func TestConnector_DestinationOutsideRoot(t *testing.T) {
+	srcDir, root := t.TempDir(), t.TempDir()
+	sdk := newProviderSdk(t, root, func(context.Context, *dsdk.DataFlow) (*dsdk.DataAddress, error) {
+		return NewDataAddress(srcDir)
+	})
+
+	_, err := sdk.Start(context.Background(), newStartMessage(fileAddress(t, filepath.Join(root, "..", "elsewhere"))))
+
+	assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+}
+
+func TestConnector_SourceNotFound(t *testing.T) {
+	dstDir := t.TempDir()
+	sdk := newProviderSdk(t, dstDir, func(context.Context, *dsdk.DataFlow) (*dsdk.DataAddress, error) {
+		return NewDataAddress("/does/not/exist")
+	})
+
+	_, err := sdk.Start(context.Background(), newStartMessage(fileAddress(t, dstDir)))
+
+	assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+}
+
+func TestNewPrepareProcessor(t *testing.T) {
+	baseDir := t.TempDir()
+	store := memory.NewInMemoryStore()
+	sdk, err := dsdk.NewDataPlaneSDK(
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
+		dsdk.WithPrepareProcessor(NewPrepareProcessor(baseDir)),
+	)
+	require.NoError(t, err)
+
+	message := newStartMessage(nil)
+	response, err := sdk.Prepare(context.Background(), dsdk.DataFlowPrepareMessage{DataFlowBaseMessage: message.DataFlowBaseMessage})
+
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Prepared, response.State)
+	path, err := PathOf(response.DataAddress)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(baseDir, message.ProcessID), path)
+	assert.DirExists(t, path)
+}
+
+func TestNewPrepareProcessor_RejectsPathInProcessID(t *testing.T) {
+	baseDir := t.TempDir()
+	store := memory.NewInMemoryStore()
+	sdk, err := dsdk.NewDataPlaneSDK(
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
+		dsdk.WithPrepareProcessor(NewPrepareProcessor(filepath.Join(baseDir, "flows"))),
+	)
+	require.NoError(t, err)
+
+	message := newStartMessage(nil)
+	message.ProcessID = "../escaped"
+	_, err = sdk.Prepare(context.Background(), dsdk.DataFlowPrepareMessage{DataFlowBaseMessage: message.DataFlowBaseMessage})
+
+	assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+	assert.NoDirExists(t, filepath.Join(baseDir, "escaped"))
+}
+
+func newProviderSdk(t *testing.T, destinationRoot string, resolver SourceResolver) *dsdk.DataPlaneSDK {
+	t.Helper()
+	connector := NewConnector(resolver, destinationRoot)
+	store := memory.NewInMemoryStore()
+	sdk, err := dsdk.NewDataPlaneSDK(
+		dsdk.WithStore(store),
+		dsdk.WithTransactionContext(memory.NewInMemoryTrxContext(store)),
+		dsdk.WithStartProcessor(connector.StartProcessor),
+	)
+	require.NoError(t, err)
+	return sdk
+}
+
+func newStartMessage(destination *dsdk.DataAddress) dsdk.DataFlowStartMessage {
+	return dsdk.DataFlowStartMessage{
+		DataFlowBaseMessage: dsdk.DataFlowBaseMessage{
+			MessageID:        uuid.NewString(),
+			ParticipantID:    "did:web:provider.com",
+			CounterPartyID:   "did:web:consumer.com",
+			DataspaceContext: "dscontext",
+			ProcessID:        uuid.NewString(),
+			AgreementID:      uuid.NewString(),
+			DatasetID:        uuid.NewString(),
+			CallbackAddress:  dsdk.CallbackURL{Scheme: "http", Host: "test.com", Path: "/callback"},
+			TransferType:     dsdk.TransferType{DestinationType: EndpointType, FlowType: dsdk.Push},
+			DataAddress:      destination,
+		},
+	}
+}
+
+func awaitState(t *testing.T, sdk *dsdk.DataPlaneSDK, processID string, state dsdk.DataFlowState) *dsdk.DataFlow {
+	t.Helper()
+	var flow *dsdk.DataFlow
+	assert.Eventually(t, func() bool {
+		found, err := sdk.Status(context.Background(), processID)
+		if err != nil {
+			return false
+		}
+		flow = found
+		return found.State == state
+	}, 5*time.Second, 10*time.Millisecond)
+	return flow
+}
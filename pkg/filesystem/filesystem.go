@@ -0,0 +1,341 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+)
+
+const (
+	// EndpointType is the DataAddress endpoint type handled by the filesystem connectors.
+	EndpointType = "file"
+	// ChecksumKey is an optional DataAddress property holding the hex-encoded SHA-256 checksum of a single source file.
+	ChecksumKey = "checksum"
+
+	tempPattern = ".dsdk-*.tmp"
+)
+
+// ErrChecksumMismatch indicates that the content written to the sink does not match the expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// NewDataAddress creates a file DataAddress pointing to the given path.
+func NewDataAddress(path string) (*dsdk.DataAddress, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: path cannot be empty", dsdk.ErrInvalidInput)
+	}
+	return dsdk.NewDataAddressBuilder().
+		Property(dsdk.EndpointType, EndpointType).
+		Property(dsdk.EndpointKey, path).
+		Build()
+}
+
+// PathOf returns the filesystem path of a file DataAddress.
+func PathOf(address *dsdk.DataAddress) (string, error) {
+	if address == nil || address.Properties == nil {
+		return "", fmt.Errorf("%w: data address is empty", dsdk.ErrInvalidInput)
+	}
+	if t, _ := address.Properties[dsdk.EndpointType].(string); t != EndpointType {
+		return "", fmt.Errorf("%w: unsupported endpoint type %v", dsdk.ErrInvalidInput, address.Properties[dsdk.EndpointType])
+	}
+	path, _ := address.Properties[dsdk.EndpointKey].(string)
+	if path == "" {
+		return "", fmt.Errorf("%w: data address has no %s", dsdk.ErrInvalidInput, dsdk.EndpointKey)
+	}
+	return filepath.Clean(path), nil
+}
+
+// FileResult describes a single file written by a Sink.
+type FileResult struct {
+	Path     string
+	Size     int64
+	Checksum string
+}
+
+// TransferResult summarizes a completed transfer.
+type TransferResult struct {
+	Files []FileResult
+	Bytes int64
+}
+
+// Source reads a single file or a directory tree.
+type Source struct {
+	root     string
+	dir      bool
+	checksum string
+}
+
+// NewSource creates a Source for the given file DataAddress. The path must exist.
+func NewSource(address *dsdk.DataAddress) (*Source, error) {
+	path, err := PathOf(address)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening source %s: %w", path, err)
+	}
+	checksum, _ := address.Properties[ChecksumKey].(string)
+	return &Source{root: path, dir: info.IsDir(), checksum: checksum}, nil
+}
+
+// IsDir returns true if the source is a directory tree.
+func (s *Source) IsDir() bool {
+	return s.dir
+}
+
+// Files returns the slash-separated paths of all regular files in the source relative to its root. For a single file
+// source, the file name is returned.
+func (s *Source) Files() ([]string, error) {
+	if !s.dir {
+		return []string{filepath.Base(s.root)}, nil
+	}
+	var files []string
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing source %s: %w", s.root, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Size returns the total size in bytes of all files in the source.
+func (s *Source) Size() (int64, error) {
+	files, err := s.Files()
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, rel := range files {
+		path := s.root
+		if s.dir {
+			path = filepath.Join(s.root, filepath.FromSlash(rel))
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("reading size of %s: %w", rel, err)
+		}
+		total += info.Size()
+	}
+	return total, nil
+}
+
+// Open opens the file at the given relative path.
+func (s *Source) Open(rel string) (io.ReadCloser, error) {
+	if !s.dir {
+		return os.Open(s.root)
+	}
+	return os.Open(filepath.Join(s.root, filepath.FromSlash(rel)))
+}
+
+// Sink writes files atomically below a target path. Content is written to a temporary file in the target directory,
+// synced, verified against its checksum and then renamed into place, so readers never observe partially written files.
+type Sink struct {
+	root string
+}
+
+// NewSink creates a Sink for the given file DataAddress.
+func NewSink(address *dsdk.DataAddress) (*Sink, error) {
+	path, err := PathOf(address)
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{root: path}, nil
+}
+
+// Write atomically writes the content of the reader to the given target path. If expectedChecksum is not empty, the
+// written content must match it.
+func (s *Sink) Write(ctx context.Context, target string, r io.Reader, expectedChecksum string) (FileResult, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return FileResult{}, fmt.Errorf("creating directory for %s: %w", target, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), tempPattern)
+	if err != nil {
+		return FileResult{}, fmt.Errorf("creating temporary file for %s: %w", target, err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), &contextReader{ctx: ctx, r: r})
+	if err != nil {
+		return FileResult{}, fmt.Errorf("writing %s: %w", target, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return FileResult{}, fmt.Errorf("syncing %s: %w", target, err)
+	}
+	checksum := hex.EncodeToString(hash.Sum(nil))
+
+	// verify what is on disk before making it visible
+	written, err := fileChecksum(tmp.Name())
+	if err != nil {
+		return FileResult{}, err
+	}
+	if written != checksum || (expectedChecksum != "" && expectedChecksum != checksum) {
+		return FileResult{}, fmt.Errorf("%w: %s", ErrChecksumMismatch, target)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return FileResult{}, fmt.Errorf("closing %s: %w", target, err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return FileResult{}, fmt.Errorf("renaming %s: %w", target, err)
+	}
+	committed = true
+	return FileResult{Path: target, Size: size, Checksum: checksum}, nil
+}
+
+// ProgressFunc is invoked with the number of bytes read from the source during a transfer.
+type ProgressFunc func(bytes int64)
+
+// TransferOption configures a transfer
+type TransferOption func(*transferOptions)
+
+type transferOptions struct {
+	progress ProgressFunc
+}
+
+// WithProgress registers a function that is notified as data is transferred.
+func WithProgress(progress ProgressFunc) TransferOption {
+	return func(o *transferOptions) {
+		o.progress = progress
+	}
+}
+
+// Transfer copies the file or directory tree referenced by the source address to the destination address. A single
+// file is written to the destination path, or into it if the destination is an existing directory. A directory tree is
+// recreated below the destination path.
+func Transfer(ctx context.Context, source *dsdk.DataAddress, destination *dsdk.DataAddress, options ...TransferOption) (*TransferResult, error) {
+	opts := &transferOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+	src, err := NewSource(source)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := NewSink(destination)
+	if err != nil {
+		return nil, err
+	}
+	files, err := src.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TransferResult{}
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		fr, err := transferFile(ctx, src, sink, rel, opts.progress)
+		if err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, fr)
+		result.Bytes += fr.Size
+	}
+	return result, nil
+}
+
+func transferFile(ctx context.Context, src *Source, sink *Sink, rel string, progress ProgressFunc) (FileResult, error) {
+	var target string
+	var expected string
+	if src.dir {
+		target = filepath.Join(sink.root, filepath.FromSlash(rel))
+	} else {
+		target = sink.root
+		if info, err := os.Stat(sink.root); err == nil && info.IsDir() {
+			target = filepath.Join(sink.root, rel)
+		}
+		expected = src.checksum
+	}
+
+	r, err := src.Open(rel)
+	if err != nil {
+		return FileResult{}, fmt.Errorf("opening %s: %w", rel, err)
+	}
+	defer r.Close()
+	if progress != nil {
+		return sink.Write(ctx, target, &progressReader{r: r, progress: progress}, expected)
+	}
+	return sink.Write(ctx, target, r, expected)
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("verifying %s: %w", path, err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("verifying %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// contextReader aborts reads once the context is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// progressReader reports the number of bytes read.
+type progressReader struct {
+	r        io.Reader
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.progress(int64(n))
+	}
+	return n, err
+}
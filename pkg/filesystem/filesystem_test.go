@@ -0,0 +1,177 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathOf(t *testing.T) {
+	t.Run("file address", func(t *testing.T) {
+		da, err := NewDataAddress("/tmp/data/../file.txt")
+		require.NoError(t, err)
+
+		path, err := PathOf(da)
+		assert.NoError(t, err)
+		assert.Equal(t, "/tmp/file.txt", path)
+	})
+
+	t.Run("wrong endpoint type", func(t *testing.T) {
+		da, _ := dsdk.NewDataAddressBuilder().Property(dsdk.EndpointType, "http").Property(dsdk.EndpointKey, "/tmp").Build()
+		_, err := PathOf(da)
+		assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+	})
+
+	t.Run("missing endpoint", func(t *testing.T) {
+		da, _ := dsdk.NewDataAddressBuilder().Property(dsdk.EndpointType, EndpointType).Build()
+		_, err := PathOf(da)
+		assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+	})
+
+	t.Run("nil address", func(t *testing.T) {
+		_, err := PathOf(nil)
+		assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+	})
+}
+
+func TestTransfer_SingleFile(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcFile := writeFile(t, srcDir, "data.txt", "hello world")
+
+	source := fileAddress(t, srcFile)
+	destination := fileAddress(t, filepath.Join(dstDir, "out.txt"))
+
+	result, err := Transfer(context.Background(), source, destination)
+
+	require.NoError(t, err)
+	require.Len(t, result.Files, 1)
+	assert.Equal(t, int64(11), result.Bytes)
+	assert.Equal(t, checksum("hello world"), result.Files[0].Checksum)
+	assertFile(t, filepath.Join(dstDir, "out.txt"), "hello world")
+	assertNoTempFiles(t, dstDir)
+}
+
+func TestTransfer_SingleFileIntoDirectory(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcFile := writeFile(t, srcDir, "data.txt", "hello world")
+
+	_, err := Transfer(context.Background(), fileAddress(t, srcFile), fileAddress(t, dstDir))
+
+	require.NoError(t, err)
+	assertFile(t, filepath.Join(dstDir, "data.txt"), "hello world")
+}
+
+func TestTransfer_DirectoryTree(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	writeFile(t, srcDir, "a.txt", "a")
+	writeFile(t, srcDir, "nested/b.txt", "bb")
+	writeFile(t, srcDir, "nested/deeper/c.txt", "ccc")
+
+	target := filepath.Join(dstDir, "target")
+	result, err := Transfer(context.Background(), fileAddress(t, srcDir), fileAddress(t, target))
+
+	require.NoError(t, err)
+	assert.Len(t, result.Files, 3)
+	assert.Equal(t, int64(6), result.Bytes)
+	assertFile(t, filepath.Join(target, "a.txt"), "a")
+	assertFile(t, filepath.Join(target, "nested", "b.txt"), "bb")
+	assertFile(t, filepath.Join(target, "nested", "deeper", "c.txt"), "ccc")
+}
+
+func TestTransfer_ChecksumVerified(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcFile := writeFile(t, srcDir, "data.txt", "hello world")
+
+	source := fileAddress(t, srcFile)
+	source.Properties[ChecksumKey] = checksum("hello world")
+
+	_, err := Transfer(context.Background(), source, fileAddress(t, filepath.Join(dstDir, "out.txt")))
+
+	assert.NoError(t, err)
+}
+
+func TestTransfer_ChecksumMismatch(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcFile := writeFile(t, srcDir, "data.txt", "hello world")
+
+	source := fileAddress(t, srcFile)
+	source.Properties[ChecksumKey] = checksum("something else")
+
+	_, err := Transfer(context.Background(), source, fileAddress(t, filepath.Join(dstDir, "out.txt")))
+
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	_, statErr := os.Stat(filepath.Join(dstDir, "out.txt"))
+	assert.True(t, os.IsNotExist(statErr))
+	assertNoTempFiles(t, dstDir)
+}
+
+func TestTransfer_SourceNotFound(t *testing.T) {
+	_, err := Transfer(context.Background(), fileAddress(t, "/does/not/exist"), fileAddress(t, t.TempDir()))
+	assert.Error(t, err)
+}
+
+func TestTransfer_Cancelled(t *testing.T) {
+	srcDir, dstDir := t.TempDir(), t.TempDir()
+	srcFile := writeFile(t, srcDir, "data.txt", "hello world")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Transfer(ctx, fileAddress(t, srcFile), fileAddress(t, filepath.Join(dstDir, "out.txt")))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assertNoTempFiles(t, dstDir)
+}
+
+func fileAddress(t *testing.T, path string) *dsdk.DataAddress {
+	t.Helper()
+	da, err := NewDataAddress(path)
+	require.NoError(t, err)
+	return da
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func assertFile(t *testing.T, path string, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, tempPattern))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -13,7 +13,11 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
@@ -21,19 +25,33 @@ import (
 
 // InMemoryStore is a thread-safe in-memory implementation of DataplaneStore
 type InMemoryStore struct {
-	mu    sync.RWMutex
-	flows map[string]*dsdk.DataFlow
+	mu       sync.RWMutex
+	flows    map[string]*dsdk.DataFlow
+	messages map[string]*dsdk.ProcessedMessage
+	history  map[string][]dsdk.TransitionRecord
+
+	// sequence is incremented on every write; revisions holds the sequence of the last write of each flow and is used
+	// to detect conflicting transactions
+	sequence  uint64
+	revisions map[string]uint64
 }
 
 // NewInMemoryStore creates a new thread-safe in-memory store
 func NewInMemoryStore() *InMemoryStore {
 	return &InMemoryStore{
-		flows: make(map[string]*dsdk.DataFlow),
+		flows:     make(map[string]*dsdk.DataFlow),
+		messages:  make(map[string]*dsdk.ProcessedMessage),
+		history:   make(map[string][]dsdk.TransitionRecord),
+		revisions: make(map[string]uint64),
 	}
 }
 
 // FindById returns a DataFlow for the given id or an error
 func (s *InMemoryStore) FindById(ctx context.Context, id string) (*dsdk.DataFlow, error) {
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.findById(id)
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -55,6 +73,9 @@ func (s *InMemoryStore) Create(ctx context.Context, flow *dsdk.DataFlow) error {
 	if flow.ID == "" {
 		return dsdk.ErrInvalidInput
 	}
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.create(flow)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -63,13 +84,12 @@ func (s *InMemoryStore) Create(ctx context.Context, flow *dsdk.DataFlow) error {
 		return dsdk.ErrConflict
 	}
 
-	// Store a copy to prevent external modifications
-	flowCopy := *flow
-	s.flows[flow.ID] = &flowCopy
+	s.store(flow)
 	return nil
 }
 
-// Save updates an existing DataFlow entry
+// Save updates an existing DataFlow entry if its Version matches the stored one and increments the Version. Returns
+// ErrConflict if the flow has been saved since it was read.
 func (s *InMemoryStore) Save(ctx context.Context, flow *dsdk.DataFlow) error {
 	if flow == nil {
 		return dsdk.ErrInvalidInput
@@ -77,17 +97,25 @@ func (s *InMemoryStore) Save(ctx context.Context, flow *dsdk.DataFlow) error {
 	if flow.ID == "" {
 		return dsdk.ErrInvalidInput
 	}
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.save(flow)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.flows[flow.ID]; !exists {
+	stored, exists := s.flows[flow.ID]
+	if !exists {
 		return dsdk.ErrNotFound
 	}
+	if stored.Version != flow.Version {
+		return conflict(flow.ID)
+	}
 
-	// Store a copy to prevent external modifications
-	flowCopy := *flow
-	s.flows[flow.ID] = &flowCopy
+	flow.Version++
+	progress := savedProgress(stored.Progress, flow.Progress)
+	s.store(flow)
+	s.flows[flow.ID].Progress = progress
 	return nil
 }
 
@@ -96,6 +124,9 @@ func (s *InMemoryStore) Delete(ctx context.Context, id string) error {
 	if id == "" {
 		return dsdk.ErrInvalidInput
 	}
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.delete(id)
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -103,11 +134,166 @@ func (s *InMemoryStore) Delete(ctx context.Context, id string) error {
 	if _, exists := s.flows[id]; !exists {
 		return dsdk.ErrNotFound
 	}
+	s.remove(id)
+	return nil
+}
+
+// conflict reports a save of a flow that has been modified since it was read
+func conflict(id string) error {
+	return fmt.Errorf("%w: data flow %s was modified concurrently", dsdk.ErrConflict, id)
+}
 
+// remove deletes a flow with its history and processed messages. Must be called with the lock held.
+func (s *InMemoryStore) remove(id string) {
 	delete(s.flows, id)
+	delete(s.history, id)
+	// a flow created later under the same ID gets a new revision, so the entry can be dropped
+	delete(s.revisions, id)
+	for messageID, message := range s.messages {
+		if message.ProcessID == id {
+			delete(s.messages, messageID)
+		}
+	}
+}
+
+// Query returns copies of the DataFlow entries matching the query ordered by state timestamp
+func (s *InMemoryStore) Query(ctx context.Context, query dsdk.FlowQuery) (dsdk.Iterator[*dsdk.DataFlow], error) {
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.query(query), nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var flows []*dsdk.DataFlow
+	for _, flow := range s.flows {
+		if query.Matches(flow) {
+			flowCopy := *flow
+			flows = append(flows, &flowCopy)
+		}
+	}
+	return newQueryIterator(flows, query), nil
+}
+
+// newQueryIterator sorts the matching flows by state timestamp and ID and applies the limit of the query
+func newQueryIterator(flows []*dsdk.DataFlow, query dsdk.FlowQuery) *memoryIterator[*dsdk.DataFlow] {
+	slices.SortFunc(flows, func(a, b *dsdk.DataFlow) int {
+		if c := cmp.Compare(a.StateTimestamp, b.StateTimestamp); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	if query.Limit > 0 && len(flows) > query.Limit {
+		flows = flows[:query.Limit]
+	}
+	return &memoryIterator[*dsdk.DataFlow]{items: flows, index: -1}
+}
+
+// History returns the transition history of a DataFlow
+func (s *InMemoryStore) History(ctx context.Context, id string) ([]dsdk.TransitionRecord, error) {
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.historyOf(id)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.flows[id]; !exists {
+		return nil, dsdk.ErrNotFound
+	}
+	return slices.Clone(s.history[id]), nil
+}
+
+// store saves a copy of the flow and appends its pending transitions to the history. Must be called with the lock held.
+func (s *InMemoryStore) store(flow *dsdk.DataFlow) {
+	s.history[flow.ID] = append(s.history[flow.ID], flow.TakeTransitions()...)
+
+	// Store a copy to prevent external modifications
+	flowCopy := *flow
+	flowCopy.SetTransitionSource("", "")
+	s.flows[flow.ID] = &flowCopy
+	s.touch(flow.ID)
+}
+
+// savedProgress returns the progress persisted when a flow is saved. The counters are only changed by UpdateProgress,
+// which may run concurrently with operations on the flow; a positive expected total replaces the stored one.
+func savedProgress(stored dsdk.TransferProgress, saved dsdk.TransferProgress) dsdk.TransferProgress {
+	if saved.ExpectedTotal > 0 {
+		stored.ExpectedTotal = saved.ExpectedTotal
+	}
+	return stored
+}
+
+// touch records a write of the flow. Must be called with the lock held.
+func (s *InMemoryStore) touch(id string) {
+	s.sequence++
+	s.revisions[id] = s.sequence
+}
+
+// UpdateProgress applies a progress update to an existing DataFlow entry
+func (s *InMemoryStore) UpdateProgress(ctx context.Context, id string, update dsdk.ProgressUpdate, timestamp int64) error {
+	if id == "" {
+		return dsdk.ErrInvalidInput
+	}
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.updateProgress(id, update, timestamp)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flow, exists := s.flows[id]
+	if !exists {
+		return dsdk.ErrNotFound
+	}
+
+	// progress updates do not change the revision, so they do not conflict with transactions
+	flow.Progress.Apply(update, timestamp)
 	return nil
 }
 
+// FindMessage returns the processed message for the given message id or an error
+func (s *InMemoryStore) FindMessage(ctx context.Context, messageID string) (*dsdk.ProcessedMessage, error) {
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.findMessage(messageID)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	message, exists := s.messages[messageID]
+	if !exists {
+		return nil, dsdk.ErrNotFound
+	}
+	return copyMessage(message), nil
+}
+
+// SaveMessage records a processed message
+func (s *InMemoryStore) SaveMessage(ctx context.Context, message *dsdk.ProcessedMessage) error {
+	if message == nil || message.MessageID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	if tx := s.transaction(ctx); tx != nil {
+		return tx.saveMessage(message)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.messages[message.MessageID]; exists {
+		return dsdk.ErrConflict
+	}
+	s.messages[message.MessageID] = copyMessage(message)
+	return nil
+}
+
+func copyMessage(message *dsdk.ProcessedMessage) *dsdk.ProcessedMessage {
+	messageCopy := *message
+	messageCopy.Response = slices.Clone(message.Response)
+	messageCopy.Failure = slices.Clone(message.Failure)
+	return &messageCopy
+}
+
 // memoryIterator is a simple iterator implementation for slice data
 type memoryIterator[T any] struct {
 	items []T
@@ -141,9 +327,21 @@ func (it *memoryIterator[T]) Close() error {
 	return nil
 }
 
-type InMemoryTrxContext struct {
+// LeaderLock is an in-process dsdk.LeaderLock. It only coordinates tasks within a single process.
+type LeaderLock struct {
+	mu sync.Mutex
 }
 
-func (c InMemoryTrxContext) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
-	return fn(context.TODO())
+// NewLeaderLock creates a new in-process leader lock
+func NewLeaderLock() *LeaderLock {
+	return &LeaderLock{}
+}
+
+func (l *LeaderLock) TryLock(ctx context.Context) (bool, error) {
+	return l.mu.TryLock(), nil
+}
+
+func (l *LeaderLock) Unlock(ctx context.Context) error {
+	l.mu.Unlock()
+	return nil
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/storetest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +34,13 @@ func TestNewInMemoryStore(t *testing.T) {
 	assert.Equal(t, 0, len(store.flows))
 }
 
+func TestInMemoryStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) (dsdk.DataplaneStore, dsdk.TransactionContext) {
+		store := NewInMemoryStore()
+		return store, NewInMemoryTrxContext(store)
+	})
+}
+
 func TestInMemoryStore_FindById(t *testing.T) {
 	store := NewInMemoryStore()
 	ctx := context.Background()
@@ -230,6 +238,16 @@ func TestInMemoryStore_Delete(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("delete removes processed messages", func(t *testing.T) {
+		require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "test-flow-2"}))
+		require.NoError(t, store.SaveMessage(ctx, &dsdk.ProcessedMessage{MessageID: "message-1", ProcessID: "test-flow-2"}))
+
+		require.NoError(t, store.Delete(ctx, "test-flow-2"))
+
+		_, err := store.FindMessage(ctx, "message-1")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+
 	t.Run("delete non-existing flow", func(t *testing.T) {
 		err := store.Delete(ctx, "non-existing")
 
@@ -245,6 +263,187 @@ func TestInMemoryStore_Delete(t *testing.T) {
 	})
 }
 
+func TestInMemoryStore_UpdateProgress(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	t.Run("update existing flow", func(t *testing.T) {
+		err := store.Create(ctx, &dsdk.DataFlow{ID: "test-flow-1"})
+		require.NoError(t, err)
+
+		err = store.UpdateProgress(ctx, "test-flow-1", dsdk.ProgressUpdate{Bytes: 100, Messages: 1, ExpectedTotal: 1000}, 42)
+		require.NoError(t, err)
+		err = store.UpdateProgress(ctx, "test-flow-1", dsdk.ProgressUpdate{Bytes: 50, Messages: 2}, 43)
+		require.NoError(t, err)
+
+		found, err := store.FindById(ctx, "test-flow-1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(150), found.Progress.BytesTransferred)
+		assert.Equal(t, int64(3), found.Progress.MessagesTransferred)
+		assert.Equal(t, int64(1000), found.Progress.ExpectedTotal)
+		assert.Equal(t, int64(43), found.Progress.LastActivity)
+	})
+
+	t.Run("update non-existing flow", func(t *testing.T) {
+		err := store.UpdateProgress(ctx, "non-existing", dsdk.ProgressUpdate{Bytes: 1}, 1)
+
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+}
+
+func TestInMemoryStore_History(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	t.Run("append transitions on create and save", func(t *testing.T) {
+		flow := &dsdk.DataFlow{ID: "test-flow-1", State: dsdk.Starting}
+		flow.SetTransitionSource("message-1", "did:web:provider.com")
+		require.NoError(t, flow.TransitionToStarted())
+		require.NoError(t, store.Create(ctx, flow))
+
+		found, err := store.FindById(ctx, "test-flow-1")
+		require.NoError(t, err)
+		require.NoError(t, found.TransitionToSuspended("maintenance"))
+		require.NoError(t, store.Save(ctx, found))
+
+		history, err := store.History(ctx, "test-flow-1")
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, dsdk.Starting, history[0].From)
+		assert.Equal(t, dsdk.Started, history[0].To)
+		assert.Equal(t, "message-1", history[0].MessageID)
+		assert.Equal(t, "did:web:provider.com", history[0].Caller)
+		assert.Equal(t, dsdk.Suspended, history[1].To)
+		assert.Equal(t, "maintenance", history[1].Reason)
+		assert.Empty(t, history[1].MessageID, "transition source must not leak into stored flows")
+	})
+
+	t.Run("history of non-existing flow", func(t *testing.T) {
+		_, err := store.History(ctx, "non-existing")
+
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+
+	t.Run("delete removes history", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "test-flow-1"))
+
+		_, err := store.History(ctx, "test-flow-1")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+}
+
+func TestInMemoryStore_MessageLedger(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+
+	t.Run("save and find message", func(t *testing.T) {
+		message := &dsdk.ProcessedMessage{
+			MessageID:   "message-1",
+			ProcessID:   "test-flow-1",
+			MessageType: dsdk.StartMessageType,
+			PayloadHash: "hash",
+			Response:    []byte(`{"state":2}`),
+			CreatedAt:   42,
+		}
+		require.NoError(t, store.SaveMessage(ctx, message))
+		message.Response[0] = 'x'
+
+		found, err := store.FindMessage(ctx, "message-1")
+		require.NoError(t, err)
+		assert.Equal(t, "test-flow-1", found.ProcessID)
+		assert.Equal(t, dsdk.StartMessageType, found.MessageType)
+		assert.Equal(t, "hash", found.PayloadHash)
+		assert.Equal(t, `{"state":2}`, string(found.Response))
+		assert.Equal(t, int64(42), found.CreatedAt)
+	})
+
+	t.Run("save duplicate message", func(t *testing.T) {
+		err := store.SaveMessage(ctx, &dsdk.ProcessedMessage{MessageID: "message-1"})
+
+		assert.ErrorIs(t, err, dsdk.ErrConflict)
+	})
+
+	t.Run("save message without id", func(t *testing.T) {
+		err := store.SaveMessage(ctx, &dsdk.ProcessedMessage{})
+
+		assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+	})
+
+	t.Run("find non-existing message", func(t *testing.T) {
+		_, err := store.FindMessage(ctx, "non-existing")
+
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+}
+
+func TestInMemoryStore_Query(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+	flows := []*dsdk.DataFlow{
+		{ID: "completed-new", State: dsdk.Completed, StateTimestamp: 300},
+		{ID: "terminated-old", State: dsdk.Terminated, StateTimestamp: 100},
+		{ID: "completed-old", State: dsdk.Completed, StateTimestamp: 200},
+		{ID: "started", State: dsdk.Started, StateTimestamp: 50},
+	}
+	for _, flow := range flows {
+		store.flows[flow.ID] = flow
+	}
+
+	collect := func(query dsdk.FlowQuery) []string {
+		iterator, err := store.Query(ctx, query)
+		require.NoError(t, err)
+		defer iterator.Close()
+		var ids []string
+		for iterator.Next() {
+			ids = append(ids, iterator.Get().ID)
+		}
+		require.NoError(t, iterator.Error())
+		return ids
+	}
+
+	t.Run("filter by state and timestamp", func(t *testing.T) {
+		ids := collect(dsdk.FlowQuery{
+			States:               []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated},
+			StateTimestampBefore: 300,
+		})
+
+		assert.Equal(t, []string{"terminated-old", "completed-old"}, ids)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		ids := collect(dsdk.FlowQuery{Limit: 2})
+
+		assert.Equal(t, []string{"started", "terminated-old"}, ids)
+	})
+
+	t.Run("returns copies", func(t *testing.T) {
+		iterator, err := store.Query(ctx, dsdk.FlowQuery{States: []dsdk.DataFlowState{dsdk.Started}})
+		require.NoError(t, err)
+		require.True(t, iterator.Next())
+		iterator.Get().State = dsdk.Terminated
+
+		assert.Equal(t, dsdk.Started, store.flows["started"].State)
+	})
+}
+
+func TestLeaderLock(t *testing.T) {
+	lock := NewLeaderLock()
+	ctx := context.Background()
+
+	acquired, err := lock.TryLock(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = lock.TryLock(ctx)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	require.NoError(t, lock.Unlock(ctx))
+	acquired, err = lock.TryLock(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
 func TestMemoryIterator(t *testing.T) {
 	t.Run("iterate through items", func(t *testing.T) {
 		items := []string{"item1", "item2", "item3"}
@@ -0,0 +1,389 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+)
+
+type transactionKeyType struct{}
+
+// ErrUnboundTrxContext is returned by the zero value of InMemoryTrxContext, which is not bound to a store.
+var ErrUnboundTrxContext = errors.New("in-memory transaction context is not bound to a store, create it with NewInMemoryTrxContext")
+
+// InMemoryTrxContext executes operations in transactions of an InMemoryStore. Writes are buffered and applied
+// atomically when the operation succeeds, and discarded if it returns an error or panics. Transactions do not see
+// uncommitted writes of others. A transaction that wrote a flow, a message or a deletion fails to commit with
+// dsdk.ErrConflict if a flow it read or wrote has been modified by another transaction since; read-only transactions
+// always commit. Progress updates are applied to the committed counters and do not cause conflicts.
+//
+// The zero value is not bound to a store and fails with ErrUnboundTrxContext; use NewInMemoryTrxContext.
+type InMemoryTrxContext struct {
+	store *InMemoryStore
+}
+
+// NewInMemoryTrxContext creates a transaction context for the store
+func NewInMemoryTrxContext(store *InMemoryStore) InMemoryTrxContext {
+	return InMemoryTrxContext{store: store}
+}
+
+// Execute runs fn in a new transaction. If ctx already carries an active transaction of the store, fn joins it.
+func (c InMemoryTrxContext) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.store == nil {
+		return ErrUnboundTrxContext
+	}
+	if c.store.transaction(ctx) != nil {
+		return fn(ctx)
+	}
+
+	tx := newTransaction(c.store)
+	defer func() {
+		if p := recover(); p != nil {
+			tx.discard()
+			panic(p) // re-throw panic
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, transactionKeyType{}, tx)); err != nil {
+		tx.discard()
+		return err
+	}
+	return tx.commit()
+}
+
+// transaction returns the active transaction of the store bound to the context, or nil.
+func (s *InMemoryStore) transaction(ctx context.Context) *transaction {
+	tx, ok := ctx.Value(transactionKeyType{}).(*transaction)
+	if !ok || tx.store != s || !tx.active() {
+		return nil
+	}
+	return tx
+}
+
+// transaction buffers the writes of an operation in an overlay over the committed state of the store.
+type transaction struct {
+	store *InMemoryStore
+
+	mu   sync.Mutex
+	done bool
+	// reads holds the revision of every committed flow the transaction observed, zero if it did not exist
+	reads map[string]uint64
+	// flows holds the written flows, nil for flows deleted by the transaction
+	flows map[string]*dsdk.DataFlow
+	// deleted holds the flows deleted by the transaction whose committed history and messages are removed on commit
+	deleted  map[string]bool
+	history  map[string][]dsdk.TransitionRecord
+	messages map[string]*dsdk.ProcessedMessage
+	// progress holds the progress updates applied to the committed counters of the flows on commit
+	progress map[string][]progressUpdate
+}
+
+// progressUpdate is a progress update buffered by a transaction
+type progressUpdate struct {
+	update    dsdk.ProgressUpdate
+	timestamp int64
+}
+
+func newTransaction(store *InMemoryStore) *transaction {
+	return &transaction{
+		store:    store,
+		reads:    make(map[string]uint64),
+		flows:    make(map[string]*dsdk.DataFlow),
+		deleted:  make(map[string]bool),
+		history:  make(map[string][]dsdk.TransitionRecord),
+		messages: make(map[string]*dsdk.ProcessedMessage),
+		progress: make(map[string][]progressUpdate),
+	}
+}
+
+func (t *transaction) active() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.done
+}
+
+// lookup returns a copy of the flow as seen by the transaction, including its buffered progress updates. Must be called
+// with t.mu held.
+func (t *transaction) lookup(id string) (*dsdk.DataFlow, bool) {
+	flow, exists := t.base(id, true)
+	if exists {
+		t.applyProgress(flow)
+	}
+	return flow, exists
+}
+
+// base returns a copy of the flow written by the transaction or committed to the store, without buffered progress
+// updates. The committed revision is only recorded if observe is set. Must be called with t.mu held.
+func (t *transaction) base(id string, observe bool) (*dsdk.DataFlow, bool) {
+	if flow, written := t.flows[id]; written {
+		if flow == nil {
+			return nil, false
+		}
+		flowCopy := *flow
+		return &flowCopy, true
+	}
+
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	if observe {
+		t.observe(id)
+	}
+	flow, exists := t.store.flows[id]
+	if !exists {
+		return nil, false
+	}
+	flowCopy := *flow
+	return &flowCopy, true
+}
+
+// applyProgress applies the buffered progress updates to a copy of a flow. Must be called with t.mu held.
+func (t *transaction) applyProgress(flow *dsdk.DataFlow) {
+	for _, pending := range t.progress[flow.ID] {
+		flow.Progress.Apply(pending.update, pending.timestamp)
+	}
+}
+
+// observe records the committed revision of a flow on first access. Must be called with t.mu and the store lock held.
+func (t *transaction) observe(id string) {
+	if _, seen := t.reads[id]; !seen {
+		t.reads[id] = t.store.revisions[id]
+	}
+}
+
+// put buffers a copy of the flow and its pending transitions. Must be called with t.mu held.
+func (t *transaction) put(flow *dsdk.DataFlow) {
+	t.history[flow.ID] = append(t.history[flow.ID], flow.TakeTransitions()...)
+	flowCopy := *flow
+	flowCopy.SetTransitionSource("", "")
+	t.flows[flow.ID] = &flowCopy
+}
+
+func (t *transaction) findById(id string) (*dsdk.DataFlow, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	flow, exists := t.lookup(id)
+	if !exists {
+		return nil, dsdk.ErrNotFound
+	}
+	return flow, nil
+}
+
+func (t *transaction) create(flow *dsdk.DataFlow) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.lookup(flow.ID); exists {
+		return dsdk.ErrConflict
+	}
+	t.put(flow)
+	return nil
+}
+
+func (t *transaction) save(flow *dsdk.DataFlow) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stored, exists := t.lookup(flow.ID)
+	if !exists {
+		return dsdk.ErrNotFound
+	}
+	if stored.Version != flow.Version {
+		return conflict(flow.ID)
+	}
+	flow.Version++
+	t.put(flow)
+	// the counters are only changed by progress updates, which are buffered separately
+	base, _ := t.base(flow.ID, false)
+	t.flows[flow.ID].Progress = savedProgress(base.Progress, flow.Progress)
+	return nil
+}
+
+func (t *transaction) delete(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.lookup(id); !exists {
+		return dsdk.ErrNotFound
+	}
+	t.flows[id] = nil
+	t.deleted[id] = true
+	delete(t.history, id)
+	delete(t.progress, id)
+	for messageID, message := range t.messages {
+		if message.ProcessID == id {
+			delete(t.messages, messageID)
+		}
+	}
+	return nil
+}
+
+// updateProgress buffers a progress update. The existence check does not record the revision of the flow, so concurrent
+// writes of the flow do not conflict with the update.
+func (t *transaction) updateProgress(id string, update dsdk.ProgressUpdate, timestamp int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.base(id, false); !exists {
+		return dsdk.ErrNotFound
+	}
+	t.progress[id] = append(t.progress[id], progressUpdate{update: update, timestamp: timestamp})
+	return nil
+}
+
+func (t *transaction) query(query dsdk.FlowQuery) dsdk.Iterator[*dsdk.DataFlow] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var flows []*dsdk.DataFlow
+	t.store.mu.RLock()
+	for id, flow := range t.store.flows {
+		if _, written := t.flows[id]; written || !query.Matches(flow) {
+			continue
+		}
+		t.observe(id)
+		flowCopy := *flow
+		flows = append(flows, &flowCopy)
+	}
+	t.store.mu.RUnlock()
+
+	for _, flow := range t.flows {
+		if flow != nil && query.Matches(flow) {
+			flowCopy := *flow
+			flows = append(flows, &flowCopy)
+		}
+	}
+	for _, flow := range flows {
+		t.applyProgress(flow)
+	}
+	return newQueryIterator(flows, query)
+}
+
+func (t *transaction) historyOf(id string) ([]dsdk.TransitionRecord, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.lookup(id); !exists {
+		return nil, dsdk.ErrNotFound
+	}
+	var history []dsdk.TransitionRecord
+	if !t.deleted[id] {
+		t.store.mu.RLock()
+		history = slices.Clone(t.store.history[id])
+		t.store.mu.RUnlock()
+	}
+	return append(history, t.history[id]...), nil
+}
+
+func (t *transaction) findMessage(messageID string) (*dsdk.ProcessedMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if message, exists := t.messages[messageID]; exists {
+		return copyMessage(message), nil
+	}
+
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	message, exists := t.store.messages[messageID]
+	if !exists || t.deleted[message.ProcessID] {
+		return nil, dsdk.ErrNotFound
+	}
+	return copyMessage(message), nil
+}
+
+func (t *transaction) saveMessage(message *dsdk.ProcessedMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, exists := t.messages[message.MessageID]; exists {
+		return dsdk.ErrConflict
+	}
+	t.store.mu.RLock()
+	_, exists := t.store.messages[message.MessageID]
+	t.store.mu.RUnlock()
+	if exists {
+		return dsdk.ErrConflict
+	}
+	t.messages[message.MessageID] = copyMessage(message)
+	return nil
+}
+
+// commit validates that no flow observed by the transaction has been modified since and applies the buffered writes.
+// Read-only transactions are not validated.
+func (t *transaction) commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = true
+
+	s := t.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writes := len(t.flows) > 0 || len(t.messages) > 0
+	for id, revision := range t.reads {
+		if writes && s.revisions[id] != revision {
+			return fmt.Errorf("%w: data flow %s was modified by a concurrent transaction", dsdk.ErrConflict, id)
+		}
+	}
+	for messageID := range t.messages {
+		if _, exists := s.messages[messageID]; exists {
+			return fmt.Errorf("%w: message %s was processed by a concurrent transaction", dsdk.ErrConflict, messageID)
+		}
+	}
+
+	for id := range t.deleted {
+		s.remove(id)
+	}
+	for id, flow := range t.flows {
+		if flow == nil {
+			continue
+		}
+		if stored, exists := s.flows[id]; exists {
+			// progress committed by others since the flow was read is kept
+			flow.Progress = savedProgress(stored.Progress, flow.Progress)
+		}
+		s.flows[id] = flow
+		s.touch(id)
+	}
+	for id, updates := range t.progress {
+		flow, exists := s.flows[id]
+		if !exists {
+			continue
+		}
+		for _, pending := range updates {
+			flow.Progress.Apply(pending.update, pending.timestamp)
+		}
+	}
+	for id, records := range t.history {
+		s.history[id] = append(s.history[id], records...)
+	}
+	for messageID, message := range t.messages {
+		s.messages[messageID] = message
+	}
+	return nil
+}
+
+// discard drops the buffered writes
+func (t *transaction) discard() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done = true
+	t.flows, t.deleted, t.history, t.messages, t.progress = nil, nil, nil, nil, nil
+}
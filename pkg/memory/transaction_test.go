@@ -0,0 +1,254 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKeyType struct{}
+
+func TestInMemoryTrxContext_PropagatesContext(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testKeyType{}, "value"))
+	cancel()
+
+	err := NewInMemoryTrxContext(store).Execute(ctx, func(ctx context.Context) error {
+		assert.Equal(t, "value", ctx.Value(testKeyType{}))
+		return ctx.Err()
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestInMemoryTrxContext_ZeroValue(t *testing.T) {
+	err := InMemoryTrxContext{}.Execute(context.Background(), func(ctx context.Context) error {
+		t.Error("the operation must not run without a store")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrUnboundTrxContext)
+}
+
+func TestInMemoryTrxContext_Rollback(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "existing", State: dsdk.Started}))
+	forced := errors.New("forced error")
+
+	err := trxContext.Execute(ctx, func(ctx context.Context) error {
+		require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "created"}))
+		require.NoError(t, store.Save(ctx, &dsdk.DataFlow{ID: "existing", State: dsdk.Terminated}))
+		require.NoError(t, store.UpdateProgress(ctx, "existing", dsdk.ProgressUpdate{Bytes: 10}, 1))
+		require.NoError(t, store.SaveMessage(ctx, &dsdk.ProcessedMessage{MessageID: "message-1", ProcessID: "existing"}))
+
+		// writes are visible within the transaction
+		flow, err := store.FindById(ctx, "existing")
+		require.NoError(t, err)
+		assert.Equal(t, dsdk.Terminated, flow.State)
+		assert.Equal(t, int64(10), flow.Progress.BytesTransferred)
+		return forced
+	})
+
+	assert.ErrorIs(t, err, forced)
+	_, err = store.FindById(ctx, "created")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	flow, err := store.FindById(ctx, "existing")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Started, flow.State)
+	assert.Zero(t, flow.Progress.BytesTransferred)
+	_, err = store.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestInMemoryTrxContext_RollbackOnPanic(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+
+	assert.PanicsWithValue(t, "forced panic", func() {
+		_ = trxContext.Execute(ctx, func(ctx context.Context) error {
+			_ = store.Create(ctx, &dsdk.DataFlow{ID: "created"})
+			panic("forced panic")
+		})
+	})
+
+	_, err := store.FindById(ctx, "created")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestInMemoryTrxContext_Commit(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "deleted"}))
+	require.NoError(t, store.SaveMessage(ctx, &dsdk.ProcessedMessage{MessageID: "message-1", ProcessID: "deleted"}))
+
+	err := trxContext.Execute(ctx, func(ctx context.Context) error {
+		flow := &dsdk.DataFlow{ID: "created", State: dsdk.Starting}
+		flow.SetTransitionSource("message-2", "")
+		require.NoError(t, flow.TransitionToStarted())
+		require.NoError(t, store.Create(ctx, flow))
+		require.NoError(t, store.Delete(ctx, "deleted"))
+		_, err := store.FindMessage(ctx, "message-1")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+		return nil
+	})
+
+	require.NoError(t, err)
+	flow, err := store.FindById(ctx, "created")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Started, flow.State)
+	history, err := store.History(ctx, "created")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "message-2", history[0].MessageID)
+	_, err = store.FindById(ctx, "deleted")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	_, err = store.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestInMemoryTrxContext_Isolation(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+
+	err := trxContext.Execute(ctx, func(txCtx context.Context) error {
+		require.NoError(t, store.Create(txCtx, &dsdk.DataFlow{ID: "flow-1", State: dsdk.Completed}))
+
+		// uncommitted writes are not visible outside the transaction
+		_, err := store.FindById(ctx, "flow-1")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+		iterator, err := store.Query(ctx, dsdk.FlowQuery{})
+		require.NoError(t, err)
+		assert.False(t, iterator.Next())
+
+		// but are returned by queries within it
+		iterator, err = store.Query(txCtx, dsdk.FlowQuery{States: []dsdk.DataFlowState{dsdk.Completed}})
+		require.NoError(t, err)
+		require.True(t, iterator.Next())
+		assert.Equal(t, "flow-1", iterator.Get().ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	_, err = store.FindById(ctx, "flow-1")
+	assert.NoError(t, err)
+}
+
+func TestInMemoryTrxContext_Conflict(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "flow-1", State: dsdk.Started}))
+
+	err := trxContext.Execute(ctx, func(txCtx context.Context) error {
+		flow, err := store.FindById(txCtx, "flow-1")
+		require.NoError(t, err)
+
+		// a concurrent transaction modifies the flow and commits first
+		require.NoError(t, trxContext.Execute(ctx, func(ctx context.Context) error {
+			return store.Save(ctx, &dsdk.DataFlow{ID: "flow-1", State: dsdk.Suspended})
+		}))
+
+		flow.State = dsdk.Terminated
+		return store.Save(txCtx, flow)
+	})
+
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+	flow, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Suspended, flow.State)
+}
+
+func TestInMemoryTrxContext_ReadOnlyDoesNotConflict(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "flow-1", State: dsdk.Started}))
+
+	err := trxContext.Execute(ctx, func(txCtx context.Context) error {
+		_, err := store.FindById(txCtx, "flow-1")
+		require.NoError(t, err)
+		return store.Save(ctx, &dsdk.DataFlow{ID: "flow-1", State: dsdk.Suspended})
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestInMemoryTrxContext_ProgressDoesNotConflict(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "flow-1", State: dsdk.Started}))
+
+	err := trxContext.Execute(ctx, func(txCtx context.Context) error {
+		flow, err := store.FindById(txCtx, "flow-1")
+		require.NoError(t, err)
+		require.NoError(t, store.UpdateProgress(txCtx, "flow-1", dsdk.ProgressUpdate{Bytes: 5}, 1))
+
+		// a transfer reports progress while the transaction is open
+		require.NoError(t, store.UpdateProgress(ctx, "flow-1", dsdk.ProgressUpdate{Bytes: 10}, 2))
+
+		flow.State = dsdk.Suspended
+		return store.Save(txCtx, flow)
+	})
+
+	require.NoError(t, err)
+	flow, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Suspended, flow.State)
+	assert.Equal(t, int64(15), flow.Progress.BytesTransferred)
+}
+
+func TestInMemoryTrxContext_ConcurrentCreateConflict(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+
+	err := trxContext.Execute(ctx, func(txCtx context.Context) error {
+		require.NoError(t, store.Create(txCtx, &dsdk.DataFlow{ID: "flow-1"}))
+		require.NoError(t, store.Create(ctx, &dsdk.DataFlow{ID: "flow-1"}))
+		return nil
+	})
+
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+}
+
+func TestInMemoryTrxContext_NestedJoins(t *testing.T) {
+	store := NewInMemoryStore()
+	trxContext := NewInMemoryTrxContext(store)
+	ctx := context.Background()
+
+	err := trxContext.Execute(ctx, func(outer context.Context) error {
+		require.NoError(t, trxContext.Execute(outer, func(inner context.Context) error {
+			return store.Create(inner, &dsdk.DataFlow{ID: "flow-1"})
+		}))
+		// the inner operation has not committed on its own
+		_, err := store.FindById(ctx, "flow-1")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+		return errors.New("forced error")
+	})
+
+	assert.Error(t, err)
+	_, err = store.FindById(ctx, "flow-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
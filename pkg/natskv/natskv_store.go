@@ -0,0 +1,435 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natskv
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DefaultBucket is the name of the key-value bucket used by CreateBucket if none is given.
+const DefaultBucket = "dataflows"
+
+// Store is a DataplaneStore backed by a JetStream key-value bucket. Each flow is stored as a JSON value under a key
+// derived from its ID. DataFlow.Version holds the revision of the entry: Create and Save set it to the new revision
+// and Save only succeeds if the stored revision still matches, which provides optimistic concurrency across instances.
+//
+// The store keeps an index entry per flow under a key derived from its state, which Query uses to read only the flows
+// in the requested states. Index entries are written before the flow and removed after it, so an index entry may be
+// stale but a flow is never missing from the index of its state.
+//
+// Store implements the MessageLedger extension. It does not implement ProgressStore and HistoryStore: progress is
+// saved with the flow and transition history is not recorded.
+type Store struct {
+	kv jetstream.KeyValue
+}
+
+// NewStore creates a store for the bucket.
+func NewStore(kv jetstream.KeyValue) *Store {
+	return &Store{kv: kv}
+}
+
+// Ping verifies that the bucket is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	if _, err := s.kv.Status(ctx); err != nil {
+		return fmt.Errorf("bucket %s unavailable: %w", s.kv.Bucket(), err)
+	}
+	return nil
+}
+
+// CreateBucket creates the key-value bucket for the store or returns it if it exists. Only the latest revision of a
+// flow is retained.
+func CreateBucket(ctx context.Context, js jetstream.JetStream, bucket string) (jetstream.KeyValue, error) {
+	if bucket == "" {
+		bucket = DefaultBucket
+	}
+	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "Data plane SDK data flows",
+		History:     1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+	}
+	return kv, nil
+}
+
+// FindById returns the flow with its Version set to the revision of the entry.
+func (s *Store) FindById(ctx context.Context, id string) (*dsdk.DataFlow, error) {
+	if id == "" {
+		return nil, dsdk.ErrInvalidInput
+	}
+	entry, err := s.kv.Get(ctx, key(id))
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil, dsdk.ErrNotFound
+		}
+		return nil, err
+	}
+	return decode(entry)
+}
+
+// Create stores a new flow. Returns ErrConflict if a flow with the ID exists.
+func (s *Store) Create(ctx context.Context, flow *dsdk.DataFlow) error {
+	if flow == nil || flow.ID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return err
+	}
+	if _, err := s.kv.Put(ctx, stateKey(flow.State, flow.ID), nil); err != nil {
+		return err
+	}
+	revision, err := s.kv.Create(ctx, key(flow.ID), data)
+	if err != nil {
+		if isWrongRevision(err) {
+			return dsdk.ErrConflict
+		}
+		return err
+	}
+	flow.Version = int64(revision)
+	return nil
+}
+
+// Save updates the flow if its Version matches the stored revision and returns ErrConflict otherwise. A flow with a
+// zero Version has not been loaded from the store and is created.
+func (s *Store) Save(ctx context.Context, flow *dsdk.DataFlow) error {
+	if flow == nil || flow.ID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	if flow.Version == 0 {
+		return s.Create(ctx, flow)
+	}
+	stored, err := s.FindById(ctx, flow.ID)
+	if err != nil {
+		return err
+	}
+	if stored.Version != flow.Version {
+		return fmt.Errorf("%w: data flow %s was modified concurrently", dsdk.ErrConflict, flow.ID)
+	}
+	data, err := json.Marshal(flow)
+	if err != nil {
+		return err
+	}
+	if _, err := s.kv.Put(ctx, stateKey(flow.State, flow.ID), nil); err != nil {
+		return err
+	}
+	var previous jetstream.KeyValueEntry
+	if stored.State != flow.State {
+		if previous, err = s.kv.Get(ctx, stateKey(stored.State, flow.ID)); err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+			return err
+		}
+	}
+	revision, err := s.kv.Update(ctx, key(flow.ID), data, uint64(flow.Version))
+	if err != nil {
+		if isWrongRevision(err) {
+			return fmt.Errorf("%w: data flow %s was modified concurrently", dsdk.ErrConflict, flow.ID)
+		}
+		return err
+	}
+	flow.Version = int64(revision)
+	if previous != nil {
+		// the entry is kept if a concurrent save has moved the flow back to the previous state in the meantime
+		err := s.kv.Delete(ctx, previous.Key(), jetstream.LastRevision(previous.Revision()))
+		if err != nil && !isWrongRevision(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete purges the flow and its previous revisions from the bucket, together with its index entries and processed
+// messages.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return dsdk.ErrInvalidInput
+	}
+	if _, err := s.kv.Get(ctx, key(id)); err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return dsdk.ErrNotFound
+		}
+		return err
+	}
+	if err := s.kv.Purge(ctx, key(id)); err != nil {
+		return err
+	}
+
+	indexKeys, err := s.keys(ctx, "state.*."+key(id))
+	if err != nil {
+		return err
+	}
+	for _, indexKey := range indexKeys {
+		if err := s.kv.Purge(ctx, indexKey); err != nil {
+			return err
+		}
+	}
+	return s.deleteMessages(ctx, id)
+}
+
+// Query returns the flows matching the query ordered by state timestamp. If the query restricts the states, only the
+// flows in the state index of these states are read. Otherwise, all flows of the bucket are read, so the cost grows
+// with the number of stored flows.
+func (s *Store) Query(ctx context.Context, query dsdk.FlowQuery) (dsdk.Iterator[*dsdk.DataFlow], error) {
+	var flows []*dsdk.DataFlow
+	var err error
+	if len(query.States) > 0 {
+		flows, err = s.indexed(ctx, query.States)
+	} else {
+		flows, err = s.all(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	flows = slices.DeleteFunc(flows, func(flow *dsdk.DataFlow) bool {
+		return !query.Matches(flow)
+	})
+
+	slices.SortFunc(flows, func(a, b *dsdk.DataFlow) int {
+		return cmp.Or(cmp.Compare(a.StateTimestamp, b.StateTimestamp), cmp.Compare(a.ID, b.ID))
+	})
+	if query.Limit > 0 && len(flows) > query.Limit {
+		flows = flows[:query.Limit]
+	}
+	return &flowIterator{flows: flows, index: -1}, nil
+}
+
+// indexed reads the flows referenced by the state index entries of the states. Index entries of flows that have
+// since been deleted are skipped; those of flows that have changed state are filtered by the caller.
+func (s *Store) indexed(ctx context.Context, states []dsdk.DataFlowState) ([]*dsdk.DataFlow, error) {
+	filters := make([]string, 0, len(states))
+	for _, state := range states {
+		filters = append(filters, fmt.Sprintf("state.%d.*", state))
+	}
+	indexKeys, err := s.keys(ctx, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(indexKeys))
+	var flows []*dsdk.DataFlow
+	for _, indexKey := range indexKeys {
+		flowKey := indexKey[strings.LastIndex(indexKey, ".")+1:]
+		if seen[flowKey] {
+			continue
+		}
+		seen[flowKey] = true
+		entry, err := s.kv.Get(ctx, flowKey)
+		if err != nil {
+			if errors.Is(err, jetstream.ErrKeyNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		flow, err := decode(entry)
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, flow)
+	}
+	return flows, nil
+}
+
+// all reads every flow of the bucket. Flow keys consist of a single token, which excludes index and message entries.
+func (s *Store) all(ctx context.Context) ([]*dsdk.DataFlow, error) {
+	watcher, err := s.kv.Watch(ctx, "*", jetstream.IgnoreDeletes())
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.Stop()
+
+	var flows []*dsdk.DataFlow
+	for {
+		var entry jetstream.KeyValueEntry
+		select {
+		case entry = <-watcher.Updates():
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if entry == nil {
+			// all current entries have been delivered
+			return flows, nil
+		}
+		flow, err := decode(entry)
+		if err != nil {
+			return nil, err
+		}
+		flows = append(flows, flow)
+	}
+}
+
+// keys returns the keys matching the filters
+func (s *Store) keys(ctx context.Context, filters ...string) ([]string, error) {
+	lister, err := s.kv.ListKeysFiltered(ctx, filters...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = lister.Stop() }()
+
+	var keys []string
+	for k := range lister.Keys() {
+		keys = append(keys, k)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// FindMessage returns the processed message with the ID or ErrNotFound.
+func (s *Store) FindMessage(ctx context.Context, messageID string) (*dsdk.ProcessedMessage, error) {
+	entry, err := s.kv.Get(ctx, messageKey(messageID))
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return nil, dsdk.ErrNotFound
+		}
+		return nil, err
+	}
+	var message dsdk.ProcessedMessage
+	if err := json.Unmarshal(entry.Value(), &message); err != nil {
+		return nil, fmt.Errorf("failed to decode message %s: %w", messageID, err)
+	}
+	return &message, nil
+}
+
+// SaveMessage records a processed message. Returns ErrConflict if a message with the ID has been recorded. An entry
+// referencing the message from its flow is written first so that Delete finds it.
+func (s *Store) SaveMessage(ctx context.Context, message *dsdk.ProcessedMessage) error {
+	if message == nil || message.MessageID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if _, err := s.kv.Put(ctx, processMessageKey(message.ProcessID, message.MessageID), nil); err != nil {
+		return err
+	}
+	if _, err := s.kv.Create(ctx, messageKey(message.MessageID), data); err != nil {
+		if isWrongRevision(err) {
+			return dsdk.ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// deleteMessages purges the messages processed for the flow and the entries referencing them.
+func (s *Store) deleteMessages(ctx context.Context, id string) error {
+	referenceKeys, err := s.keys(ctx, "process."+key(id)+".*")
+	if err != nil {
+		return err
+	}
+	for _, referenceKey := range referenceKeys {
+		messageID, err := base64.RawURLEncoding.DecodeString(referenceKey[strings.LastIndex(referenceKey, ".")+1:])
+		if err != nil {
+			return fmt.Errorf("invalid message reference %s: %w", referenceKey, err)
+		}
+		// the reference may have been written for a message ID recorded by another flow
+		message, err := s.FindMessage(ctx, string(messageID))
+		switch {
+		case err == nil && message.ProcessID == id:
+			if err := s.kv.Purge(ctx, messageKey(message.MessageID)); err != nil {
+				return err
+			}
+		case err != nil && !errors.Is(err, dsdk.ErrNotFound):
+			return err
+		}
+		if err := s.kv.Purge(ctx, referenceKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flowIterator iterates over the result of a query
+type flowIterator struct {
+	flows []*dsdk.DataFlow
+	index int
+}
+
+func (it *flowIterator) Next() bool {
+	it.index++
+	return it.index < len(it.flows)
+}
+
+func (it *flowIterator) Get() *dsdk.DataFlow {
+	return it.flows[it.index]
+}
+
+func (it *flowIterator) Error() error {
+	return nil
+}
+
+func (it *flowIterator) Close() error {
+	return nil
+}
+
+// TransactionContext executes operations directly as key-value buckets do not support transactions. Consistency of
+// concurrent updates relies on the revision check performed by Store.Save.
+//
+// Writes are not atomic and not rolled back: if an operation fails after a write, the earlier writes persist. For
+// example, a flow saved by a processor stays saved if recording the message in the ledger fails afterwards, and a
+// retried message is then processed again against the updated flow.
+type TransactionContext struct{}
+
+func (TransactionContext) Execute(ctx context.Context, callback func(ctx context.Context) error) error {
+	return callback(ctx)
+}
+
+// key encodes the flow ID so that it only contains characters permitted in bucket keys
+func key(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// stateKey returns the key of the state index entry of a flow
+func stateKey(state dsdk.DataFlowState, id string) string {
+	return fmt.Sprintf("state.%d.%s", state, key(id))
+}
+
+// messageKey returns the key of a processed message
+func messageKey(messageID string) string {
+	return "message." + key(messageID)
+}
+
+// processMessageKey returns the key of the entry referencing a processed message from its flow
+func processMessageKey(processID string, messageID string) string {
+	return "process." + key(processID) + "." + key(messageID)
+}
+
+func decode(entry jetstream.KeyValueEntry) (*dsdk.DataFlow, error) {
+	var flow dsdk.DataFlow
+	if err := json.Unmarshal(entry.Value(), &flow); err != nil {
+		return nil, fmt.Errorf("failed to decode data flow %s: %w", entry.Key(), err)
+	}
+	flow.Version = int64(entry.Revision())
+	return &flow, nil
+}
+
+// isWrongRevision detects writes rejected because the expected revision of the key does not match.
+func isWrongRevision(err error) bool {
+	var apiErr *jetstream.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode == jetstream.JSErrCodeStreamWrongLastSequence
+	}
+	return errors.Is(err, jetstream.ErrKeyExists)
+}
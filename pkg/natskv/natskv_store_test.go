@@ -0,0 +1,276 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package natskv
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/storetest"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore starts an embedded JetStream-enabled server and returns a store for a fresh bucket.
+func newTestStore(t *testing.T) *Store {
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	require.NoError(t, err)
+	go ns.Start()
+	require.True(t, ns.ReadyForConnections(5*time.Second), "server not ready for connections")
+	t.Cleanup(ns.Shutdown)
+
+	nc, err := nats.Connect(ns.ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(nc.Close)
+
+	js, err := jetstream.New(nc)
+	require.NoError(t, err)
+	kv, err := CreateBucket(context.Background(), js, "")
+	require.NoError(t, err)
+	return NewStore(kv)
+}
+
+func newTestFlow(id string) *dsdk.DataFlow {
+	callbackURL, _ := url.Parse("http://example.com/callback")
+	return &dsdk.DataFlow{
+		ID:              id,
+		AgreementID:     "agreement-1",
+		RuntimeID:       "runtime-1",
+		CallbackAddress: dsdk.CallbackURL(*callbackURL),
+		TransferType:    dsdk.TransferType{DestinationType: "HttpData", FlowType: dsdk.Push},
+		SourceDataAddress: dsdk.DataAddress{Properties: map[string]any{
+			"endpoint": "http://example.com/source",
+		}},
+		State: dsdk.Started,
+	}
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) (dsdk.DataplaneStore, dsdk.TransactionContext) {
+		return newTestStore(t), TransactionContext{}
+	}, storetest.SkipRollback())
+}
+
+func TestStore_CreateAndFind(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	flow := newTestFlow("urn:uuid:flow/1")
+
+	require.NoError(t, store.Create(ctx, flow))
+	assert.NotZero(t, flow.Version)
+
+	found, err := store.FindById(ctx, flow.ID)
+	require.NoError(t, err)
+	assert.Equal(t, flow.ID, found.ID)
+	assert.Equal(t, flow.Version, found.Version)
+	assert.Equal(t, dsdk.Started, found.State)
+	assert.Equal(t, flow.TransferType, found.TransferType)
+	assert.Equal(t, "http://example.com/callback", found.CallbackAddress.URL().String())
+	assert.Equal(t, "http://example.com/source", found.SourceDataAddress.Properties["endpoint"])
+}
+
+func TestStore_Create_Conflict(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+
+	err := store.Create(ctx, newTestFlow("flow-1"))
+
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+}
+
+func TestStore_FindById_NotFound(t *testing.T) {
+	store := newTestStore(t)
+
+	_, err := store.FindById(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestStore_Save(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+
+	flow, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	created := flow.Version
+	flow.State = dsdk.Completed
+	require.NoError(t, store.Save(ctx, flow))
+	assert.Greater(t, flow.Version, created)
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Completed, found.State)
+	assert.Equal(t, flow.Version, found.Version)
+}
+
+func TestStore_Save_StaleRevision(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+
+	first, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	second, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+
+	first.State = dsdk.Suspended
+	require.NoError(t, store.Save(ctx, first))
+	second.State = dsdk.Terminated
+	err = store.Save(ctx, second)
+
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Suspended, found.State)
+}
+
+func TestStore_Save_CreatesNewFlow(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, newTestFlow("flow-1")))
+
+	_, err := store.FindById(ctx, "flow-1")
+	assert.NoError(t, err)
+}
+
+func TestStore_Delete(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+
+	_, err := store.FindById(ctx, "flow-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "flow-1"), dsdk.ErrNotFound)
+	// the ID can be reused after the flow has been deleted
+	assert.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+}
+
+func TestStore_Query(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	for i, state := range []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated, dsdk.Started} {
+		flow := newTestFlow(string(rune('a' + i)))
+		flow.State = state
+		flow.StateTimestamp = int64(300 - 100*i)
+		require.NoError(t, store.Create(ctx, flow))
+	}
+	require.NoError(t, store.Create(ctx, newTestFlow("deleted")))
+	require.NoError(t, store.Delete(ctx, "deleted"))
+
+	collect := func(query dsdk.FlowQuery) []string {
+		iterator, err := store.Query(ctx, query)
+		require.NoError(t, err)
+		defer iterator.Close()
+		var ids []string
+		for iterator.Next() {
+			ids = append(ids, iterator.Get().ID)
+		}
+		require.NoError(t, iterator.Error())
+		return ids
+	}
+
+	assert.Equal(t, []string{"b", "a"}, collect(dsdk.FlowQuery{
+		States: []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated},
+	}))
+	assert.Equal(t, []string{"c", "b"}, collect(dsdk.FlowQuery{StateTimestampBefore: 300}))
+	assert.Equal(t, []string{"c"}, collect(dsdk.FlowQuery{Limit: 1}))
+}
+
+func TestStore_Query_EmptyBucket(t *testing.T) {
+	store := newTestStore(t)
+
+	iterator, err := store.Query(context.Background(), dsdk.FlowQuery{})
+
+	require.NoError(t, err)
+	assert.False(t, iterator.Next())
+}
+
+func TestStore_Ping(t *testing.T) {
+	store := newTestStore(t)
+
+	assert.NoError(t, store.Ping(context.Background()))
+}
+
+func TestStore_Query_StateIndex(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-2")))
+	flow, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	flow.State = dsdk.Suspended
+	require.NoError(t, store.Save(ctx, flow))
+
+	collect := func(states ...dsdk.DataFlowState) []string {
+		iterator, err := store.Query(ctx, dsdk.FlowQuery{States: states})
+		require.NoError(t, err)
+		defer iterator.Close()
+		var ids []string
+		for iterator.Next() {
+			ids = append(ids, iterator.Get().ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"flow-2"}, collect(dsdk.Started))
+	assert.Equal(t, []string{"flow-1"}, collect(dsdk.Suspended))
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+	assert.Empty(t, collect(dsdk.Suspended))
+	keys, err := store.keys(ctx, "state.*.*")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1, "index entries of deleted flows must be purged")
+}
+
+func TestStore_MessageLedger(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+	message := &dsdk.ProcessedMessage{
+		MessageID:   "message-1",
+		ProcessID:   "flow-1",
+		MessageType: "start",
+		PayloadHash: "hash",
+		Response:    []byte(`{"state":"STARTED"}`),
+	}
+
+	_, err := store.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	require.NoError(t, store.SaveMessage(ctx, message))
+	assert.ErrorIs(t, store.SaveMessage(ctx, message), dsdk.ErrConflict)
+
+	found, err := store.FindMessage(ctx, "message-1")
+	require.NoError(t, err)
+	assert.Equal(t, message, found)
+
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+	_, err = store.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
@@ -0,0 +1,62 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// RetentionLockKey is the advisory lock key used by the retention janitor.
+const RetentionLockKey int64 = 0x64736b5f726574 // "dsk_ret"
+
+// AdvisoryLock is a dsdk.LeaderLock backed by a Postgres session-level advisory lock. Since session locks are bound to
+// a connection, a dedicated connection is held while the lock is acquired.
+type AdvisoryLock struct {
+	db   *sql.DB
+	key  int64
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewAdvisoryLock creates a lock for the given key. All instances must use the same key to coordinate.
+func NewAdvisoryLock(db *sql.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key}
+}
+
+func (l *AdvisoryLock) TryLock(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return false, nil // held by this instance
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if !acquired {
+		return false, conn.Close()
+	}
+	l.conn = conn
+	return true, nil
+}
+
+func (l *AdvisoryLock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return errors.New("advisory lock is not held")
+	}
+	_, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	closeErr := l.conn.Close()
+	l.conn = nil
+	return errors.Join(err, closeErr)
+}
@@ -0,0 +1,234 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MigrationLockKey is the advisory lock key held while migrations are applied.
+const MigrationLockKey int64 = 0x64736b5f6d6967 // "dsk_mig"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is a versioned schema change embedded in the package.
+type Migration struct {
+	Version  int
+	Name     string
+	SQL      string
+	Checksum string
+}
+
+// MigrationStatus describes a migration and whether it has been applied to the database.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations to a database. Applied migrations are tracked in the schema_migrations
+// table. A session-level advisory lock serializes concurrent instances, so a data plane can migrate on startup.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the embedded migrations.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations returns the embedded migrations in version order.
+func (m *Migrator) Migrations() []Migration {
+	return append([]Migration(nil), m.migrations...)
+}
+
+// Up applies all pending migrations in version order, each in its own transaction, and returns the number of
+// migrations applied. It fails if an applied migration has been modified since it was applied.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to obtain connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, MigrationLockKey); err != nil {
+		return 0, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// use a fresh context so the lock is released if ctx has been cancelled
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, MigrationLockKey)
+	}()
+
+	if err := createMigrationsTable(ctx, conn); err != nil {
+		return 0, err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if record, found := applied[migration.Version]; found {
+			if record.checksum != migration.Checksum {
+				return count, fmt.Errorf("migration %04d_%s has been modified after it was applied", migration.Version, migration.Name)
+			}
+			continue
+		}
+		if err := apply(ctx, conn, migration); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Status returns all embedded migrations and whether they have been applied. It does not modify the database.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain connection: %w", err)
+	}
+	defer conn.Close()
+
+	applied := make(map[int]migrationRecord)
+	var exists bool
+	if err := conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	if exists {
+		if applied, err = appliedMigrations(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		entry := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, found := applied[migration.Version]; found {
+			entry.Applied = true
+			entry.AppliedAt = time.UnixMilli(record.appliedAt)
+		}
+		status = append(status, entry)
+	}
+	return status, nil
+}
+
+type migrationRecord struct {
+	checksum  string
+	appliedAt int64
+}
+
+func createMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations
+		(
+			version       INTEGER PRIMARY KEY NOT NULL,
+			name          TEXT                NOT NULL,
+			checksum      TEXT                NOT NULL,
+			applied_at_ms BIGINT              NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int]migrationRecord, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, checksum, applied_at_ms FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]migrationRecord)
+	for rows.Next() {
+		var version int
+		var record migrationRecord
+		if err := rows.Scan(&version, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = record
+	}
+	return applied, rows.Err()
+}
+
+func apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return errors.Join(fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err), tx.Rollback())
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum, applied_at_ms) VALUES ($1, $2, $3, $4)`,
+		migration.Version, migration.Name, migration.Checksum, time.Now().UnixMilli())
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to record migration %04d_%s: %w", migration.Version, migration.Name, err), tx.Rollback())
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// loadMigrations reads migrations named <version>_<name>.sql from a directory. Versions must be unique.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, name, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !found || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %s, expected <version>_<name>.sql", entry.Name())
+		}
+		if existing, duplicate := seen[version]; duplicate {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, existing, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		sum := sha256.Sum256(content)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     name,
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
@@ -0,0 +1,66 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Migrator_Status(t *testing.T) {
+	migrator, err := NewMigrator(testDB)
+	require.NoError(t, err)
+
+	status, err := migrator.Status(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, status, len(migrator.Migrations()))
+	for _, entry := range status {
+		assert.True(t, entry.Applied, "migration %d not applied", entry.Version)
+		assert.False(t, entry.AppliedAt.IsZero())
+	}
+}
+
+func Test_Migrator_Up_Idempotent(t *testing.T) {
+	migrator, err := NewMigrator(testDB)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied)
+}
+
+func Test_Migrator_Up_Concurrent(t *testing.T) {
+	migrator, err := NewMigrator(testDB)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := migrator.Up(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func Test_Migrator_Up_ModifiedMigration(t *testing.T) {
+	migrator, err := NewMigrator(testDB)
+	require.NoError(t, err)
+	migrator.migrations[0].Checksum = "modified"
+
+	_, err = migrator.Up(ctx)
+
+	assert.ErrorContains(t, err, "has been modified")
+}
@@ -0,0 +1,63 @@
+//go:build postgres
+
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, migration.Name)
+		assert.NotEmpty(t, migration.SQL)
+		assert.Len(t, migration.Checksum, 64)
+	}
+	assert.Equal(t, "baseline", migrations[0].Name)
+}
+
+func Test_LoadMigrations_Order(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0010_later.sql":  {Data: []byte("SELECT 10;")},
+		"m/0002_second.sql": {Data: []byte("SELECT 2;")},
+		"m/README.md":       {Data: []byte("ignored")},
+	}
+
+	migrations, err := loadMigrations(fsys, "m")
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, 2, migrations[0].Version)
+	assert.Equal(t, "second", migrations[0].Name)
+	assert.Equal(t, 10, migrations[1].Version)
+	assert.Equal(t, "later", migrations[1].Name)
+}
+
+func Test_LoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing version", fstest.MapFS{"m/baseline.sql": {Data: []byte("SELECT 1;")}}},
+		{"non-numeric version", fstest.MapFS{"m/v1_baseline.sql": {Data: []byte("SELECT 1;")}}},
+		{"duplicate version", fstest.MapFS{
+			"m/0001_a.sql": {Data: []byte("SELECT 1;")},
+			"m/001_b.sql":  {Data: []byte("SELECT 1;")},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadMigrations(test.fsys, "m")
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,4 +1,5 @@
--- DataFlow persistence (PostgreSQL)
+-- Baseline DataFlow persistence schema (PostgreSQL). Uses IF NOT EXISTS so that databases created from the former
+-- dataflow_schema.sql are adopted without changes.
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
@@ -0,0 +1,6 @@
+-- Transfer progress of data flows
+ALTER TABLE data_flows
+    ADD COLUMN IF NOT EXISTS bytes_transferred    BIGINT NOT NULL DEFAULT 0, -- DataFlow.Progress.BytesTransferred
+    ADD COLUMN IF NOT EXISTS messages_transferred BIGINT NOT NULL DEFAULT 0, -- DataFlow.Progress.MessagesTransferred
+    ADD COLUMN IF NOT EXISTS expected_total       BIGINT NOT NULL DEFAULT 0, -- DataFlow.Progress.ExpectedTotal
+    ADD COLUMN IF NOT EXISTS last_activity_ms     BIGINT NOT NULL DEFAULT 0; -- DataFlow.Progress.LastActivity (epoch millis)
@@ -0,0 +1,12 @@
+-- Ledger of processed signaling messages used for MessageID based idempotency
+CREATE TABLE IF NOT EXISTS processed_messages
+(
+    message_id    TEXT PRIMARY KEY NOT NULL, -- ProcessedMessage.MessageID
+    process_id    TEXT             NOT NULL, -- ProcessedMessage.ProcessID
+    message_type  TEXT             NOT NULL, -- ProcessedMessage.MessageType
+    payload_hash  TEXT             NOT NULL, -- ProcessedMessage.PayloadHash (hex encoded SHA-256)
+    response      TEXT,                      -- ProcessedMessage.Response (JSON)
+    created_at_ms BIGINT           NOT NULL  -- ProcessedMessage.CreatedAt (epoch millis)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_messages_process ON processed_messages (process_id);
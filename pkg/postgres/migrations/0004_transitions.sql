@@ -0,0 +1,14 @@
+-- Append-only transition history of data flows, removed with the flow
+CREATE TABLE IF NOT EXISTS data_flow_transitions
+(
+    seq          BIGSERIAL PRIMARY KEY,                                       -- insertion order
+    flow_id      TEXT    NOT NULL REFERENCES data_flows (id) ON DELETE CASCADE, -- TransitionRecord.FlowID
+    from_state   INTEGER NOT NULL,                                            -- TransitionRecord.From
+    to_state     INTEGER NOT NULL,                                            -- TransitionRecord.To
+    reason       TEXT    NOT NULL DEFAULT '',                                 -- TransitionRecord.Reason
+    message_id   TEXT    NOT NULL DEFAULT '',                                 -- TransitionRecord.MessageID
+    caller       TEXT    NOT NULL DEFAULT '',                                 -- TransitionRecord.Caller
+    timestamp_ms BIGINT  NOT NULL                                             -- TransitionRecord.Timestamp (epoch millis)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_flow_transitions_flow ON data_flow_transitions (flow_id, seq);
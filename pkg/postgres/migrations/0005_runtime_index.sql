@@ -0,0 +1,2 @@
+-- Lookup of the active flows owned by a runtime, e.g. for recovery on startup
+CREATE INDEX IF NOT EXISTS idx_data_flows_runtime ON data_flows (runtime_id, state);
@@ -0,0 +1,2 @@
+-- Problem response of messages whose operation failed after persisting the flow, e.g. a fatal processor failure
+ALTER TABLE processed_messages ADD COLUMN IF NOT EXISTS failure TEXT; -- ProcessedMessage.Failure (JSON)
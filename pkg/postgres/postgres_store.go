@@ -7,13 +7,27 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
 	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
 )
 
+const dataFlowColumns = `id, version, consumer, agreement_id, dataset_id, runtime_id, participant_id, dataspace_context,
+	counterparty_id, callback_address, transfer_type_dest, transfer_type_flowtype, source_data_address, dest_data_address,
+	state, state_count, state_timestamp_ms, error_detail, created_at_ms, updated_at_ms, bytes_transferred,
+	messages_transferred, expected_total, last_activity_ms`
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 type PostgresStore struct {
 	db *sql.DB
 }
@@ -22,14 +36,152 @@ func NewStore(db *sql.DB) *PostgresStore {
 	return &PostgresStore{db: db}
 }
 
+// Ping verifies the connection to the database
+func (p PostgresStore) Ping(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// LockAdmission acquires a transaction-scoped advisory lock for the key, so that instances sharing the database count
+// and create flows for the key one at a time. Outside a transaction the lock is released immediately.
+func (p PostgresStore) LockAdmission(ctx context.Context, key string) error {
+	if _, err := p.conn(ctx).ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key); err != nil {
+		return fmt.Errorf("acquiring admission lock for %s: %w", key, err)
+	}
+	return nil
+}
+
+// conn returns the transaction bound to the context by DBTransactionContext, or the database if there is none.
+func (p PostgresStore) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(DBTransactionKey).(*sql.Tx); ok {
+		return tx
+	}
+	return p.db
+}
+
 func (p PostgresStore) FindById(ctx context.Context, id string) (*dsdk.DataFlow, error) {
-	query := `SELECT * FROM data_flows WHERE id = $1`
+	query := `SELECT ` + dataFlowColumns + ` FROM data_flows WHERE id = $1`
+
+	df, err := scanDataFlow(p.conn(ctx).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dsdk.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// Query returns the data flows matching the query ordered by state timestamp.
+func (p PostgresStore) Query(ctx context.Context, query dsdk.FlowQuery) (dsdk.Iterator[*dsdk.DataFlow], error) {
+	where, args := whereClause(query)
+	statement := `SELECT ` + dataFlowColumns + ` FROM data_flows` + where
+	statement += ` ORDER BY state_timestamp_ms, id`
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		statement += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := p.conn(ctx).QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &rowIterator{rows: rows}, nil
+}
+
+// CountFlows returns the number of data flows matching the query, ignoring its limit.
+func (p PostgresStore) CountFlows(ctx context.Context, query dsdk.FlowQuery) (int, error) {
+	where, args := whereClause(query)
+	var count int
+	if err := p.conn(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM data_flows`+where, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// whereClause returns the WHERE clause selecting the data flows matching the query and its arguments
+func whereClause(query dsdk.FlowQuery) (string, []any) {
+	var conditions []string
+	var args []any
+	if len(query.States) > 0 {
+		states := make([]int64, 0, len(query.States))
+		for _, state := range query.States {
+			states = append(states, int64(state))
+		}
+		args = append(args, pq.Array(states))
+		conditions = append(conditions, fmt.Sprintf("state = ANY($%d)", len(args)))
+	}
+	if query.StateTimestampBefore > 0 {
+		args = append(args, query.StateTimestampBefore)
+		conditions = append(conditions, fmt.Sprintf("state_timestamp_ms < $%d", len(args)))
+	}
+	if query.RuntimeID != "" {
+		args = append(args, query.RuntimeID)
+		conditions = append(conditions, fmt.Sprintf("runtime_id = $%d", len(args)))
+	}
+	if query.ParticipantID != "" {
+		args = append(args, query.ParticipantID)
+		conditions = append(conditions, fmt.Sprintf("participant_id = $%d", len(args)))
+	}
+	if query.CounterPartyID != "" {
+		args = append(args, query.CounterPartyID)
+		conditions = append(conditions, fmt.Sprintf("counterparty_id = $%d", len(args)))
+	}
+	if query.AgreementID != "" {
+		args = append(args, query.AgreementID)
+		conditions = append(conditions, fmt.Sprintf("agreement_id = $%d", len(args)))
+	}
+	if query.DatasetID != "" {
+		args = append(args, query.DatasetID)
+		conditions = append(conditions, fmt.Sprintf("dataset_id = $%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+// rowIterator iterates over data flow query results
+type rowIterator struct {
+	rows    *sql.Rows
+	current *dsdk.DataFlow
+	err     error
+}
+
+func (it *rowIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+	it.current, it.err = scanDataFlow(it.rows)
+	return it.err == nil
+}
 
+func (it *rowIterator) Get() *dsdk.DataFlow {
+	return it.current
+}
+
+func (it *rowIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+func (it *rowIterator) Close() error {
+	return it.rows.Close()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanDataFlow reads a data flow selected with dataFlowColumns
+func scanDataFlow(row scanner) (*dsdk.DataFlow, error) {
 	var df dsdk.DataFlow
 	var callbackAddressJson string
 	var sourceDataAddressJson, destDataAddressJson *string
 
-	err := p.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&df.ID,
 		&df.Version,
 		&df.Consumer,
@@ -50,11 +202,11 @@ func (p PostgresStore) FindById(ctx context.Context, id string) (*dsdk.DataFlow,
 		&df.ErrorDetail,
 		&df.CreatedAt,
 		&df.UpdatedAt,
+		&df.Progress.BytesTransferred,
+		&df.Progress.MessagesTransferred,
+		&df.Progress.ExpectedTotal,
+		&df.Progress.LastActivity,
 	)
-
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, dsdk.ErrNotFound
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -98,17 +250,23 @@ func (p PostgresStore) Create(ctx context.Context, flow *dsdk.DataFlow) error {
 		    source_data_address,
 		    dest_data_address,
 		    state,
+		    state_count,
 		    state_timestamp_ms,
 		    error_detail,
 		    created_at_ms,
-		    updated_at_ms
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`
+		    updated_at_ms,
+		    bytes_transferred,
+		    messages_transferred,
+		    expected_total,
+		    last_activity_ms,
+		    version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`
 
 	cba, err := flow.CallbackAddress.MarshalJSON()
 	if err != nil {
 		return err
 	}
-	_, err = p.db.ExecContext(ctx, query,
+	_, err = p.conn(ctx).ExecContext(ctx, query,
 		flow.ID,
 		flow.Consumer,
 		flow.AgreementID,
@@ -123,10 +281,16 @@ func (p PostgresStore) Create(ctx context.Context, flow *dsdk.DataFlow) error {
 		toJson(flow.SourceDataAddress),
 		toJson(flow.DestinationDataAddress),
 		flow.State,
+		flow.StateCount,
 		time.Now().UnixMilli(),
 		flow.ErrorDetail,
 		time.Now().UnixMilli(),
 		time.Now().UnixMilli(),
+		flow.Progress.BytesTransferred,
+		flow.Progress.MessagesTransferred,
+		flow.Progress.ExpectedTotal,
+		flow.Progress.LastActivity,
+		flow.Version,
 	)
 
 	if err != nil {
@@ -136,14 +300,16 @@ func (p PostgresStore) Create(ctx context.Context, flow *dsdk.DataFlow) error {
 		return err
 	}
 
-	return nil
+	return p.appendHistory(ctx, flow)
 }
 
+// Save updates the data flow or creates it if it does not exist. An update only succeeds if the Version of the flow
+// matches the stored one and increments it; otherwise ErrConflict is returned.
 func (p PostgresStore) Save(ctx context.Context, flow *dsdk.DataFlow) error {
 	if flow.ID == "" {
 		return dsdk.ErrInvalidInput
 	}
-	if exists(p.db, ctx, flow.ID) {
+	if exists(p.conn(ctx), ctx, flow.ID) {
 		// update
 		query := `
 		UPDATE data_flows
@@ -161,12 +327,15 @@ func (p PostgresStore) Save(ctx context.Context, flow *dsdk.DataFlow) error {
 		    source_data_address = $11,
 		    dest_data_address = $12,
 		    state = $13,
-			state_timestamp_ms = $14,
-		    error_detail = $15,
-		    updated_at_ms = $16
-		WHERE id = $17`
+		    state_count = $14,
+			state_timestamp_ms = $15,
+		    error_detail = $16,
+		    updated_at_ms = $17,
+		    expected_total = COALESCE(NULLIF($18, 0), expected_total),
+		    version = version + 1
+		WHERE id = $19 AND version = $20`
 
-		_, err := p.db.ExecContext(ctx, query,
+		res, err := p.conn(ctx).ExecContext(ctx, query,
 			flow.Consumer,
 			flow.AgreementID,
 			flow.DatasetID,
@@ -180,21 +349,114 @@ func (p PostgresStore) Save(ctx context.Context, flow *dsdk.DataFlow) error {
 			toJson(flow.SourceDataAddress),
 			toJson(flow.DestinationDataAddress),
 			flow.State,
+			flow.StateCount,
 			flow.StateTimestamp,
 			flow.ErrorDetail,
 			time.Now().UnixMilli(),
-			flow.ID)
+			flow.Progress.ExpectedTotal,
+			flow.ID,
+			flow.Version)
 		if err != nil {
 			return err
 		}
-		return nil
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("%w: data flow %s was modified concurrently", dsdk.ErrConflict, flow.ID)
+		}
+		flow.Version++
+		return p.appendHistory(ctx, flow)
 	}
 	return p.Create(ctx, flow)
 }
 
+// History returns the transition history of a data flow in chronological order.
+func (p PostgresStore) History(ctx context.Context, id string) ([]dsdk.TransitionRecord, error) {
+	if !exists(p.conn(ctx), ctx, id) {
+		return nil, dsdk.ErrNotFound
+	}
+	query := `SELECT flow_id, from_state, to_state, reason, message_id, caller, timestamp_ms
+		FROM data_flow_transitions WHERE flow_id = $1 ORDER BY seq`
+
+	rows, err := p.conn(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []dsdk.TransitionRecord{}
+	for rows.Next() {
+		var record dsdk.TransitionRecord
+		if err := rows.Scan(
+			&record.FlowID,
+			&record.From,
+			&record.To,
+			&record.Reason,
+			&record.MessageID,
+			&record.Caller,
+			&record.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, record)
+	}
+	return history, rows.Err()
+}
+
+// appendHistory inserts the pending transitions of the flow. Callers must use the transaction of the flow update so
+// both are written atomically.
+func (p PostgresStore) appendHistory(ctx context.Context, flow *dsdk.DataFlow) error {
+	query := `INSERT INTO data_flow_transitions (flow_id, from_state, to_state, reason, message_id, caller, timestamp_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, record := range flow.TakeTransitions() {
+		_, err := p.conn(ctx).ExecContext(ctx, query,
+			flow.ID,
+			record.From,
+			record.To,
+			record.Reason,
+			record.MessageID,
+			record.Caller,
+			record.Timestamp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateProgress increments the progress counters of a data flow in a single statement.
+func (p PostgresStore) UpdateProgress(ctx context.Context, id string, update dsdk.ProgressUpdate, timestamp int64) error {
+	if id == "" {
+		return dsdk.ErrInvalidInput
+	}
+	query := `
+		UPDATE data_flows
+		SET
+		    bytes_transferred = bytes_transferred + $1,
+		    messages_transferred = messages_transferred + $2,
+		    expected_total = CASE WHEN $3 > 0 THEN $3 ELSE expected_total END,
+		    last_activity_ms = $4
+		WHERE id = $5`
+	res, err := p.conn(ctx).ExecContext(ctx, query, update.Bytes, update.Messages, update.ExpectedTotal, timestamp, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return dsdk.ErrNotFound
+	}
+	return nil
+}
+
 func (p PostgresStore) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM data_flows WHERE id = $1`
-	res, err := p.db.ExecContext(ctx, query, id)
+	res, err := p.conn(ctx).ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -205,6 +467,71 @@ func (p PostgresStore) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return dsdk.ErrNotFound
 	}
+	_, err = p.conn(ctx).ExecContext(ctx, `DELETE FROM processed_messages WHERE process_id = $1`, id)
+	return err
+}
+
+func (p PostgresStore) FindMessage(ctx context.Context, messageID string) (*dsdk.ProcessedMessage, error) {
+	query := `SELECT message_id, process_id, message_type, payload_hash, response, failure, created_at_ms
+		FROM processed_messages WHERE message_id = $1`
+
+	var message dsdk.ProcessedMessage
+	var response, failure *string
+	err := p.conn(ctx).QueryRowContext(ctx, query, messageID).Scan(
+		&message.MessageID,
+		&message.ProcessID,
+		&message.MessageType,
+		&message.PayloadHash,
+		&response,
+		&failure,
+		&message.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dsdk.ErrNotFound
+		}
+		return nil, err
+	}
+	if response != nil {
+		message.Response = []byte(*response)
+	}
+	if failure != nil {
+		message.Failure = []byte(*failure)
+	}
+	return &message, nil
+}
+
+func (p PostgresStore) SaveMessage(ctx context.Context, message *dsdk.ProcessedMessage) error {
+	if message == nil || message.MessageID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	query := `INSERT INTO processed_messages (message_id, process_id, message_type, payload_hash, response, failure,
+		created_at_ms) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	var response, failure *string
+	if message.Response != nil {
+		r := string(message.Response)
+		response = &r
+	}
+	if message.Failure != nil {
+		f := string(message.Failure)
+		failure = &f
+	}
+	_, err := p.conn(ctx).ExecContext(ctx, query,
+		message.MessageID,
+		message.ProcessID,
+		message.MessageType,
+		message.PayloadHash,
+		response,
+		failure,
+		message.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return dsdk.ErrConflict
+		}
+		return err
+	}
 	return nil
 }
 
@@ -218,7 +545,7 @@ func toJson(v any) *string {
 	return &s
 }
 
-func exists(db *sql.DB, ctx context.Context, id string) bool {
+func exists(db querier, ctx context.Context, id string) bool {
 	query := `SELECT COUNT(*) FROM data_flows WHERE id = $1`
 	var count int
 	err := db.QueryRowContext(ctx, query, id).Scan(&count)
@@ -5,7 +5,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"os"
 	"testing"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/storetest"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 )
@@ -52,13 +52,22 @@ func setupDb(t *testing.T) (testcontainers.Container, context.Context) {
 		t.Fatal(err)
 	}
 	testDB = db
-	// Apply schema (expects the SQL file to be available in repo)
-	if _, err := testDB.ExecContext(ctx, schema); err != nil {
+	if err := migrate(ctx, testDB); err != nil {
 		panic(err)
 	}
 	return container, ctx
 }
 
+func Test_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) (dsdk.DataplaneStore, dsdk.TransactionContext) {
+		// the suite expects an empty store
+		if _, err := testDB.ExecContext(ctx, `TRUNCATE data_flows, processed_messages CASCADE`); err != nil {
+			t.Fatalf("truncate failed: %v", err)
+		}
+		return NewStore(testDB), NewDBTransactionContext(testDB)
+	})
+}
+
 // Smoke test ensuring container and DB are usable.
 func Test_PostgresContainer_Ready(t *testing.T) {
 	if testDB == nil {
@@ -189,3 +198,152 @@ func Test_FindById_NotExists(t *testing.T) {
 	_, err := store.FindById(ctx, "non-existing")
 	assert.ErrorIs(t, err, dsdk.ErrNotFound)
 }
+
+func Test_UpdateProgress(t *testing.T) {
+	id := uuid.New().String()
+	err := store.Create(ctx, &dsdk.DataFlow{
+		ID: id,
+	})
+	assert.NoError(t, err)
+
+	err = store.UpdateProgress(ctx, id, dsdk.ProgressUpdate{Bytes: 100, Messages: 1, ExpectedTotal: 1000}, 42)
+	assert.NoError(t, err)
+	err = store.UpdateProgress(ctx, id, dsdk.ProgressUpdate{Bytes: 50, Messages: 2}, 43)
+	assert.NoError(t, err)
+
+	found, err := store.FindById(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), found.Progress.BytesTransferred)
+	assert.Equal(t, int64(3), found.Progress.MessagesTransferred)
+	assert.Equal(t, int64(1000), found.Progress.ExpectedTotal)
+	assert.Equal(t, int64(43), found.Progress.LastActivity)
+}
+
+func Test_UpdateProgress_NotExists(t *testing.T) {
+	err := store.UpdateProgress(ctx, "non-existing", dsdk.ProgressUpdate{Bytes: 1}, 1)
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func Test_SaveMessage_FindMessage(t *testing.T) {
+	messageID := uuid.New().String()
+	err := store.SaveMessage(ctx, &dsdk.ProcessedMessage{
+		MessageID:   messageID,
+		ProcessID:   "process123",
+		MessageType: dsdk.StartMessageType,
+		PayloadHash: "hash",
+		Response:    []byte(`{"state":2}`),
+		CreatedAt:   42,
+	})
+	assert.NoError(t, err)
+
+	found, err := store.FindMessage(ctx, messageID)
+	assert.NoError(t, err)
+	assert.Equal(t, "process123", found.ProcessID)
+	assert.Equal(t, dsdk.StartMessageType, found.MessageType)
+	assert.Equal(t, "hash", found.PayloadHash)
+	assert.JSONEq(t, `{"state":2}`, string(found.Response))
+	assert.Equal(t, int64(42), found.CreatedAt)
+}
+
+func Test_SaveMessage_Duplicate(t *testing.T) {
+	message := &dsdk.ProcessedMessage{MessageID: uuid.New().String(), MessageType: dsdk.TerminateMessageType}
+	assert.NoError(t, store.SaveMessage(ctx, message))
+
+	err := store.SaveMessage(ctx, message)
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+}
+
+func Test_FindMessage_NotExists(t *testing.T) {
+	_, err := store.FindMessage(ctx, "non-existing")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func Test_History(t *testing.T) {
+	id := uuid.New().String()
+	flow := &dsdk.DataFlow{ID: id, State: dsdk.Starting}
+	flow.SetTransitionSource("message-1", "did:web:provider.com")
+	assert.NoError(t, flow.TransitionToStarted())
+	assert.NoError(t, store.Create(ctx, flow))
+
+	found, err := store.FindById(ctx, id)
+	assert.NoError(t, err)
+	assert.NoError(t, found.TransitionToSuspended("maintenance"))
+	assert.NoError(t, store.Save(ctx, found))
+
+	history, err := store.History(ctx, id)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, dsdk.Starting, history[0].From)
+	assert.Equal(t, dsdk.Started, history[0].To)
+	assert.Equal(t, "message-1", history[0].MessageID)
+	assert.Equal(t, "did:web:provider.com", history[0].Caller)
+	assert.Equal(t, dsdk.Suspended, history[1].To)
+	assert.Equal(t, "maintenance", history[1].Reason)
+
+	assert.NoError(t, store.Delete(ctx, id))
+	var num int
+	_ = testDB.QueryRowContext(ctx, "SELECT COUNT(*) FROM data_flow_transitions WHERE flow_id = $1", id).Scan(&num)
+	assert.Equal(t, 0, num)
+}
+
+func Test_History_NotExists(t *testing.T) {
+	_, err := store.History(ctx, "non-existing")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func Test_Query(t *testing.T) {
+	agreementID := uuid.New().String()
+	now := time.Now().UnixMilli()
+	flows := []*dsdk.DataFlow{
+		{ID: uuid.New().String(), AgreementID: agreementID, State: dsdk.Completed},
+		{ID: uuid.New().String(), AgreementID: agreementID, State: dsdk.Terminated},
+		{ID: uuid.New().String(), AgreementID: agreementID, State: dsdk.Started},
+	}
+	for i, flow := range flows {
+		assert.NoError(t, store.Create(ctx, flow))
+		// Create sets the state timestamp to the current time, move it into the past
+		_, err := testDB.ExecContext(ctx, "UPDATE data_flows SET state = $1, state_timestamp_ms = $2 WHERE id = $3",
+			flow.State, now-int64(1000*(len(flows)-i)), flow.ID)
+		assert.NoError(t, err)
+	}
+
+	iterator, err := store.Query(ctx, dsdk.FlowQuery{
+		States:               []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated},
+		StateTimestampBefore: now,
+	})
+	assert.NoError(t, err)
+	var ids []string
+	for iterator.Next() {
+		if iterator.Get().AgreementID == agreementID {
+			ids = append(ids, iterator.Get().ID)
+		}
+	}
+	assert.NoError(t, iterator.Error())
+	assert.NoError(t, iterator.Close())
+	assert.Equal(t, []string{flows[0].ID, flows[1].ID}, ids)
+
+	iterator, err = store.Query(ctx, dsdk.FlowQuery{States: []dsdk.DataFlowState{dsdk.Completed}, Limit: 1})
+	assert.NoError(t, err)
+	assert.True(t, iterator.Next())
+	assert.False(t, iterator.Next())
+	assert.NoError(t, iterator.Close())
+}
+
+func Test_AdvisoryLock(t *testing.T) {
+	first := NewAdvisoryLock(testDB, RetentionLockKey)
+	second := NewAdvisoryLock(testDB, RetentionLockKey)
+
+	acquired, err := first.TryLock(ctx)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = second.TryLock(ctx)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	assert.NoError(t, first.Unlock(ctx))
+	acquired, err = second.TryLock(ctx)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.NoError(t, second.Unlock(ctx))
+}
@@ -1,9 +1,10 @@
+//go:build postgres
+
 package postgres
 
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"fmt"
 	"testing"
 
@@ -52,9 +53,6 @@ func setupTestContainer(t *testing.T) (testcontainers.Container, string) {
 	return container, dsn
 }
 
-//go:embed dataflow_schema.sql
-var schema string
-
 func SetupDatabase(t *testing.T, ctx context.Context) (*sql.DB, testcontainers.Container) {
 
 	// Start a Postgres testcontainer once for all tests
@@ -63,9 +61,17 @@ func SetupDatabase(t *testing.T, ctx context.Context) (*sql.DB, testcontainers.C
 	if err != nil {
 		t.Fatal(err)
 	}
-	// Apply schema (expects the SQL file to be available in repo)
-	if _, err := db.ExecContext(ctx, schema); err != nil {
+	if err := migrate(ctx, db); err != nil {
 		panic(err)
 	}
 	return db, container
 }
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(ctx)
+	return err
+}
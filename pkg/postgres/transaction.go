@@ -13,6 +13,8 @@ type dbTransactionKeyType struct{}
 // DBTransactionKey defines the key for obtaining the transaction from the context.
 var DBTransactionKey = dbTransactionKeyType{}
 
+// DBTransactionContext executes operations in a Postgres transaction that is propagated to the store through the
+// context.
 type DBTransactionContext struct {
 	db *sql.DB
 }
@@ -21,7 +23,18 @@ func NewDBTransactionContext(db *sql.DB) *DBTransactionContext {
 	return &DBTransactionContext{db: db}
 }
 
+// Ping verifies the connection to the database
+func (trxContext *DBTransactionContext) Ping(ctx context.Context) error {
+	return trxContext.db.PingContext(ctx)
+}
+
+// Execute runs the operation in a new transaction, committing it if the operation succeeds and rolling it back
+// otherwise. If the context already carries a transaction, the operation joins it.
 func (trxContext *DBTransactionContext) Execute(ctx context.Context, operation func(context.Context) error) error {
+	if _, ok := ctx.Value(DBTransactionKey).(*sql.Tx); ok {
+		return operation(ctx)
+	}
+
 	// begin transaction
 	tx, err := trxContext.db.BeginTx(ctx, nil)
 	if err != nil {
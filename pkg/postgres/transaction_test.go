@@ -105,4 +105,14 @@ func TestDBTransactionContext(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, initialCount, count)
 	})
+	t.Run("Nested execute joins the transaction", func(t *testing.T) {
+		err := trxContext.Execute(ctx, func(outer context.Context) error {
+			return trxContext.Execute(outer, func(inner context.Context) error {
+				assert.Same(t, outer.Value(DBTransactionKey), inner.Value(DBTransactionKey))
+				return nil
+			})
+		})
+
+		assert.NoError(t, err)
+	})
 }
@@ -0,0 +1,226 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+// Package signaling provides a client for the signaling API of data planes, as used by control planes and tools.
+package signaling
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// StatusError is returned when a data plane responds with a non-2xx status. Problem is nil if the response did not
+// contain a problem body.
+type StatusError struct {
+	StatusCode int
+	Problem    *dsdk.ProblemDetails
+}
+
+func (e *StatusError) Error() string {
+	if e.Problem == nil {
+		return fmt.Sprintf("unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Problem.Detail)
+}
+
+// Client sends signaling messages to the data plane at a base URL, e.g. http://localhost:8080.
+type Client struct {
+	baseURL string
+	client  *http.Client
+	header  http.Header
+}
+
+// ClientOption configures a Client instance
+type ClientOption func(*Client)
+
+// WithHTTPClient replaces the HTTP client, which has a default timeout.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithHeader adds a header sent with every request, e.g. for authorization.
+func WithHeader(key string, value string) ClientOption {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// NewClient creates a client for the data plane at the base URL.
+func NewClient(baseURL string, options ...ClientOption) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: defaultTimeout},
+		header:  make(http.Header),
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Prepare sends a prepare message to a consumer data plane.
+func (c *Client) Prepare(ctx context.Context, message dsdk.DataFlowPrepareMessage) (*dsdk.DataFlowResponseMessage, error) {
+	var response dsdk.DataFlowResponseMessage
+	if err := c.send(ctx, http.MethodPost, "/dataflows/prepare", message, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Start sends a start message to a data plane.
+func (c *Client) Start(ctx context.Context, message dsdk.DataFlowStartMessage) (*dsdk.DataFlowResponseMessage, error) {
+	var response dsdk.DataFlowResponseMessage
+	if err := c.send(ctx, http.MethodPost, "/dataflows/start", message, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Started notifies a consumer data plane that the provider has started the transfer.
+func (c *Client) Started(ctx context.Context, processID string, message dsdk.DataFlowStartedNotificationMessage) (*dsdk.DataFlowResponseMessage, error) {
+	var response dsdk.DataFlowResponseMessage
+	if err := c.send(ctx, http.MethodPost, flowPath(processID, "started"), message, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Suspend sends a suspend message for a data flow.
+func (c *Client) Suspend(ctx context.Context, processID string, message dsdk.DataFlowTransitionMessage) error {
+	return c.send(ctx, http.MethodPost, flowPath(processID, "suspend"), message, nil)
+}
+
+// Resume sends a resume message for a suspended data flow.
+func (c *Client) Resume(ctx context.Context, processID string, message dsdk.DataFlowTransitionMessage) error {
+	return c.send(ctx, http.MethodPost, flowPath(processID, "resume"), message, nil)
+}
+
+// Terminate sends a terminate message for a data flow.
+func (c *Client) Terminate(ctx context.Context, processID string, message dsdk.DataFlowTransitionMessage) error {
+	return c.send(ctx, http.MethodPost, flowPath(processID, "terminate"), message, nil)
+}
+
+// Complete signals that the transfer of a data flow has completed.
+func (c *Client) Complete(ctx context.Context, processID string) error {
+	return c.send(ctx, http.MethodPost, flowPath(processID, "completed"), nil, nil)
+}
+
+// Status returns the extended status of a data flow. Data addresses are redacted by the data plane.
+func (c *Client) Status(ctx context.Context, processID string) (*dsdk.DataFlowExtendedStatusResponseMessage, error) {
+	var response dsdk.DataFlowExtendedStatusResponseMessage
+	path := flowPath(processID, "status") + "?" + dsdk.StatusViewParam + "=" + dsdk.ExtendedStatusView
+	if err := c.send(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// History returns the transition history of a data flow.
+func (c *Client) History(ctx context.Context, processID string) (*dsdk.DataFlowHistoryResponseMessage, error) {
+	var response dsdk.DataFlowHistoryResponseMessage
+	if err := c.send(ctx, http.MethodGet, flowPath(processID, "history"), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// List returns the status of the data flows matching the states and limit of the query.
+func (c *Client) List(ctx context.Context, query dsdk.FlowQuery) ([]dsdk.DataFlowStatusResponseMessage, error) {
+	params := url.Values{}
+	for _, state := range query.States {
+		params.Add(dsdk.StateParam, state.String())
+	}
+	if query.Limit > 0 {
+		params.Set(dsdk.LimitParam, strconv.Itoa(query.Limit))
+	}
+	path := "/dataflows"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var response dsdk.DataFlowListResponseMessage
+	if err := c.send(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.DataFlows, nil
+}
+
+// Bulk applies a suspend, resume or terminate action to the data flows matching the selector of the message and returns
+// the outcome for each flow.
+func (c *Client) Bulk(ctx context.Context, message dsdk.DataFlowBulkMessage) ([]dsdk.BulkResult, error) {
+	var response dsdk.DataFlowBulkResponseMessage
+	if err := c.send(ctx, http.MethodPost, "/dataflows/bulk", message, &response); err != nil {
+		return nil, err
+	}
+	return response.DataFlows, nil
+}
+
+func flowPath(processID string, action string) string {
+	return "/dataflows/" + url.PathEscape(processID) + "/" + action
+}
+
+// send issues a request and decodes the response into result, if given. Non-2xx responses are returned as a
+// StatusError.
+func (c *Client) send(ctx context.Context, method string, path string, message any, result any) error {
+	var body bytes.Buffer
+	if message != nil {
+		if err := json.NewEncoder(&body).Encode(message); err != nil {
+			return fmt.Errorf("encoding message: %w", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, &body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	if message != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request to %s: %w", req.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		if resp.Header.Get("Content-Type") == dsdk.ProblemContentType {
+			var problem dsdk.ProblemDetails
+			if json.NewDecoder(resp.Body).Decode(&problem) == nil {
+				statusErr.Problem = &problem
+			}
+		}
+		return statusErr
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", req.URL, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package signaling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Suspend(t *testing.T) {
+	var received *http.Request
+	var message dsdk.DataFlowTransitionMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL+"/", WithHeader("Authorization", "Bearer token"))
+
+	err := client.Suspend(context.Background(), "process/1", dsdk.DataFlowTransitionMessage{MessageID: "m1", Reason: "maintenance"})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "/dataflows/process%2F1/suspend", received.URL.EscapedPath())
+	assert.Equal(t, "Bearer token", received.Header.Get("Authorization"))
+	assert.Equal(t, "maintenance", message.Reason)
+}
+
+func TestClient_List(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(dsdk.DataFlowListResponseMessage{DataFlows: []dsdk.DataFlowStatusResponseMessage{
+			{DataFlowID: "flow1", State: dsdk.Started},
+		}})
+	}))
+	defer server.Close()
+
+	flows, err := NewClient(server.URL).List(context.Background(), dsdk.FlowQuery{
+		States: []dsdk.DataFlowState{dsdk.Started, dsdk.Suspended},
+		Limit:  5,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "limit=5&state=STARTED&state=SUSPENDED", query)
+	require.Len(t, flows, 1)
+	assert.Equal(t, "flow1", flows[0].DataFlowID)
+}
+
+func TestClient_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", dsdk.ProblemContentType)
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(dsdk.ProblemDetails{Type: dsdk.ProblemTypeConflict, Status: http.StatusConflict, Detail: "conflict"})
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).Status(context.Background(), "flow1")
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusConflict, statusErr.StatusCode)
+	require.NotNil(t, statusErr.Problem)
+	assert.Equal(t, dsdk.ProblemTypeConflict, statusErr.Problem.Type)
+	assert.EqualError(t, err, "unexpected status 409: conflict")
+}
+
+func TestClient_StatusError_WithoutProblem(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := NewClient(server.URL).Complete(context.Background(), "flow1")
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Nil(t, statusErr.Problem)
+}
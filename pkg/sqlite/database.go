@@ -0,0 +1,113 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+const memoryPath = ":memory:"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Open opens the SQLite database at the path and migrates it to the current schema. The connection is configured with
+// WAL journaling, enforced foreign keys, a busy timeout, and transactions that acquire the write lock on begin, so
+// concurrent writers wait instead of failing. Pass ":memory:" for a database that only lives as long as the returned
+// handle.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_txlock", "immediate")
+	if path != memoryPath {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if path == memoryPath {
+		// every connection to :memory: creates a separate database
+		db.SetMaxOpenConns(1)
+	}
+	if err := Migrate(ctx, db); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+	return db, nil
+}
+
+// Migrate applies the embedded schema migrations that have not been applied yet. The schema version is tracked in the
+// user_version pragma of the database.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		content, err := fs.ReadFile(migrationFiles, "migrations/"+entry.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		if err := migrate(ctx, db, version, string(content)); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// migrate applies a single migration unless the database is already at or beyond its version.
+func migrate(ctx context.Context, db *sql.DB, version int, statements string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var current int
+	if err := tx.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&current); err != nil {
+		return err
+	}
+	if current >= version {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return err
+	}
+	// PRAGMA does not accept bind parameters
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,67 @@
+-- DataFlow persistence (SQLite)
+
+CREATE TABLE IF NOT EXISTS data_flows
+(
+    id                     TEXT PRIMARY KEY NOT NULL,           -- maps to DataFlow.ID
+    version                INTEGER          NOT NULL DEFAULT 0, -- DataFlow.Version (optimistic locking)
+    consumer               INTEGER          NOT NULL,           -- DataFlow.Consumer
+    agreement_id           TEXT             NOT NULL,           -- DataFlow.AgreementID
+    dataset_id             TEXT             NOT NULL,           -- DataFlow.DatasetID
+    runtime_id             TEXT             NOT NULL,           -- DataFlow.RuntimeID
+    participant_id         TEXT             NOT NULL,           -- DataFlow.ParticipantID
+    dataspace_context      TEXT             NOT NULL,           -- DataFlow.DataspaceContext
+    counterparty_id        TEXT             NOT NULL,           -- DataFlow.CounterPartyID
+
+    callback_address       TEXT             NOT NULL,           -- DataFlow.CallbackAddress (URL as JSON string)
+
+    transfer_type_dest     TEXT             NOT NULL,           -- DataFlow.TransferType {destinationType}
+    transfer_type_flowtype TEXT             NOT NULL,           -- DataFlow.TransferType {flowType}
+    source_data_address    TEXT             NOT NULL,           -- DataFlow.SourceDataAddress (JSON)
+    dest_data_address      TEXT             NOT NULL,           -- DataFlow.DestinationDataAddress (JSON)
+
+    state                  INTEGER          NOT NULL DEFAULT 0, -- DataFlow.State (enum int)
+    state_count            INTEGER          NOT NULL DEFAULT 0, -- DataFlow.StateCount
+    state_timestamp_ms     INTEGER          NOT NULL,           -- DataFlow.StateTimestamp (epoch millis)
+
+    error_detail           TEXT             NOT NULL DEFAULT '', -- DataFlow.ErrorDetail
+
+    created_at_ms          INTEGER          NOT NULL,           -- DataFlow.CreatedAt (epoch millis)
+    updated_at_ms          INTEGER          NOT NULL,           -- DataFlow.UpdatedAt (epoch millis)
+
+    bytes_transferred      INTEGER          NOT NULL DEFAULT 0, -- DataFlow.Progress.BytesTransferred
+    messages_transferred   INTEGER          NOT NULL DEFAULT 0, -- DataFlow.Progress.MessagesTransferred
+    expected_total         INTEGER          NOT NULL DEFAULT 0, -- DataFlow.Progress.ExpectedTotal
+    last_activity_ms       INTEGER          NOT NULL DEFAULT 0  -- DataFlow.Progress.LastActivity (epoch millis)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_flows_state ON data_flows (state, state_timestamp_ms);
+CREATE INDEX IF NOT EXISTS idx_data_flows_agreement ON data_flows (agreement_id);
+CREATE INDEX IF NOT EXISTS idx_data_flows_participant ON data_flows (participant_id);
+
+-- Append-only transition history of data flows, removed with the flow
+CREATE TABLE IF NOT EXISTS data_flow_transitions
+(
+    seq          INTEGER PRIMARY KEY AUTOINCREMENT,                           -- insertion order
+    flow_id      TEXT    NOT NULL REFERENCES data_flows (id) ON DELETE CASCADE, -- TransitionRecord.FlowID
+    from_state   INTEGER NOT NULL,                                            -- TransitionRecord.From
+    to_state     INTEGER NOT NULL,                                            -- TransitionRecord.To
+    reason       TEXT    NOT NULL DEFAULT '',                                 -- TransitionRecord.Reason
+    message_id   TEXT    NOT NULL DEFAULT '',                                 -- TransitionRecord.MessageID
+    caller       TEXT    NOT NULL DEFAULT '',                                 -- TransitionRecord.Caller
+    timestamp_ms INTEGER NOT NULL                                             -- TransitionRecord.Timestamp (epoch millis)
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_flow_transitions_flow ON data_flow_transitions (flow_id, seq);
+
+-- Ledger of processed signaling messages used for MessageID based idempotency
+CREATE TABLE IF NOT EXISTS processed_messages
+(
+    message_id    TEXT PRIMARY KEY NOT NULL, -- ProcessedMessage.MessageID
+    process_id    TEXT             NOT NULL, -- ProcessedMessage.ProcessID
+    message_type  TEXT             NOT NULL, -- ProcessedMessage.MessageType
+    payload_hash  TEXT             NOT NULL, -- ProcessedMessage.PayloadHash (hex encoded SHA-256)
+    response      TEXT,                      -- ProcessedMessage.Response (JSON)
+    created_at_ms INTEGER          NOT NULL  -- ProcessedMessage.CreatedAt (epoch millis)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_messages_process ON processed_messages (process_id);
@@ -0,0 +1,2 @@
+-- Counting the active flows of a counterparty for admission control
+CREATE INDEX IF NOT EXISTS idx_data_flows_counterparty ON data_flows (counterparty_id, state);
@@ -0,0 +1,574 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const dataFlowColumns = `id, version, consumer, agreement_id, dataset_id, runtime_id, participant_id, dataspace_context,
+	counterparty_id, callback_address, transfer_type_dest, transfer_type_flowtype, source_data_address, dest_data_address,
+	state, state_count, state_timestamp_ms, error_detail, created_at_ms, updated_at_ms, bytes_transferred,
+	messages_transferred, expected_total, last_activity_ms`
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Store is a DataplaneStore backed by SQLite. It also implements the ProgressStore, MessageLedger and HistoryStore
+// extensions. Operations use the transaction bound to the context by DBTransactionContext.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore creates a store for a database opened with Open.
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Ping verifies the connection to the database, using the transaction bound to the context if there is one.
+func (s *Store) Ping(ctx context.Context) error {
+	return ping(ctx, s.db)
+}
+
+// conn returns the transaction bound to the context by DBTransactionContext, or the database if there is none. Every
+// statement must go through conn: a ":memory:" database has a single connection, which is held by an open transaction,
+// so a statement issued on the database while the context carries a transaction would never get a connection.
+func (s *Store) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(DBTransactionKey).(*sql.Tx); ok {
+		return tx
+	}
+	return s.db
+}
+
+func (s *Store) FindById(ctx context.Context, id string) (*dsdk.DataFlow, error) {
+	query := `SELECT ` + dataFlowColumns + ` FROM data_flows WHERE id = ?`
+
+	df, err := scanDataFlow(s.conn(ctx).QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dsdk.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return df, nil
+}
+
+// Query returns the data flows matching the query ordered by state timestamp.
+func (s *Store) Query(ctx context.Context, query dsdk.FlowQuery) (dsdk.Iterator[*dsdk.DataFlow], error) {
+	where, args := whereClause(query)
+	statement := `SELECT ` + dataFlowColumns + ` FROM data_flows` + where
+	statement += ` ORDER BY state_timestamp_ms, id`
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		statement += ` LIMIT ?`
+	}
+
+	rows, err := s.conn(ctx).QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &rowIterator{rows: rows}, nil
+}
+
+// CountFlows returns the number of data flows matching the query, ignoring its limit.
+func (s *Store) CountFlows(ctx context.Context, query dsdk.FlowQuery) (int, error) {
+	where, args := whereClause(query)
+	var count int
+	if err := s.conn(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM data_flows`+where, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// whereClause returns the WHERE clause selecting the data flows matching the query and its arguments
+func whereClause(query dsdk.FlowQuery) (string, []any) {
+	var conditions []string
+	var args []any
+	if len(query.States) > 0 {
+		placeholders := make([]string, 0, len(query.States))
+		for _, state := range query.States {
+			placeholders = append(placeholders, "?")
+			args = append(args, int(state))
+		}
+		conditions = append(conditions, "state IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if query.StateTimestampBefore > 0 {
+		args = append(args, query.StateTimestampBefore)
+		conditions = append(conditions, "state_timestamp_ms < ?")
+	}
+	if query.RuntimeID != "" {
+		args = append(args, query.RuntimeID)
+		conditions = append(conditions, "runtime_id = ?")
+	}
+	if query.ParticipantID != "" {
+		args = append(args, query.ParticipantID)
+		conditions = append(conditions, "participant_id = ?")
+	}
+	if query.CounterPartyID != "" {
+		args = append(args, query.CounterPartyID)
+		conditions = append(conditions, "counterparty_id = ?")
+	}
+	if query.AgreementID != "" {
+		args = append(args, query.AgreementID)
+		conditions = append(conditions, "agreement_id = ?")
+	}
+	if query.DatasetID != "" {
+		args = append(args, query.DatasetID)
+		conditions = append(conditions, "dataset_id = ?")
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return ` WHERE ` + strings.Join(conditions, " AND "), args
+}
+
+// rowIterator iterates over data flow query results
+type rowIterator struct {
+	rows    *sql.Rows
+	current *dsdk.DataFlow
+	err     error
+}
+
+func (it *rowIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+	it.current, it.err = scanDataFlow(it.rows)
+	return it.err == nil
+}
+
+func (it *rowIterator) Get() *dsdk.DataFlow {
+	return it.current
+}
+
+func (it *rowIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.rows.Err()
+}
+
+func (it *rowIterator) Close() error {
+	return it.rows.Close()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanDataFlow reads a data flow selected with dataFlowColumns
+func scanDataFlow(row scanner) (*dsdk.DataFlow, error) {
+	var df dsdk.DataFlow
+	var callbackAddressJson, sourceDataAddressJson, destDataAddressJson string
+
+	err := row.Scan(
+		&df.ID,
+		&df.Version,
+		&df.Consumer,
+		&df.AgreementID,
+		&df.DatasetID,
+		&df.RuntimeID,
+		&df.ParticipantID,
+		&df.DataspaceContext,
+		&df.CounterPartyID,
+		&callbackAddressJson,
+		&df.TransferType.DestinationType,
+		&df.TransferType.FlowType,
+		&sourceDataAddressJson,
+		&destDataAddressJson,
+		&df.State,
+		&df.StateCount,
+		&df.StateTimestamp,
+		&df.ErrorDetail,
+		&df.CreatedAt,
+		&df.UpdatedAt,
+		&df.Progress.BytesTransferred,
+		&df.Progress.MessagesTransferred,
+		&df.Progress.ExpectedTotal,
+		&df.Progress.LastActivity,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := df.CallbackAddress.UnmarshalJSON([]byte(callbackAddressJson)); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(sourceDataAddressJson), &df.SourceDataAddress); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(destDataAddressJson), &df.DestinationDataAddress); err != nil {
+		return nil, err
+	}
+	return &df, nil
+}
+
+func (s *Store) Create(ctx context.Context, flow *dsdk.DataFlow) error {
+	if flow == nil || flow.ID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	query := `
+		INSERT INTO data_flows (
+			id,
+			version,
+			consumer,
+			agreement_id,
+			dataset_id,
+			runtime_id,
+			participant_id,
+			dataspace_context,
+			counterparty_id,
+			callback_address,
+			transfer_type_dest,
+			transfer_type_flowtype,
+			source_data_address,
+			dest_data_address,
+			state,
+			state_count,
+			state_timestamp_ms,
+			error_detail,
+			created_at_ms,
+			updated_at_ms,
+			bytes_transferred,
+			messages_transferred,
+			expected_total,
+			last_activity_ms
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	callbackAddress, sourceDataAddress, destDataAddress, err := marshalAddresses(flow)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	_, err = s.conn(ctx).ExecContext(ctx, query,
+		flow.ID,
+		flow.Version,
+		flow.Consumer,
+		flow.AgreementID,
+		flow.DatasetID,
+		flow.RuntimeID,
+		flow.ParticipantID,
+		flow.DataspaceContext,
+		flow.CounterPartyID,
+		callbackAddress,
+		flow.TransferType.DestinationType,
+		flow.TransferType.FlowType,
+		sourceDataAddress,
+		destDataAddress,
+		flow.State,
+		flow.StateCount,
+		now,
+		flow.ErrorDetail,
+		now,
+		now,
+		flow.Progress.BytesTransferred,
+		flow.Progress.MessagesTransferred,
+		flow.Progress.ExpectedTotal,
+		flow.Progress.LastActivity,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return dsdk.ErrConflict
+		}
+		return err
+	}
+	return s.appendHistory(ctx, flow)
+}
+
+// Save updates the data flow or creates it if it does not exist. An update only succeeds if the Version of the flow
+// matches the stored one and increments it; otherwise ErrConflict is returned.
+func (s *Store) Save(ctx context.Context, flow *dsdk.DataFlow) error {
+	if flow == nil || flow.ID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	query := `
+		UPDATE data_flows
+		SET
+			consumer = ?,
+			agreement_id = ?,
+			dataset_id = ?,
+			runtime_id = ?,
+			participant_id = ?,
+			dataspace_context = ?,
+			counterparty_id = ?,
+			callback_address = ?,
+			transfer_type_dest = ?,
+			transfer_type_flowtype = ?,
+			source_data_address = ?,
+			dest_data_address = ?,
+			state = ?,
+			state_count = ?,
+			state_timestamp_ms = ?,
+			error_detail = ?,
+			updated_at_ms = ?,
+			expected_total = COALESCE(NULLIF(?, 0), expected_total),
+			version = version + 1
+		WHERE id = ? AND version = ?`
+
+	callbackAddress, sourceDataAddress, destDataAddress, err := marshalAddresses(flow)
+	if err != nil {
+		return err
+	}
+	res, err := s.conn(ctx).ExecContext(ctx, query,
+		flow.Consumer,
+		flow.AgreementID,
+		flow.DatasetID,
+		flow.RuntimeID,
+		flow.ParticipantID,
+		flow.DataspaceContext,
+		flow.CounterPartyID,
+		callbackAddress,
+		flow.TransferType.DestinationType,
+		flow.TransferType.FlowType,
+		sourceDataAddress,
+		destDataAddress,
+		flow.State,
+		flow.StateCount,
+		flow.StateTimestamp,
+		flow.ErrorDetail,
+		time.Now().UnixMilli(),
+		flow.Progress.ExpectedTotal,
+		flow.ID,
+		flow.Version)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		var count int
+		if err := s.conn(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM data_flows WHERE id = ?`, flow.ID).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: data flow %s was modified concurrently", dsdk.ErrConflict, flow.ID)
+		}
+		return s.Create(ctx, flow)
+	}
+	flow.Version++
+	return s.appendHistory(ctx, flow)
+}
+
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return dsdk.ErrInvalidInput
+	}
+	res, err := s.conn(ctx).ExecContext(ctx, `DELETE FROM data_flows WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return dsdk.ErrNotFound
+	}
+	_, err = s.conn(ctx).ExecContext(ctx, `DELETE FROM processed_messages WHERE process_id = ?`, id)
+	return err
+}
+
+// UpdateProgress increments the progress counters of a data flow in a single statement.
+func (s *Store) UpdateProgress(ctx context.Context, id string, update dsdk.ProgressUpdate, timestamp int64) error {
+	if id == "" {
+		return dsdk.ErrInvalidInput
+	}
+	query := `
+		UPDATE data_flows
+		SET
+			bytes_transferred = bytes_transferred + ?,
+			messages_transferred = messages_transferred + ?,
+			expected_total = CASE WHEN ? > 0 THEN ? ELSE expected_total END,
+			last_activity_ms = ?
+		WHERE id = ?`
+	res, err := s.conn(ctx).ExecContext(ctx, query,
+		update.Bytes, update.Messages, update.ExpectedTotal, update.ExpectedTotal, timestamp, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return dsdk.ErrNotFound
+	}
+	return nil
+}
+
+// History returns the transition history of a data flow in chronological order.
+func (s *Store) History(ctx context.Context, id string) ([]dsdk.TransitionRecord, error) {
+	var count int
+	if err := s.conn(ctx).QueryRowContext(ctx, `SELECT COUNT(*) FROM data_flows WHERE id = ?`, id).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, dsdk.ErrNotFound
+	}
+	query := `SELECT flow_id, from_state, to_state, reason, message_id, caller, timestamp_ms
+		FROM data_flow_transitions WHERE flow_id = ? ORDER BY seq`
+
+	rows, err := s.conn(ctx).QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []dsdk.TransitionRecord{}
+	for rows.Next() {
+		var record dsdk.TransitionRecord
+		if err := rows.Scan(
+			&record.FlowID,
+			&record.From,
+			&record.To,
+			&record.Reason,
+			&record.MessageID,
+			&record.Caller,
+			&record.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, record)
+	}
+	return history, rows.Err()
+}
+
+// appendHistory inserts the pending transitions of the flow. Callers must use the transaction of the flow update so
+// both are written atomically.
+func (s *Store) appendHistory(ctx context.Context, flow *dsdk.DataFlow) error {
+	query := `INSERT INTO data_flow_transitions (flow_id, from_state, to_state, reason, message_id, caller, timestamp_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	for _, record := range flow.TakeTransitions() {
+		_, err := s.conn(ctx).ExecContext(ctx, query,
+			flow.ID,
+			record.From,
+			record.To,
+			record.Reason,
+			record.MessageID,
+			record.Caller,
+			record.Timestamp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) FindMessage(ctx context.Context, messageID string) (*dsdk.ProcessedMessage, error) {
+	query := `SELECT message_id, process_id, message_type, payload_hash, response, failure, created_at_ms
+		FROM processed_messages WHERE message_id = ?`
+
+	var message dsdk.ProcessedMessage
+	var response, failure *string
+	err := s.conn(ctx).QueryRowContext(ctx, query, messageID).Scan(
+		&message.MessageID,
+		&message.ProcessID,
+		&message.MessageType,
+		&message.PayloadHash,
+		&response,
+		&failure,
+		&message.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dsdk.ErrNotFound
+		}
+		return nil, err
+	}
+	if response != nil {
+		message.Response = []byte(*response)
+	}
+	if failure != nil {
+		message.Failure = []byte(*failure)
+	}
+	return &message, nil
+}
+
+func (s *Store) SaveMessage(ctx context.Context, message *dsdk.ProcessedMessage) error {
+	if message == nil || message.MessageID == "" {
+		return dsdk.ErrInvalidInput
+	}
+	query := `INSERT INTO processed_messages (message_id, process_id, message_type, payload_hash, response, failure,
+		created_at_ms) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	var response, failure *string
+	if message.Response != nil {
+		r := string(message.Response)
+		response = &r
+	}
+	if message.Failure != nil {
+		f := string(message.Failure)
+		failure = &f
+	}
+	_, err := s.conn(ctx).ExecContext(ctx, query,
+		message.MessageID,
+		message.ProcessID,
+		message.MessageType,
+		message.PayloadHash,
+		response,
+		failure,
+		message.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return dsdk.ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// marshalAddresses encodes the callback, source and destination addresses of the flow as JSON.
+func marshalAddresses(flow *dsdk.DataFlow) (string, string, string, error) {
+	callbackAddress, err := flow.CallbackAddress.MarshalJSON()
+	if err != nil {
+		return "", "", "", err
+	}
+	sourceDataAddress, err := json.Marshal(flow.SourceDataAddress)
+	if err != nil {
+		return "", "", "", err
+	}
+	destDataAddress, err := json.Marshal(flow.DestinationDataAddress)
+	if err != nil {
+		return "", "", "", err
+	}
+	return string(callbackAddress), string(sourceDataAddress), string(destDataAddress), nil
+}
+
+// isUniqueViolation detects primary key and unique constraint violations.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY || code == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+	return false
+}
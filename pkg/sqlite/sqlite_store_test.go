@@ -0,0 +1,292 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/metaform/dataplane-sdk-go/pkg/storetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, *sql.DB) {
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "dataplane.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return NewStore(db), db
+}
+
+func newTestFlow(id string) *dsdk.DataFlow {
+	callbackURL, _ := url.Parse("http://example.com/callback")
+	return &dsdk.DataFlow{
+		ID:               id,
+		Consumer:         true,
+		AgreementID:      "agreement-1",
+		DatasetID:        "dataset-1",
+		RuntimeID:        "runtime-1",
+		ParticipantID:    "participant-1",
+		DataspaceContext: "context-1",
+		CounterPartyID:   "counterparty-1",
+		CallbackAddress:  dsdk.CallbackURL(*callbackURL),
+		TransferType:     dsdk.TransferType{DestinationType: "HttpData", FlowType: dsdk.Push},
+		SourceDataAddress: dsdk.DataAddress{Properties: map[string]any{
+			"endpoint": "http://example.com/source",
+		}},
+		DestinationDataAddress: dsdk.DataAddress{Properties: map[string]any{}},
+		State:                  dsdk.Preparing,
+		StateCount:             1,
+	}
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) (dsdk.DataplaneStore, dsdk.TransactionContext) {
+		store, db := newTestStore(t)
+		return store, NewDBTransactionContext(db)
+	})
+}
+
+func TestStore_CreateAndFind(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	flow := newTestFlow("flow-1")
+
+	require.NoError(t, store.Create(ctx, flow))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, flow.ID, found.ID)
+	assert.True(t, found.Consumer)
+	assert.Equal(t, flow.AgreementID, found.AgreementID)
+	assert.Equal(t, flow.RuntimeID, found.RuntimeID)
+	assert.Equal(t, flow.CallbackAddress.URL().String(), found.CallbackAddress.URL().String())
+	assert.Equal(t, flow.TransferType, found.TransferType)
+	assert.Equal(t, "http://example.com/source", found.SourceDataAddress.Properties["endpoint"])
+	assert.Equal(t, dsdk.Preparing, found.State)
+	assert.Equal(t, uint(1), found.StateCount)
+	assert.NotZero(t, found.StateTimestamp)
+	assert.NotZero(t, found.CreatedAt)
+}
+
+func TestStore_Create_Conflict(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+
+	err := store.Create(ctx, newTestFlow("flow-1"))
+
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+}
+
+func TestStore_Create_InvalidInput(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	assert.ErrorIs(t, store.Create(context.Background(), &dsdk.DataFlow{}), dsdk.ErrInvalidInput)
+	assert.ErrorIs(t, store.Create(context.Background(), nil), dsdk.ErrInvalidInput)
+}
+
+func TestStore_FindById_NotFound(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	_, err := store.FindById(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestStore_Save(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	flow := newTestFlow("flow-1")
+	require.NoError(t, store.Create(ctx, flow))
+
+	flow.State = dsdk.Terminated
+	flow.StateTimestamp = 42
+	flow.ErrorDetail = "failed"
+	require.NoError(t, store.Save(ctx, flow))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Terminated, found.State)
+	assert.Equal(t, int64(42), found.StateTimestamp)
+	assert.Equal(t, "failed", found.ErrorDetail)
+}
+
+func TestStore_Save_CreatesMissingFlow(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, newTestFlow("flow-1")))
+
+	_, err := store.FindById(ctx, "flow-1")
+	assert.NoError(t, err)
+}
+
+func TestStore_Delete(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+	require.NoError(t, store.SaveMessage(ctx, &dsdk.ProcessedMessage{MessageID: "message-1", ProcessID: "flow-1"}))
+
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+
+	_, err := store.FindById(ctx, "flow-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	_, err = store.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "flow-1"), dsdk.ErrNotFound)
+}
+
+func TestStore_Query(t *testing.T) {
+	store, db := newTestStore(t)
+	ctx := context.Background()
+	for i, state := range []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated, dsdk.Started} {
+		flow := newTestFlow(string(rune('a' + i)))
+		flow.State = state
+		require.NoError(t, store.Create(ctx, flow))
+		_, err := db.ExecContext(ctx, `UPDATE data_flows SET state_timestamp_ms = ? WHERE id = ?`, 100*(i+1), flow.ID)
+		require.NoError(t, err)
+	}
+
+	iterator, err := store.Query(ctx, dsdk.FlowQuery{
+		States:               []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated, dsdk.Started},
+		StateTimestampBefore: 300,
+	})
+	require.NoError(t, err)
+	var ids []string
+	for iterator.Next() {
+		ids = append(ids, iterator.Get().ID)
+	}
+	require.NoError(t, iterator.Error())
+	require.NoError(t, iterator.Close())
+	assert.Equal(t, []string{"a", "b"}, ids)
+
+	iterator, err = store.Query(ctx, dsdk.FlowQuery{Limit: 1})
+	require.NoError(t, err)
+	assert.True(t, iterator.Next())
+	assert.Equal(t, "a", iterator.Get().ID)
+	assert.False(t, iterator.Next())
+	require.NoError(t, iterator.Close())
+}
+
+func TestStore_UpdateProgress(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+
+	require.NoError(t, store.UpdateProgress(ctx, "flow-1", dsdk.ProgressUpdate{Bytes: 10, Messages: 1, ExpectedTotal: 100}, 5))
+	require.NoError(t, store.UpdateProgress(ctx, "flow-1", dsdk.ProgressUpdate{Bytes: 20, Messages: 2}, 6))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.TransferProgress{BytesTransferred: 30, MessagesTransferred: 3, ExpectedTotal: 100, LastActivity: 6}, found.Progress)
+	assert.ErrorIs(t, store.UpdateProgress(ctx, "unknown", dsdk.ProgressUpdate{Bytes: 1}, 7), dsdk.ErrNotFound)
+}
+
+func TestStore_History(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	flow := newTestFlow("flow-1")
+	require.NoError(t, store.Create(ctx, flow))
+
+	flow.SetTransitionSource("message-1", "caller-1")
+	require.NoError(t, flow.TransitionToPrepared())
+	require.NoError(t, store.Save(ctx, flow))
+
+	history, err := store.History(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, dsdk.Preparing, history[0].From)
+	assert.Equal(t, dsdk.Prepared, history[0].To)
+	assert.Equal(t, "message-1", history[0].MessageID)
+	assert.Equal(t, "caller-1", history[0].Caller)
+
+	_, err = store.History(ctx, "unknown")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestStore_MessageLedger(t *testing.T) {
+	store, _ := newTestStore(t)
+	ctx := context.Background()
+	message := &dsdk.ProcessedMessage{
+		MessageID:   "message-1",
+		ProcessID:   "flow-1",
+		MessageType: dsdk.StartMessageType,
+		PayloadHash: "hash",
+		Response:    []byte(`{"state":2}`),
+		CreatedAt:   42,
+	}
+
+	require.NoError(t, store.SaveMessage(ctx, message))
+	assert.ErrorIs(t, store.SaveMessage(ctx, message), dsdk.ErrConflict)
+
+	found, err := store.FindMessage(ctx, "message-1")
+	require.NoError(t, err)
+	assert.Equal(t, message, found)
+
+	_, err = store.FindMessage(ctx, "unknown")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func TestStore_Durability(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dataplane.db")
+	ctx := context.Background()
+
+	db, err := Open(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, NewStore(db).Create(ctx, newTestFlow("flow-1")))
+	require.NoError(t, db.Close())
+
+	db, err = Open(ctx, path)
+	require.NoError(t, err)
+	defer db.Close()
+	found, err := NewStore(db).FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, "flow-1", found.ID)
+}
+
+func TestOpen_Memory(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewStore(db)
+
+	require.NoError(t, store.Create(ctx, newTestFlow("flow-1")))
+	_, err = store.FindById(ctx, "flow-1")
+	assert.NoError(t, err)
+}
+
+func TestMigrate_Idempotent(t *testing.T) {
+	_, db := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, Migrate(ctx, db))
+
+	var version int
+	require.NoError(t, db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version))
+	assert.Equal(t, 4, version)
+}
+
+func TestStore_Ping(t *testing.T) {
+	store, db := newTestStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Ping(ctx))
+	require.NoError(t, db.Close())
+	assert.Error(t, store.Ping(ctx))
+}
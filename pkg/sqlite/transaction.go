@@ -0,0 +1,81 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type dbTransactionKeyType struct{}
+
+// DBTransactionKey defines the key for obtaining the transaction from the context.
+var DBTransactionKey = dbTransactionKeyType{}
+
+// DBTransactionContext executes operations in a SQLite transaction that is propagated to the Store through the
+// context.
+type DBTransactionContext struct {
+	db *sql.DB
+}
+
+func NewDBTransactionContext(db *sql.DB) *DBTransactionContext {
+	return &DBTransactionContext{db: db}
+}
+
+// Ping verifies the connection to the database, using the transaction bound to the context if there is one.
+func (trxContext *DBTransactionContext) Ping(ctx context.Context) error {
+	return ping(ctx, trxContext.db)
+}
+
+// ping verifies the connection through the transaction bound to the context, or pings the database if there is none.
+func ping(ctx context.Context, db *sql.DB) error {
+	if tx, ok := ctx.Value(DBTransactionKey).(*sql.Tx); ok {
+		_, err := tx.ExecContext(ctx, `SELECT 1`)
+		return err
+	}
+	return db.PingContext(ctx)
+}
+
+// Execute runs the operation in a new transaction, committing it if the operation succeeds and rolling it back
+// otherwise. If the context already carries a transaction, the operation joins it.
+func (trxContext *DBTransactionContext) Execute(ctx context.Context, operation func(context.Context) error) error {
+	if _, ok := ctx.Value(DBTransactionKey).(*sql.Tx); ok {
+		return operation(ctx)
+	}
+
+	tx, err := trxContext.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// rollback on panic
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p) // re-throw panic
+		}
+	}()
+
+	if err := operation(context.WithValue(ctx, DBTransactionKey, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("operation failed: %v, rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
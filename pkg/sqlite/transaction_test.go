@@ -0,0 +1,136 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBTransactionContext(t *testing.T) {
+	store, db := newTestStore(t)
+	trxContext := NewDBTransactionContext(db)
+	ctx := context.Background()
+
+	t.Run("commit", func(t *testing.T) {
+		err := trxContext.Execute(ctx, func(ctx context.Context) error {
+			_, ok := ctx.Value(DBTransactionKey).(*sql.Tx)
+			assert.True(t, ok)
+			return store.Create(ctx, newTestFlow("committed"))
+		})
+
+		require.NoError(t, err)
+		_, err = store.FindById(ctx, "committed")
+		assert.NoError(t, err)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		err := trxContext.Execute(ctx, func(ctx context.Context) error {
+			if err := store.Create(ctx, newTestFlow("rolled-back")); err != nil {
+				return err
+			}
+			return errors.New("forced error")
+		})
+
+		assert.EqualError(t, err, "forced error")
+		_, err = store.FindById(ctx, "rolled-back")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = trxContext.Execute(ctx, func(ctx context.Context) error {
+				_ = store.Create(ctx, newTestFlow("panicked"))
+				panic("forced panic")
+			})
+		})
+
+		_, err := store.FindById(ctx, "panicked")
+		assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	})
+
+	t.Run("nested execute joins the transaction", func(t *testing.T) {
+		err := trxContext.Execute(ctx, func(outer context.Context) error {
+			return trxContext.Execute(outer, func(inner context.Context) error {
+				assert.Same(t, outer.Value(DBTransactionKey), inner.Value(DBTransactionKey))
+				return nil
+			})
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- trxContext.Execute(ctx, func(ctx context.Context) error {
+					return store.Create(ctx, newTestFlow("concurrent-"+string(rune('a'+i))))
+				})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+	})
+}
+
+func TestDBTransactionContext_MemoryDatabase(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	store := NewStore(db)
+	trxContext := NewDBTransactionContext(db)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- trxContext.Execute(ctx, func(ctx context.Context) error {
+			if err := store.Create(ctx, newTestFlow("memory")); err != nil {
+				return err
+			}
+			if err := store.Ping(ctx); err != nil {
+				return err
+			}
+			if err := trxContext.Ping(ctx); err != nil {
+				return err
+			}
+			return trxContext.Execute(ctx, func(ctx context.Context) error {
+				_, err := store.FindById(ctx, "memory")
+				return err
+			})
+		})
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("operation on the single connection of a :memory: database did not complete")
+	}
+	_, err = store.FindById(ctx, "memory")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,622 @@
+//  Copyright (c) 2025 Metaform Systems, Inc
+//
+//  This program and the accompanying materials are made available under the
+//  terms of the Apache License, Version 2.0 which is available at
+//  https://www.apache.org/licenses/LICENSE-2.0
+//
+//  SPDX-License-Identifier: Apache-2.0
+//
+//  Contributors:
+//       Metaform Systems, Inc. - initial API and implementation
+//
+
+// Package storetest provides a conformance test suite for DataplaneStore implementations. Store implementations
+// run it from their own tests:
+//
+//	func TestStore_Conformance(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) (dsdk.DataplaneStore, dsdk.TransactionContext) {
+//			return newEmptyStore(t), newTransactionContext(t)
+//		})
+//	}
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/metaform/dataplane-sdk-go/pkg/dsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty store and the transaction context it participates in. It is invoked once per test case.
+type Factory func(t *testing.T) (dsdk.DataplaneStore, dsdk.TransactionContext)
+
+type config struct {
+	rollback bool
+}
+
+// Option configures the conformance suite
+type Option func(*config)
+
+// SkipRollback skips the test cases that require a failed transaction to discard its changes. Use it for stores
+// without transactional semantics.
+func SkipRollback() Option {
+	return func(c *config) {
+		c.rollback = false
+	}
+}
+
+// Run executes the conformance suite as subtests of t.
+func Run(t *testing.T, factory Factory, options ...Option) {
+	cfg := &config{rollback: true}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	t.Run("CreateAndFind", func(t *testing.T) { testCreateAndFind(t, factory) })
+	t.Run("CreateConflict", func(t *testing.T) { testCreateConflict(t, factory) })
+	t.Run("CreateInvalidInput", func(t *testing.T) { testCreateInvalidInput(t, factory) })
+	t.Run("FindNotFound", func(t *testing.T) { testFindNotFound(t, factory) })
+	t.Run("Save", func(t *testing.T) { testSave(t, factory) })
+	t.Run("Delete", func(t *testing.T) { testDelete(t, factory) })
+	t.Run("ReturnsCopies", func(t *testing.T) { testReturnsCopies(t, factory) })
+	t.Run("RoundTripAddresses", func(t *testing.T) { testRoundTripAddresses(t, factory) })
+	t.Run("ConcurrentCreate", func(t *testing.T) { testConcurrentCreate(t, factory) })
+	t.Run("ConcurrentSave", func(t *testing.T) { testConcurrentSave(t, factory) })
+	t.Run("TransactionCommit", func(t *testing.T) { testTransactionCommit(t, factory) })
+	if cfg.rollback {
+		t.Run("TransactionRollback", func(t *testing.T) { testTransactionRollback(t, factory) })
+	}
+	t.Run("QueryFilters", func(t *testing.T) { testQueryFilters(t, factory) })
+	t.Run("QueryRuntime", func(t *testing.T) { testQueryRuntime(t, factory) })
+	t.Run("QueryParticipant", func(t *testing.T) { testQueryParticipant(t, factory) })
+	t.Run("QueryCounterPartyAndAgreement", func(t *testing.T) { testQueryCounterPartyAndAgreement(t, factory) })
+	t.Run("QueryDataset", func(t *testing.T) { testQueryDataset(t, factory) })
+	t.Run("QueryOrderAndLimit", func(t *testing.T) { testQueryOrderAndLimit(t, factory) })
+	t.Run("QueryEmpty", func(t *testing.T) { testQueryEmpty(t, factory) })
+	t.Run("FlowCounter", func(t *testing.T) { testFlowCounter(t, factory) })
+	t.Run("ProgressStore", func(t *testing.T) { testProgressStore(t, factory) })
+	t.Run("MessageLedger", func(t *testing.T) { testMessageLedger(t, factory) })
+	t.Run("HistoryStore", func(t *testing.T) { testHistoryStore(t, factory) })
+}
+
+// NewDataFlow returns a flow with all persisted fields populated.
+func NewDataFlow(id string) *dsdk.DataFlow {
+	callbackURL, _ := url.Parse("https://controlplane.example.com/callback/v1?tenant=a%20b")
+	return &dsdk.DataFlow{
+		ID:               id,
+		Consumer:         true,
+		AgreementID:      "agreement-" + id,
+		DatasetID:        "dataset-1",
+		RuntimeID:        "runtime-1",
+		ParticipantID:    "participant-1",
+		DataspaceContext: "dataspace-1",
+		CounterPartyID:   "counterparty-1",
+		CallbackAddress:  dsdk.CallbackURL(*callbackURL),
+		TransferType:     dsdk.TransferType{DestinationType: "HttpData", FlowType: dsdk.Push},
+		SourceDataAddress: dsdk.DataAddress{Properties: map[string]any{
+			"endpoint": "https://provider.example.com/data",
+		}},
+		DestinationDataAddress: dsdk.DataAddress{Properties: map[string]any{
+			"endpoint": "https://consumer.example.com/data",
+		}},
+		State:       dsdk.Started,
+		StateCount:  2,
+		ErrorDetail: "detail",
+		Progress: dsdk.TransferProgress{
+			BytesTransferred:    1024,
+			MessagesTransferred: 3,
+			ExpectedTotal:       4096,
+			LastActivity:        1700000000000,
+		},
+	}
+}
+
+func testCreateAndFind(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ctx := context.Background()
+	flow := NewDataFlow("flow-1")
+
+	require.NoError(t, store.Create(ctx, flow))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, flow.ID, found.ID)
+	assert.Equal(t, flow.Consumer, found.Consumer)
+	assert.Equal(t, flow.AgreementID, found.AgreementID)
+	assert.Equal(t, flow.DatasetID, found.DatasetID)
+	assert.Equal(t, flow.RuntimeID, found.RuntimeID)
+	assert.Equal(t, flow.ParticipantID, found.ParticipantID)
+	assert.Equal(t, flow.DataspaceContext, found.DataspaceContext)
+	assert.Equal(t, flow.CounterPartyID, found.CounterPartyID)
+	assert.Equal(t, flow.TransferType, found.TransferType)
+	assert.Equal(t, flow.State, found.State)
+	assert.Equal(t, flow.StateCount, found.StateCount)
+	assert.Equal(t, flow.ErrorDetail, found.ErrorDetail)
+	assert.Equal(t, flow.Progress, found.Progress)
+}
+
+func testCreateConflict(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("flow-1")))
+
+	err := store.Create(ctx, NewDataFlow("flow-1"))
+
+	assert.ErrorIs(t, err, dsdk.ErrConflict)
+}
+
+func testCreateInvalidInput(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+
+	err := store.Create(context.Background(), NewDataFlow(""))
+
+	assert.ErrorIs(t, err, dsdk.ErrInvalidInput)
+}
+
+func testFindNotFound(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+
+	_, err := store.FindById(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func testSave(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("flow-1")))
+
+	flow, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	flow.State = dsdk.Terminated
+	flow.StateCount = 3
+	flow.StateTimestamp = 1700000000000
+	flow.ErrorDetail = "terminated"
+	created := flow.Progress.BytesTransferred
+	flow.Progress.BytesTransferred = 2048
+	flow.Progress.ExpectedTotal = 4096
+	flow.SourceDataAddress = dsdk.DataAddress{Properties: map[string]any{"endpoint": "https://other.example.com"}}
+	require.NoError(t, store.Save(ctx, flow))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Terminated, found.State)
+	assert.Equal(t, uint(3), found.StateCount)
+	assert.Equal(t, int64(1700000000000), found.StateTimestamp)
+	assert.Equal(t, "terminated", found.ErrorDetail)
+	assert.Equal(t, int64(4096), found.Progress.ExpectedTotal)
+	if _, ok := store.(dsdk.ProgressStore); ok {
+		assert.Equal(t, created, found.Progress.BytesTransferred, "Save must not overwrite counters maintained by UpdateProgress")
+	} else {
+		assert.Equal(t, int64(2048), found.Progress.BytesTransferred)
+	}
+	assert.Equal(t, "https://other.example.com", found.SourceDataAddress.Properties["endpoint"])
+}
+
+func testDelete(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("flow-1")))
+
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+
+	_, err := store.FindById(ctx, "flow-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "flow-1"), dsdk.ErrNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, "unknown"), dsdk.ErrNotFound)
+}
+
+func testReturnsCopies(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ctx := context.Background()
+	flow := NewDataFlow("flow-1")
+	require.NoError(t, store.Create(ctx, flow))
+
+	flow.State = dsdk.Terminated
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	found.State = dsdk.Suspended
+
+	found, err = store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Started, found.State, "changes must only be persisted by Save")
+}
+
+func testRoundTripAddresses(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ctx := context.Background()
+	flow := NewDataFlow("flow-1")
+	flow.SourceDataAddress = dsdk.DataAddress{Properties: map[string]any{
+		"endpoint": "https://provider.example.com/data?page=1&size=10",
+		"auth": map[string]any{
+			"type":  "bearer",
+			"token": "secret",
+		},
+		"regions": []any{"eu", "us"},
+		"enabled": true,
+	}}
+	flow.DestinationDataAddress = dsdk.DataAddress{Properties: map[string]any{}}
+
+	require.NoError(t, store.Create(ctx, flow))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, flow.SourceDataAddress.Properties, found.SourceDataAddress.Properties)
+	assert.Empty(t, found.DestinationDataAddress.Properties)
+	assert.Equal(t, flow.CallbackAddress.URL().String(), found.CallbackAddress.URL().String())
+}
+
+func testConcurrentCreate(t *testing.T, factory Factory) {
+	store, trxContext := factory(t)
+	ctx := context.Background()
+
+	const workers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- trxContext.Execute(ctx, func(ctx context.Context) error {
+				return store.Create(ctx, NewDataFlow("flow-1"))
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, dsdk.ErrConflict)
+	}
+	assert.Equal(t, 1, created, "exactly one concurrent create must succeed")
+}
+
+func testConcurrentSave(t *testing.T, factory Factory) {
+	store, trxContext := factory(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("flow-1")))
+
+	// every worker saves a copy read before any of them has been saved
+	const workers = 10
+	copies := make([]*dsdk.DataFlow, workers)
+	for i := range copies {
+		flow, err := store.FindById(ctx, "flow-1")
+		require.NoError(t, err)
+		copies[i] = flow
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i, flow := range copies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- trxContext.Execute(ctx, func(ctx context.Context) error {
+				flow.ErrorDetail = fmt.Sprintf("worker-%d", i)
+				return store.Save(ctx, flow)
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	saved := 0
+	for err := range errs {
+		if err == nil {
+			saved++
+			continue
+		}
+		assert.ErrorIs(t, err, dsdk.ErrConflict)
+	}
+	assert.Equal(t, 1, saved, "exactly one concurrent save of the same flow must succeed")
+}
+
+func testTransactionCommit(t *testing.T, factory Factory) {
+	store, trxContext := factory(t)
+	ctx := context.Background()
+
+	err := trxContext.Execute(ctx, func(ctx context.Context) error {
+		if err := store.Create(ctx, NewDataFlow("flow-1")); err != nil {
+			return err
+		}
+		_, err := store.FindById(ctx, "flow-1")
+		return err
+	})
+
+	require.NoError(t, err)
+	_, err = store.FindById(ctx, "flow-1")
+	assert.NoError(t, err)
+}
+
+func testTransactionRollback(t *testing.T, factory Factory) {
+	store, trxContext := factory(t)
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("existing")))
+	forced := errors.New("forced error")
+
+	err := trxContext.Execute(ctx, func(ctx context.Context) error {
+		if err := store.Create(ctx, NewDataFlow("flow-1")); err != nil {
+			return err
+		}
+		flow, err := store.FindById(ctx, "existing")
+		if err != nil {
+			return err
+		}
+		flow.State = dsdk.Terminated
+		if err := store.Save(ctx, flow); err != nil {
+			return err
+		}
+		return forced
+	})
+
+	assert.ErrorIs(t, err, forced)
+	_, err = store.FindById(ctx, "flow-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound, "created flow must be rolled back")
+	existing, err := store.FindById(ctx, "existing")
+	require.NoError(t, err)
+	assert.Equal(t, dsdk.Started, existing.State, "saved flow must be rolled back")
+}
+
+// createWithTimestamp creates a flow in the state and sets its state timestamp. Stores may assign the timestamp on
+// create, so it is applied with Save.
+func createWithTimestamp(t *testing.T, store dsdk.DataplaneStore, id string, state dsdk.DataFlowState, timestamp int64) {
+	ctx := context.Background()
+	flow := NewDataFlow(id)
+	flow.State = state
+	require.NoError(t, store.Create(ctx, flow))
+	flow, err := store.FindById(ctx, id)
+	require.NoError(t, err)
+	flow.StateTimestamp = timestamp
+	require.NoError(t, store.Save(ctx, flow))
+}
+
+func query(t *testing.T, store dsdk.DataplaneStore, q dsdk.FlowQuery) []string {
+	iterator, err := store.Query(context.Background(), q)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, iterator.Close())
+	}()
+	ids := []string{}
+	for iterator.Next() {
+		ids = append(ids, iterator.Get().ID)
+	}
+	require.NoError(t, iterator.Error())
+	return ids
+}
+
+func testQueryFilters(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "completed-old", dsdk.Completed, 1000)
+	createWithTimestamp(t, store, "completed-new", dsdk.Completed, 3000)
+	createWithTimestamp(t, store, "terminated-old", dsdk.Terminated, 2000)
+	createWithTimestamp(t, store, "started-old", dsdk.Started, 500)
+
+	assert.ElementsMatch(t, []string{"completed-old", "completed-new"},
+		query(t, store, dsdk.FlowQuery{States: []dsdk.DataFlowState{dsdk.Completed}}))
+	assert.ElementsMatch(t, []string{"completed-old", "terminated-old"}, query(t, store, dsdk.FlowQuery{
+		States:               []dsdk.DataFlowState{dsdk.Completed, dsdk.Terminated},
+		StateTimestampBefore: 3000,
+	}))
+	assert.ElementsMatch(t, []string{"started-old", "completed-old"},
+		query(t, store, dsdk.FlowQuery{StateTimestampBefore: 2000}))
+}
+
+func testQueryRuntime(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "owned", dsdk.Started, 1000)
+	other := NewDataFlow("other")
+	other.RuntimeID = "runtime-2"
+	require.NoError(t, store.Create(context.Background(), other))
+
+	assert.Equal(t, []string{"owned"}, query(t, store, dsdk.FlowQuery{
+		States:    []dsdk.DataFlowState{dsdk.Started},
+		RuntimeID: "runtime-1",
+	}))
+	assert.Equal(t, []string{"other"}, query(t, store, dsdk.FlowQuery{RuntimeID: "runtime-2"}))
+}
+
+func testQueryParticipant(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "tenant-1", dsdk.Started, 1000)
+	other := NewDataFlow("tenant-2")
+	other.ParticipantID = "participant-2"
+	require.NoError(t, store.Create(context.Background(), other))
+
+	assert.Equal(t, []string{"tenant-1"}, query(t, store, dsdk.FlowQuery{
+		States:        []dsdk.DataFlowState{dsdk.Started},
+		ParticipantID: "participant-1",
+	}))
+	assert.Equal(t, []string{"tenant-2"}, query(t, store, dsdk.FlowQuery{ParticipantID: "participant-2"}))
+}
+
+func testQueryCounterPartyAndAgreement(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "a", dsdk.Started, 1000)
+	other := NewDataFlow("b")
+	other.CounterPartyID = "counterparty-2"
+	require.NoError(t, store.Create(context.Background(), other))
+
+	assert.Equal(t, []string{"a"}, query(t, store, dsdk.FlowQuery{CounterPartyID: "counterparty-1"}))
+	assert.Equal(t, []string{"b"}, query(t, store, dsdk.FlowQuery{CounterPartyID: "counterparty-2"}))
+	assert.Equal(t, []string{"b"}, query(t, store, dsdk.FlowQuery{
+		CounterPartyID: "counterparty-2",
+		AgreementID:    "agreement-b",
+	}))
+	assert.Empty(t, query(t, store, dsdk.FlowQuery{CounterPartyID: "counterparty-1", AgreementID: "agreement-b"}))
+}
+
+func testQueryDataset(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "a", dsdk.Started, 1000)
+	other := NewDataFlow("b")
+	other.DatasetID = "dataset-2"
+	require.NoError(t, store.Create(context.Background(), other))
+
+	assert.Equal(t, []string{"a"}, query(t, store, dsdk.FlowQuery{DatasetID: "dataset-1"}))
+	assert.Equal(t, []string{"b"}, query(t, store, dsdk.FlowQuery{DatasetID: "dataset-2"}))
+}
+
+func testQueryOrderAndLimit(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "c", dsdk.Completed, 3000)
+	createWithTimestamp(t, store, "a", dsdk.Completed, 1000)
+	createWithTimestamp(t, store, "b2", dsdk.Completed, 2000)
+	createWithTimestamp(t, store, "b1", dsdk.Completed, 2000)
+
+	states := []dsdk.DataFlowState{dsdk.Completed}
+	assert.Equal(t, []string{"a", "b1", "b2", "c"}, query(t, store, dsdk.FlowQuery{States: states}),
+		"flows must be ordered by state timestamp and ID")
+	assert.Equal(t, []string{"a", "b1"}, query(t, store, dsdk.FlowQuery{States: states, Limit: 2}))
+}
+
+func testQueryEmpty(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	createWithTimestamp(t, store, "flow-1", dsdk.Started, 1000)
+
+	assert.Empty(t, query(t, store, dsdk.FlowQuery{States: []dsdk.DataFlowState{dsdk.Terminated}}))
+}
+
+func testFlowCounter(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	counter, ok := store.(dsdk.FlowCounter)
+	if !ok {
+		t.Skip("store does not implement FlowCounter")
+	}
+	ctx := context.Background()
+	createWithTimestamp(t, store, "started", dsdk.Started, 1000)
+	createWithTimestamp(t, store, "suspended", dsdk.Suspended, 2000)
+	createWithTimestamp(t, store, "completed", dsdk.Completed, 3000)
+	other := NewDataFlow("other")
+	other.CounterPartyID = "counterparty-2"
+	require.NoError(t, store.Create(ctx, other))
+
+	count, err := counter.CountFlows(ctx, dsdk.FlowQuery{
+		States:         []dsdk.DataFlowState{dsdk.Started, dsdk.Suspended},
+		CounterPartyID: "counterparty-1",
+		Limit:          1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "the limit must be ignored")
+	count, err = counter.CountFlows(ctx, dsdk.FlowQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	count, err = counter.CountFlows(ctx, dsdk.FlowQuery{DatasetID: "unknown"})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func testProgressStore(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	progressStore, ok := store.(dsdk.ProgressStore)
+	if !ok {
+		t.Skip("store does not implement ProgressStore")
+	}
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("flow-1")))
+	flow, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+
+	require.NoError(t, progressStore.UpdateProgress(ctx, "flow-1", dsdk.ProgressUpdate{Bytes: 100, Messages: 2}, 1800000000000))
+	require.NoError(t, progressStore.UpdateProgress(ctx, "flow-1", dsdk.ProgressUpdate{Bytes: 50, ExpectedTotal: 8192}, 1800000001000))
+
+	found, err := store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1174), found.Progress.BytesTransferred)
+	assert.Equal(t, int64(5), found.Progress.MessagesTransferred)
+	assert.Equal(t, int64(8192), found.Progress.ExpectedTotal)
+	assert.Equal(t, int64(1800000001000), found.Progress.LastActivity)
+
+	flow.State = dsdk.Suspended
+	require.NoError(t, store.Save(ctx, flow), "progress updates must not conflict with saves of the flow")
+	found, err = store.FindById(ctx, "flow-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1174), found.Progress.BytesTransferred, "Save must not overwrite counters maintained by UpdateProgress")
+
+	err = progressStore.UpdateProgress(ctx, "unknown", dsdk.ProgressUpdate{Bytes: 1}, 1800000000000)
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}
+
+func testMessageLedger(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	ledger, ok := store.(dsdk.MessageLedger)
+	if !ok {
+		t.Skip("store does not implement MessageLedger")
+	}
+	ctx := context.Background()
+	require.NoError(t, store.Create(ctx, NewDataFlow("flow-1")))
+	message := &dsdk.ProcessedMessage{
+		MessageID:   "message-1",
+		ProcessID:   "flow-1",
+		MessageType: "start",
+		PayloadHash: "hash",
+		Response:    []byte(`{"state":"STARTED"}`),
+		CreatedAt:   1800000000000,
+	}
+
+	_, err := ledger.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	require.NoError(t, ledger.SaveMessage(ctx, message))
+	assert.ErrorIs(t, ledger.SaveMessage(ctx, message), dsdk.ErrConflict)
+
+	found, err := ledger.FindMessage(ctx, "message-1")
+	require.NoError(t, err)
+	assert.Equal(t, message, found)
+
+	failed := &dsdk.ProcessedMessage{
+		MessageID:   "message-2",
+		ProcessID:   "flow-1",
+		MessageType: "start",
+		PayloadHash: "hash",
+		Failure:     []byte(`{"status":500}`),
+		CreatedAt:   1800000000000,
+	}
+	require.NoError(t, ledger.SaveMessage(ctx, failed))
+	found, err = ledger.FindMessage(ctx, "message-2")
+	require.NoError(t, err)
+	assert.Equal(t, failed, found)
+
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+	_, err = ledger.FindMessage(ctx, "message-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound, "messages must be deleted with their flow")
+}
+
+func testHistoryStore(t *testing.T, factory Factory) {
+	store, _ := factory(t)
+	historyStore, ok := store.(dsdk.HistoryStore)
+	if !ok {
+		t.Skip("store does not implement HistoryStore")
+	}
+	ctx := context.Background()
+	flow := NewDataFlow("flow-1")
+	flow.State = dsdk.Uninitialized
+	require.NoError(t, flow.TransitionToStarting())
+	require.NoError(t, store.Create(ctx, flow))
+
+	require.NoError(t, flow.TransitionToStarted())
+	require.NoError(t, store.Save(ctx, flow))
+
+	history, err := historyStore.History(ctx, "flow-1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, dsdk.Uninitialized, history[0].From)
+	assert.Equal(t, dsdk.Starting, history[0].To)
+	assert.Equal(t, dsdk.Starting, history[1].From)
+	assert.Equal(t, dsdk.Started, history[1].To)
+
+	_, err = historyStore.History(ctx, "unknown")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+	require.NoError(t, store.Delete(ctx, "flow-1"))
+	_, err = historyStore.History(ctx, "flow-1")
+	assert.ErrorIs(t, err, dsdk.ErrNotFound)
+}